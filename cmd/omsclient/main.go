@@ -3,7 +3,6 @@ package main
 import (
 	"errors"
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/chrisrob11/oms/internal/cmds"
@@ -39,12 +38,32 @@ func main() {
 			cmds.AdjustInvoice,
 			cmds.UpdateCampaign,
 			cmds.UpdateCampaignLineItem,
+			cmds.CloneCampaign,
+			cmds.DeleteCampaign,
+			cmds.Restore,
+			cmds.Validate,
+			cmds.CreateCommissionPlan,
+			cmds.CreateSalesRep,
+			cmds.ListSalesReps,
+			cmds.SetCampaignOwners,
+			cmds.CollectInvoice,
+			cmds.Commissions,
+			cmds.IngestDelivery,
+			cmds.MoveLineItem,
+			cmds.VarianceReport,
+			cmds.UnlockLineItem,
+			cmds.CreateWebhook,
+			cmds.ListWebhooks,
+			cmds.DeleteWebhook,
+			cmds.WebhookDeliveries,
+			cmds.RetryWebhookDelivery,
 		},
 	}
 
 	err := app.Run(os.Args)
 	if err != nil {
-		log.Fatal(err)
+		cmds.PrintError(os.Stderr, err)
+		os.Exit(1)
 	}
 }
 
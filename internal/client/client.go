@@ -12,15 +12,123 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
 	"github.com/pkg/errors"
 )
 
-var errUnexpectedStatusCode = fmt.Errorf("unexpected status code")
+var (
+	// ErrConflict is returned when the service refuses a request because of the current state of a resource.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidRequest is returned when the service rejects the values sent in a request.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrNotFound is returned when the resource of a request does not exist.
+	ErrNotFound = errors.New("not found")
+)
+
+// APIError is an error response of the service, the problem it answered with.
+// Code tells the problems apart, RequestID finds the request in the service logs.
+// It matches ErrConflict, ErrInvalidRequest or ErrNotFound with errors.Is
+// depending on its status.
+type APIError struct {
+	StatusCode int
+	models.Problem
+}
+
+func (e *APIError) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+
+	for _, field := range e.Errors {
+		message += "; " + field.Field + " " + field.Message
+	}
 
-func newErrUnexpectedStatusCode(resp *http.Response) error {
-	return errors.Wrapf(errUnexpectedStatusCode, "status code: %s", resp.Status)
+	for _, result := range e.Results {
+		if result.Status == http.StatusOK {
+			continue
+		}
+
+		message += fmt.Sprintf("; operation %d (%s %d): %s", result.Index, result.Op, result.ID, result.Error)
+		for _, field := range result.Fields {
+			message += ", " + field.Field + " " + field.Message
+		}
+	}
+
+	return fmt.Sprintf("%s (%s)", message, e.Code)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+	case ErrInvalidRequest:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusPreconditionRequired ||
+			e.StatusCode == http.StatusUnsupportedMediaType
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
+// VersionConflictError is returned when a resource was changed by someone else after
+// the version an update or delete was based on. It matches ErrConflict with errors.Is.
+type VersionConflictError struct {
+	*APIError
+	// CurrentVersion is the version the resource has now, fetch it again before retrying.
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return "version conflict: " + e.APIError.Error()
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return e.APIError
+}
+
+// setIfMatch makes the request conditional on the version, zero sends no condition.
+func setIfMatch(req *http.Request, version int) {
+	if version > 0 {
+		req.Header.Set("If-Match", models.ETag(version))
+	}
+}
+
+// newErrFromResponse reads the problem the service answered with. A body that is
+// not a problem, such as the error page of a proxy, keeps only the status.
+func newErrFromResponse(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	if err := decodeBody(resp.Body, &apiErr.Problem); err != nil || apiErr.Code == "" {
+		apiErr.Problem = models.Problem{Status: resp.StatusCode, Title: resp.Status, Code: "unexpected_response"}
+	}
+
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-Id")
+	}
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		conflictErr := &VersionConflictError{APIError: apiErr}
+		conflictErr.CurrentVersion, _ = models.ParseETag(resp.Header.Get("ETag"))
+
+		return conflictErr
+	}
+
+	return apiErr
+}
+
+// decodeBody decodes the /v1 wire form of out from a response body.
+func decodeBody(body io.Reader, out interface{}) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	return v1.Decode(data, out)
 }
 
 // Client represents the HTTP client for the API.
@@ -35,14 +143,19 @@ func NewClient(baseURL string) *Client {
 	return &Client{BaseURL: baseURL, logger: logger}
 }
 
+// url is the address of a /v1 route of the service.
+func (c *Client) url(path string) string {
+	return c.BaseURL + v1.BasePath + path
+}
+
 // createResource sends a POST request to create a new resource.
 func (c *Client) createResource(endpoint string, data interface{}, out interface{}) error {
-	reqBody, err := json.Marshal(data)
+	reqBody, err := json.Marshal(v1.Encode(data))
 	if err != nil {
 		return fmt.Errorf("error encoding JSON: %w", err)
 	}
 
-	resp, err := http.Post(c.BaseURL+endpoint, "application/json", bytes.NewBuffer(reqBody))
+	resp, err := http.Post(c.url(endpoint), "application/json", bytes.NewBuffer(reqBody))
 	if err != nil {
 		return fmt.Errorf("error making HTTP request: %w", err)
 	}
@@ -55,7 +168,7 @@ func (c *Client) createResource(endpoint string, data interface{}, out interface
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return newErrUnexpectedStatusCode(resp)
+		return newErrFromResponse(resp)
 	}
 
 	outData, err := io.ReadAll(resp.Body)
@@ -63,7 +176,7 @@ func (c *Client) createResource(endpoint string, data interface{}, out interface
 		return errors.Wrap(err, "cannot read body")
 	}
 
-	err = json.Unmarshal(outData, out)
+	err = v1.Decode(outData, out)
 	if err != nil {
 		return errors.Wrap(err, "cannot unmarshal body to output value")
 	}
@@ -76,7 +189,7 @@ func (c *Client) executeAction(endpoint string, id int, action string, data inte
 	var dataReader io.Reader
 
 	if data != nil {
-		reqBody, err := json.Marshal(data)
+		reqBody, err := json.Marshal(v1.Encode(data))
 		if err != nil {
 			return fmt.Errorf("error encoding JSON: %w", err)
 		}
@@ -84,7 +197,7 @@ func (c *Client) executeAction(endpoint string, id int, action string, data inte
 		dataReader = bytes.NewBuffer(reqBody)
 	}
 
-	resp, err := http.Post(c.BaseURL+endpoint+"/"+strconv.Itoa(id)+"/"+action, "application/json", dataReader)
+	resp, err := http.Post(c.url(endpoint+"/"+strconv.Itoa(id)+"/"+action), "application/json", dataReader)
 	if err != nil {
 		return fmt.Errorf("error making HTTP request: %w", err)
 	}
@@ -97,7 +210,7 @@ func (c *Client) executeAction(endpoint string, id int, action string, data inte
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return newErrUnexpectedStatusCode(resp)
+		return newErrFromResponse(resp)
 	}
 
 	outData, err := io.ReadAll(resp.Body)
@@ -105,7 +218,7 @@ func (c *Client) executeAction(endpoint string, id int, action string, data inte
 		return errors.Wrap(err, "cannot read body")
 	}
 
-	err = json.Unmarshal(outData, out)
+	err = v1.Decode(outData, out)
 	if err != nil {
 		return errors.Wrap(err, "cannot unmarshal body to output value")
 	}
@@ -113,19 +226,62 @@ func (c *Client) executeAction(endpoint string, id int, action string, data inte
 	return nil
 }
 
-// update sends a POST request to do an action on a resource.
-func (c *Client) update(endpoint string, id int, in interface{}) error {
-	reqBody, err := json.Marshal(in)
+// update sends a PUT request to replace a resource that is still at the version.
+func (c *Client) update(endpoint string, id int, version int, in interface{}) error {
+	return c.put(endpoint+"/"+strconv.Itoa(id), version, in)
+}
+
+// put sends a PUT request with the JSON encoded input to the path.
+func (c *Client) put(path string, version int, in interface{}) error {
+	reqBody, err := json.Marshal(v1.Encode(in))
 	if err != nil {
 		return fmt.Errorf("error encoding JSON: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", c.BaseURL+endpoint+"/"+strconv.Itoa(id), bytes.NewBuffer(reqBody))
+	req, err := http.NewRequest("PUT", c.url(path), bytes.NewBuffer(reqBody))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	setIfMatch(req, version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making HTTP request: %w", err)
+	}
+
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			c.logger.Warn("Error closing body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return newErrFromResponse(resp)
+	}
+
+	return nil
+}
+
+// patch sends a JSON merge patch for a resource that is still at the version and
+// decodes the patched resource into out. The patch names the fields like the models
+// do, they are sent by their keys on the wire.
+func (c *Client) patch(endpoint string, names *v1.WireNames, id int, version int, patch map[string]interface{},
+	out interface{}) error {
+	reqBody, err := json.Marshal(names.PatchToWire(patch))
+	if err != nil {
+		return fmt.Errorf("error encoding JSON: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, c.url(endpoint+"/"+strconv.Itoa(id)), bytes.NewBuffer(reqBody))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	setIfMatch(req, version)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -140,15 +296,56 @@ func (c *Client) update(endpoint string, id int, in interface{}) error {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return newErrUnexpectedStatusCode(resp)
+		return newErrFromResponse(resp)
+	}
+
+	if err := decodeBody(resp.Body, out); err != nil {
+		return fmt.Errorf("error decoding JSON response: %w", err)
+	}
+
+	return nil
+}
+
+// deleteResource sends a DELETE request to remove a resource that is still at the version.
+func (c *Client) deleteResource(endpoint string, id int, version int, queryValues url.Values) error {
+	query := c.url(endpoint + "/" + strconv.Itoa(id))
+	if len(queryValues) > 0 {
+		query = query + "?" + queryValues.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, query, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	setIfMatch(req, version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making HTTP request: %w", err)
+	}
+
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			c.logger.Warn("Error closing body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return newErrFromResponse(resp)
 	}
 
 	return nil
 }
 
 // listResources sends a Get request to list resources.
-func (c *Client) listResources(endpoint string, token *string, limit *int, out interface{}) error {
-	queryValues := url.Values{}
+func (c *Client) listResources(endpoint string, queryValues url.Values, token *string, limit *int,
+	out interface{}) error {
+	if queryValues == nil {
+		queryValues = url.Values{}
+	}
+
 	if token != nil {
 		queryValues.Add("$token", *token)
 	}
@@ -157,7 +354,7 @@ func (c *Client) listResources(endpoint string, token *string, limit *int, out i
 		queryValues.Add("$limit", strconv.Itoa(*limit))
 	}
 
-	query := c.BaseURL + endpoint
+	query := c.url(endpoint)
 	if len(queryValues) > 0 {
 		query = query + "?" + queryValues.Encode()
 	}
@@ -176,7 +373,7 @@ func (c *Client) listResources(endpoint string, token *string, limit *int, out i
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return newErrUnexpectedStatusCode(resp)
+		return newErrFromResponse(resp)
 	}
 
 	outData, err := io.ReadAll(resp.Body)
@@ -184,7 +381,7 @@ func (c *Client) listResources(endpoint string, token *string, limit *int, out i
 		return errors.Wrap(err, "cannot read body")
 	}
 
-	err = json.Unmarshal(outData, out)
+	err = v1.Decode(outData, out)
 	if err != nil {
 		return errors.Wrap(err, "cannot unmarshal body to output value")
 	}
@@ -192,9 +389,19 @@ func (c *Client) listResources(endpoint string, token *string, limit *int, out i
 	return nil
 }
 
-// listResources sends a Get request to list resources.
+// showResources sends a Get request to get a single resource.
 func (c *Client) showResources(endpoint string, id int, out interface{}) error {
-	resp, err := http.Get(c.BaseURL + endpoint + "/" + strconv.Itoa(id))
+	return c.getResource(endpoint+"/"+strconv.Itoa(id), out)
+}
+
+// showSubResource sends a Get request to get a resource nested under another resource.
+func (c *Client) showSubResource(endpoint string, id int, subResource string, out interface{}) error {
+	return c.getResource(endpoint+"/"+strconv.Itoa(id)+"/"+subResource, out)
+}
+
+// getResource sends a Get request to the path and decodes the response into out.
+func (c *Client) getResource(path string, out interface{}) error {
+	resp, err := http.Get(c.url(path))
 	if err != nil {
 		return fmt.Errorf("error making HTTP request: %w", err)
 	}
@@ -207,7 +414,7 @@ func (c *Client) showResources(endpoint string, id int, out interface{}) error {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return newErrUnexpectedStatusCode(resp)
+		return newErrFromResponse(resp)
 	}
 
 	outData, err := io.ReadAll(resp.Body)
@@ -215,7 +422,7 @@ func (c *Client) showResources(endpoint string, id int, out interface{}) error {
 		return errors.Wrap(err, "cannot read body")
 	}
 
-	err = json.Unmarshal(outData, out)
+	err = v1.Decode(outData, out)
 	if err != nil {
 		return errors.Wrap(err, "cannot unmarshal body to output value")
 	}
@@ -248,19 +455,36 @@ func (c *Client) CreateCampaignOrderLine(orderLine *models.CampaignLineItem) (in
 }
 
 type ListCampaignRequest struct {
-	Size  int
-	Token *string
+	Size           int
+	Token          *string
+	IncludeDeleted bool
+	// Filter and OrderBy are sent as $filter and $orderby, the token remembers them for later pages.
+	Filter  string
+	OrderBy string
+	// Count asks for the TotalCount of the list, which costs an extra query.
+	Count bool
+	// Select and Expand are sent as $select and $expand, see ShowCampaignRequest.
+	Select []string
+	Expand []string
 }
 
 // ListCampaigns sends a Get request to get a list of campaigns.
 func (c *Client) ListCampaigns(req *ListCampaignRequest) (*models.List[models.Campaign], error) {
-	if req.Size == 0 {
-		req.Size = 100
+	items := &models.List[models.Campaign]{}
+
+	err := c.listCampaigns(req, items)
+	if err != nil {
+		return nil, err
 	}
 
-	items := &models.List[models.Campaign]{}
+	return items, nil
+}
+
+// ListExpandedCampaigns lists campaigns together with the relations named in req.Expand.
+func (c *Client) ListExpandedCampaigns(req *ListCampaignRequest) (*models.List[models.ExpandedCampaign], error) {
+	items := &models.List[models.ExpandedCampaign]{}
 
-	err := c.listResources("/campaigns", req.Token, &req.Size, items)
+	err := c.listCampaigns(req, items)
 	if err != nil {
 		return nil, err
 	}
@@ -268,9 +492,26 @@ func (c *Client) ListCampaigns(req *ListCampaignRequest) (*models.List[models.Ca
 	return items, nil
 }
 
+func (c *Client) listCampaigns(req *ListCampaignRequest, out interface{}) error {
+	if req.Size == 0 {
+		req.Size = 100
+	}
+
+	queryValues := listQuery(req.IncludeDeleted, req.Count, req.Filter, req.OrderBy)
+	addShapeQuery(queryValues, req.Select, req.Expand)
+
+	return c.listResources("/campaigns", queryValues, req.Token, &req.Size, out)
+}
+
 type ListCampaignLineItemRequest struct {
-	Size  int
-	Token *string
+	Size           int
+	Token          *string
+	IncludeDeleted bool
+	// CampaignID limits the list to the line items of a single campaign when set.
+	CampaignID int
+	Filter     string
+	OrderBy    string
+	Count      bool
 }
 
 func (c *Client) ListCampaignLineItems(
@@ -279,9 +520,16 @@ func (c *Client) ListCampaignLineItems(
 		req.Size = 100
 	}
 
+	endpoint := "/campaignLineItems"
+	if req.CampaignID != 0 {
+		endpoint = "/campaigns/" + strconv.Itoa(req.CampaignID) + "/lineItems"
+	}
+
 	items := &models.List[models.CampaignLineItem]{}
 
-	err := c.listResources("/campaignLineItems", req.Token, &req.Size, items)
+	queryValues := listQuery(req.IncludeDeleted, req.Count, req.Filter, req.OrderBy)
+
+	err := c.listResources(endpoint, queryValues, req.Token, &req.Size, items)
 	if err != nil {
 		return nil, err
 	}
@@ -291,13 +539,36 @@ func (c *Client) ListCampaignLineItems(
 
 type ShowCampaignRequest struct {
 	ID int
+	// Select limits the response to these campaign fields, the ID is always returned.
+	Select []string
+	// Expand embeds related resources, "line_items" and "invoices" are supported.
+	Expand []string
 }
 
 // ShowCampaign sends a Get request to get a specific campaigns.
 func (c *Client) ShowCampaign(req *ShowCampaignRequest) (*models.Campaign, error) {
-	campaign := models.Campaign{}
+	campaign, err := c.ShowExpandedCampaign(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaign.Campaign, nil
+}
+
+// ShowExpandedCampaign gets a campaign together with the relations named in req.Expand.
+func (c *Client) ShowExpandedCampaign(req *ShowCampaignRequest) (*models.ExpandedCampaign, error) {
+	campaign := models.ExpandedCampaign{}
 
-	err := c.showResources("/campaigns", req.ID, &campaign)
+	path := "/campaigns/" + strconv.Itoa(req.ID)
+
+	queryValues := url.Values{}
+	addShapeQuery(queryValues, req.Select, req.Expand)
+
+	if len(queryValues) > 0 {
+		path += "?" + queryValues.Encode()
+	}
+
+	err := c.getResource(path, &campaign)
 	if err != nil {
 		return nil, err
 	}
@@ -305,6 +576,29 @@ func (c *Client) ShowCampaign(req *ShowCampaignRequest) (*models.Campaign, error
 	return &campaign, nil
 }
 
+// addShapeQuery adds the $select and $expand options of a campaign request.
+func addShapeQuery(queryValues url.Values, selectFields []string, expand []string) {
+	if len(selectFields) > 0 {
+		queryValues.Add("$select", strings.Join(selectFields, ","))
+	}
+
+	if len(expand) > 0 {
+		queryValues.Add("$expand", strings.Join(expand, ","))
+	}
+}
+
+// ShowCampaignSummary sends a Get request to get the aggregated financials of a campaign.
+func (c *Client) ShowCampaignSummary(req *ShowCampaignRequest) (*models.CampaignSummary, error) {
+	summary := models.CampaignSummary{}
+
+	err := c.showSubResource("/campaigns", req.ID, "summary", &summary)
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
 type ShowCampaignOrderLineRequest struct {
 	ID int
 }
@@ -369,8 +663,12 @@ func (c *Client) GenerateInvoiceFromCampaign(req *GenerateInvoiceFromCampaignReq
 }
 
 type ListInvoicesRequest struct {
-	Size  int
-	Token *string
+	Size           int
+	Token          *string
+	IncludeDeleted bool
+	Filter         string
+	OrderBy        string
+	Count          bool
 }
 
 func (c *Client) ListInvoices(req *ListInvoicesRequest) (*models.List[models.Invoice], error) {
@@ -380,7 +678,9 @@ func (c *Client) ListInvoices(req *ListInvoicesRequest) (*models.List[models.Inv
 
 	items := &models.List[models.Invoice]{}
 
-	err := c.listResources("/invoices", req.Token, &req.Size, items)
+	queryValues := listQuery(req.IncludeDeleted, req.Count, req.Filter, req.OrderBy)
+
+	err := c.listResources("/invoices", queryValues, req.Token, &req.Size, items)
 	if err != nil {
 		return nil, err
 	}
@@ -389,7 +689,7 @@ func (c *Client) ListInvoices(req *ListInvoicesRequest) (*models.List[models.Inv
 }
 
 func (c *Client) UpdateCampaign(campaign models.Campaign) error {
-	err := c.update("/campaigns", campaign.ID, &campaign)
+	err := c.update("/campaigns", campaign.ID, campaign.Version, &campaign)
 	if err != nil {
 		return err
 	}
@@ -398,10 +698,458 @@ func (c *Client) UpdateCampaign(campaign models.Campaign) error {
 }
 
 func (c *Client) UpdateCampaignLineItem(campaignLineItem models.CampaignLineItem) error {
-	err := c.update("/campaignLineItems", campaignLineItem.ID, &campaignLineItem)
+	err := c.update("/campaignLineItems", campaignLineItem.ID, campaignLineItem.Version, &campaignLineItem)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// PatchCampaign changes only the fields in the merge patch, a nil value clears the field.
+func (c *Client) PatchCampaign(id int, version int, patch map[string]interface{}) (*models.Campaign, error) {
+	campaign := &models.Campaign{}
+	if err := c.patch("/campaigns", v1.CampaignNames, id, version, patch, campaign); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// PatchCampaignLineItem changes only the fields in the merge patch, a nil value clears the field.
+func (c *Client) PatchCampaignLineItem(id int, version int,
+	patch map[string]interface{}) (*models.CampaignLineItem, error) {
+	campaignLineItem := &models.CampaignLineItem{}
+	if err := c.patch("/campaignLineItems", v1.CampaignLineItemNames, id, version, patch, campaignLineItem); err != nil {
+		return nil, err
+	}
+
+	return campaignLineItem, nil
+}
+
+// PatchInvoice changes only the fields in the merge patch, a nil value clears the field.
+func (c *Client) PatchInvoice(id int, version int, patch map[string]interface{}) (*models.Invoice, error) {
+	invoice := &models.Invoice{}
+	if err := c.patch("/invoices", v1.InvoiceNames, id, version, patch, invoice); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+type CloneCampaignRequest struct {
+	ID           int
+	Name         string
+	OffsetDays   int
+	ResetActuals bool
+}
+
+// CloneCampaign copies a campaign and its line items, returning the new campaign id.
+func (c *Client) CloneCampaign(req *CloneCampaignRequest) (int, error) {
+	outCampaign := models.Campaign{}
+
+	body := &models.CloneCampaignRequest{
+		Name:         req.Name,
+		OffsetDays:   req.OffsetDays,
+		ResetActuals: req.ResetActuals,
+	}
+
+	err := c.executeAction("/campaigns", req.ID, "clone", body, &outCampaign)
+	if err != nil {
+		return 0, err
+	}
+
+	return outCampaign.ID, nil
+}
+
+type DeleteCampaignRequest struct {
+	ID int
+	// Version is the version of the campaign the deletion was decided on.
+	Version int
+	// Cascade removes the line items and draft invoices of the campaign as well.
+	Cascade bool
+}
+
+// DeleteCampaign sends a DELETE request to remove a campaign.
+func (c *Client) DeleteCampaign(req *DeleteCampaignRequest) error {
+	queryValues := url.Values{}
+	if req.Cascade {
+		queryValues.Add("mode", "cascade")
+	}
+
+	return c.deleteResource("/campaigns", req.ID, req.Version, queryValues)
+}
+
+func includeDeletedQuery(includeDeleted bool) url.Values {
+	queryValues := url.Values{}
+	if includeDeleted {
+		queryValues.Add("includeDeleted", "true")
+	}
+
+	return queryValues
+}
+
+// listQuery adds the $filter and $orderby options of a list request when they are set.
+func listQuery(includeDeleted, count bool, filter, orderBy string) url.Values {
+	queryValues := includeDeletedQuery(includeDeleted)
+
+	if count {
+		queryValues.Add("$count", "true")
+	}
+
+	if filter != "" {
+		queryValues.Add("$filter", filter)
+	}
+
+	if orderBy != "" {
+		queryValues.Add("$orderby", orderBy)
+	}
+
+	return queryValues
+}
+
+type RestoreRequest struct {
+	ID int
+}
+
+// RestoreCampaign takes a campaign, and everything trashed with it, out of the trash.
+func (c *Client) RestoreCampaign(req *RestoreRequest) error {
+	var outID int
+
+	return c.executeAction("/campaigns", req.ID, "restore", nil, &outID)
+}
+
+// RestoreCampaignLineItem takes a campaign line item out of the trash.
+func (c *Client) RestoreCampaignLineItem(req *RestoreRequest) error {
+	var outID int
+
+	return c.executeAction("/campaignLineItems", req.ID, "restore", nil, &outID)
+}
+
+// RestoreInvoice takes an invoice out of the trash.
+func (c *Client) RestoreInvoice(req *RestoreRequest) error {
+	var outID int
+
+	return c.executeAction("/invoices", req.ID, "restore", nil, &outID)
+}
+
+// CreateCommissionPlan sends a POST request to create a new commission plan.
+func (c *Client) CreateCommissionPlan(plan *models.CommissionPlan) (int, error) {
+	outPlan := models.CommissionPlan{}
+
+	err := c.createResource("/commissionPlans", plan, &outPlan)
+	if err != nil {
+		return 0, err
+	}
+
+	return outPlan.ID, nil
+}
+
+// ListCommissionPlans sends a Get request to get all commission plans.
+func (c *Client) ListCommissionPlans() (*models.List[models.CommissionPlan], error) {
+	items := &models.List[models.CommissionPlan]{}
+
+	err := c.getResource("/commissionPlans", items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CreateSalesRep sends a POST request to create a new sales rep.
+func (c *Client) CreateSalesRep(rep *models.SalesRep) (int, error) {
+	outRep := models.SalesRep{}
+
+	err := c.createResource("/salesReps", rep, &outRep)
+	if err != nil {
+		return 0, err
+	}
+
+	return outRep.ID, nil
+}
+
+// ListSalesReps sends a Get request to get all sales reps.
+func (c *Client) ListSalesReps() (*models.List[models.SalesRep], error) {
+	items := &models.List[models.SalesRep]{}
+
+	err := c.getResource("/salesReps", items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetCampaignOwnersRequest replaces the owners while the campaign is at Version,
+// the owners are versioned with their campaign.
+type SetCampaignOwnersRequest struct {
+	CampaignID int
+	Version    int
+	Owners     []*models.CampaignOwner
+}
+
+// SetCampaignOwners replaces the sales reps owning a campaign.
+func (c *Client) SetCampaignOwners(req *SetCampaignOwnersRequest) error {
+	owners := req.Owners
+	if owners == nil {
+		owners = []*models.CampaignOwner{}
+	}
+
+	return c.put("/campaigns/"+strconv.Itoa(req.CampaignID)+"/owners", req.Version, owners)
+}
+
+// ListCampaignOwners sends a Get request to get the sales reps owning a campaign.
+func (c *Client) ListCampaignOwners(req *ShowCampaignRequest) (*models.List[models.CampaignOwner], error) {
+	items := &models.List[models.CampaignOwner]{}
+
+	err := c.showSubResource("/campaigns", req.ID, "owners", items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+type CollectInvoiceRequest struct {
+	ID int
+}
+
+// CollectInvoice records that the payment of an invoice was received.
+func (c *Client) CollectInvoice(req *CollectInvoiceRequest) error {
+	var outID int
+
+	return c.executeAction("/invoices", req.ID, "collect", nil, &outID)
+}
+
+type CommissionReportRequest struct {
+	// Period is a year (2024), a quarter (2024-Q1) or a month (2024-03).
+	Period string
+}
+
+// CommissionReport sends a Get request to get the commissions earned in a period.
+func (c *Client) CommissionReport(req *CommissionReportRequest) (*models.CommissionReport, error) {
+	report := models.CommissionReport{}
+
+	err := c.getResource("/reports/commissions?"+url.Values{"period": {req.Period}}.Encode(), &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// IngestDelivery sends daily delivery records, days that were sent before are replaced.
+func (c *Client) IngestDelivery(records []*models.LineItemDelivery) (*models.DeliveryIngestResult, error) {
+	result := models.DeliveryIngestResult{}
+
+	err := c.createResource("/lineItemDelivery", records, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ListLineItemDelivery sends a Get request to get the daily delivery of a line item.
+func (c *Client) ListLineItemDelivery(req *ShowCampaignOrderLineRequest) (*models.List[models.LineItemDelivery], error) {
+	items := &models.List[models.LineItemDelivery]{}
+
+	err := c.showSubResource("/campaignLineItems", req.ID, "delivery", items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// BatchCampaignLineItems applies the operations in a single transaction, when any
+// operation is rejected nothing is applied and the results explain why.
+func (c *Client) BatchCampaignLineItems(
+	operations []*models.CampaignLineItemOperation) (*models.CampaignLineItemBatchResult, error) {
+	reqBody, err := json.Marshal(v1.Encode(operations))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding JSON: %w", err)
+	}
+
+	resp, err := http.Post(c.url("/campaignLineItems/batch"), "application/json", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request: %w", err)
+	}
+
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			c.logger.Warn("Error closing body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusOK {
+		result := &models.CampaignLineItemBatchResult{}
+		if err := decodeBody(resp.Body, result); err != nil {
+			return nil, errors.Wrap(err, "cannot unmarshal body to output value")
+		}
+
+		return result, nil
+	}
+
+	err = newErrFromResponse(resp)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Results != nil {
+		return &models.CampaignLineItemBatchResult{Results: apiErr.Results}, err
+	}
+
+	return nil, err
+}
+
+type MoveCampaignLineItemRequest struct {
+	ID         int
+	CampaignID int
+	Reason     string
+}
+
+// MoveCampaignLineItem moves a line item, keeping its id, to another campaign.
+func (c *Client) MoveCampaignLineItem(req *MoveCampaignLineItemRequest) error {
+	var outID int
+
+	body := &models.MoveCampaignLineItemRequest{CampaignID: req.CampaignID, Reason: req.Reason}
+
+	return c.executeAction("/campaignLineItems", req.ID, "move", body, &outID)
+}
+
+// ListCampaignLineItemHistory sends a Get request to get the recorded changes of a line item.
+func (c *Client) ListCampaignLineItemHistory(
+	req *ShowCampaignOrderLineRequest) (*models.List[models.LineItemHistoryEntry], error) {
+	items := &models.List[models.LineItemHistoryEntry]{}
+
+	err := c.showSubResource("/campaignLineItems", req.ID, "history", items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+type VarianceReportRequest struct {
+	// Threshold is an amount (100) or a percentage of booked (5%).
+	Threshold string
+	// GroupBy is lineItem or campaign.
+	GroupBy string
+	// Sort is absolute or percent.
+	Sort string
+	// Status limits the report to under or over delivery.
+	Status string
+}
+
+// VarianceReport sends a Get request to get what under or over delivered against booked.
+func (c *Client) VarianceReport(req *VarianceReportRequest) (*models.VarianceReport, error) {
+	queryValues := url.Values{}
+
+	for name, value := range map[string]string{
+		"threshold": req.Threshold,
+		"groupBy":   req.GroupBy,
+		"sort":      req.Sort,
+		"status":    req.Status,
+	} {
+		if value != "" {
+			queryValues.Add(name, value)
+		}
+	}
+
+	report := models.VarianceReport{}
+
+	err := c.getResource("/reports/variance?"+queryValues.Encode(), &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+type UnlockCampaignLineItemRequest struct {
+	ID     int
+	Reason string
+}
+
+// UnlockCampaignLineItem lets a line item billed by an issued invoice change again.
+func (c *Client) UnlockCampaignLineItem(req *UnlockCampaignLineItemRequest) error {
+	var outID int
+
+	body := &models.UnlockCampaignLineItemRequest{Reason: req.Reason}
+
+	return c.executeAction("/campaignLineItems", req.ID, "unlock", body, &outID)
+}
+
+// CreateWebhook sends a POST request to subscribe a URL to events. The returned
+// subscription holds the secret the deliveries are signed with, it is not shown again.
+func (c *Client) CreateWebhook(subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	outSubscription := models.WebhookSubscription{}
+
+	err := c.createResource("/webhooks", subscription, &outSubscription)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outSubscription, nil
+}
+
+// ListWebhooks sends a Get request to get all webhook subscriptions.
+func (c *Client) ListWebhooks() (*models.List[models.WebhookSubscription], error) {
+	items := &models.List[models.WebhookSubscription]{}
+
+	err := c.getResource("/webhooks", items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// UpdateWebhook replaces the URL and event types of a webhook subscription while
+// it is at subscription.Version.
+func (c *Client) UpdateWebhook(subscription *models.WebhookSubscription) error {
+	return c.put("/webhooks/"+strconv.Itoa(subscription.ID), subscription.Version, subscription)
+}
+
+// DeleteWebhook sends a DELETE request to remove a webhook subscription and its
+// deliveries while the subscription is at version.
+func (c *Client) DeleteWebhook(id, version int) error {
+	return c.deleteResource("/webhooks", id, version, nil)
+}
+
+type ListWebhookDeliveriesRequest struct {
+	// SubscriptionID limits the deliveries to one subscription when it is set.
+	SubscriptionID int
+	// Status is pending, delivered or dead, dead lists the dead letters.
+	Status string
+}
+
+// ListWebhookDeliveries sends a Get request to get the newest webhook deliveries.
+func (c *Client) ListWebhookDeliveries(
+	req *ListWebhookDeliveriesRequest) (*models.List[models.WebhookDelivery], error) {
+	path := "/webhookDeliveries"
+	if req.SubscriptionID > 0 {
+		path = "/webhooks/" + strconv.Itoa(req.SubscriptionID) + "/deliveries"
+	}
+
+	if req.Status != "" {
+		path += "?" + url.Values{"status": {req.Status}}.Encode()
+	}
+
+	items := &models.List[models.WebhookDelivery]{}
+
+	err := c.getResource(path, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// RetryWebhookDelivery sends a dead webhook delivery again.
+func (c *Client) RetryWebhookDelivery(id int) error {
+	var outID int
+
+	return c.executeAction("/webhookDeliveries", id, "retry", nil, &outID)
+}
@@ -0,0 +1,73 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var CloneCampaign = &cli.Command{
+	Name:    "clone-campaign",
+	Aliases: []string{"clc"},
+	Usage:   "Copy a campaign and all of its line items",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newCloneCampaignCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the campaign to clone",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Name of the new campaign, defaults to the source campaign name",
+		},
+		&cli.IntFlag{
+			Name:  "offsetDays",
+			Usage: "Number of days to shift the flight dates by",
+		},
+		&cli.BoolFlag{
+			Name:  "resetActuals",
+			Usage: "Zero the actual and adjustment amounts on the copied line items",
+		},
+	},
+}
+
+type cloneCampaignCommand struct {
+	serviceURL string
+}
+
+func newCloneCampaignCommand(serviceURL string) *cloneCampaignCommand {
+	return &cloneCampaignCommand{serviceURL: serviceURL}
+}
+
+func (i *cloneCampaignCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	newID, err := omsClient.CloneCampaign(&client.CloneCampaignRequest{
+		ID:           id,
+		Name:         c.String("name"),
+		OffsetDays:   c.Int("offsetDays"),
+		ResetActuals: c.Bool("resetActuals"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot clone campaign")
+	}
+
+	fmt.Printf("Campaign %d was cloned to campaign %d\n", id, newID)
+
+	return nil
+}
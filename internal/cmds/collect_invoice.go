@@ -0,0 +1,55 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var CollectInvoice = &cli.Command{
+	Name:    "collect-invoice",
+	Aliases: []string{"coi"},
+	Usage:   "Record that the payment of an invoice was received",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newCollectInvoiceCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the invoice",
+		},
+	},
+}
+
+type collectInvoiceCommand struct {
+	serviceURL string
+}
+
+func newCollectInvoiceCommand(serviceURL string) *collectInvoiceCommand {
+	return &collectInvoiceCommand{serviceURL: serviceURL}
+}
+
+func (i *collectInvoiceCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	if err := omsClient.CollectInvoice(&client.CollectInvoiceRequest{ID: id}); err != nil {
+		return errors.Wrap(err, "Cannot collect invoice")
+	}
+
+	fmt.Printf("Invoice %d was collected\n", id)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var Commissions = &cli.Command{
+	Name:  "commissions",
+	Usage: "Report the commission earned by each sales rep in a period",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newCommissionsCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "period",
+			Usage: "Year (2024), quarter (2024-Q1) or month (2024-03) to report on",
+		},
+	},
+}
+
+type commissionsCommand struct {
+	serviceURL string
+}
+
+func newCommissionsCommand(serviceURL string) *commissionsCommand {
+	return &commissionsCommand{serviceURL: serviceURL}
+}
+
+func (i *commissionsCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	period := c.String("period")
+	if period == "" {
+		return NewMissingError("period")
+	}
+
+	report, err := omsClient.CommissionReport(&client.CommissionReportRequest{Period: period})
+	if err != nil {
+		return errors.Wrap(err, "Cannot get commission report")
+	}
+
+	fmt.Printf("Commissions %s (%s to %s)\n\n", report.Period,
+		toCompactTime(&report.PeriodStart), toCompactTime(&report.PeriodEnd))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SalesRepID\tSalesRep\tPlan\tBasis\tRate\tInvoices\tRevenue\tCommission\n")
+
+	for _, line := range report.Lines {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.2f%%\t%d\t%f\t%f\n", line.SalesRepID, line.SalesRepName,
+			line.CommissionPlanName, line.Basis, line.RatePercent, line.InvoiceCount, line.Revenue, line.Commission)
+	}
+
+	fmt.Fprintf(w, "\t\t\t\t\t\t%f\t%f\n", report.TotalRevenue, report.TotalCommission)
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
+
+	return nil
+}
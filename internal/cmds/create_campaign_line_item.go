@@ -38,6 +38,20 @@ var CreateCampaignLineItem = &cli.Command{
 		&cli.IntFlag{
 			Name: "campaignId",
 		},
+		&cli.StringFlag{
+			Name:  "pricingModel",
+			Usage: "cpm, cpc, cpa or flat, booked and actual are computed from the units unless flat",
+		},
+		&cli.Float64Flag{
+			Name:  "unitRate",
+			Usage: "Price per thousand impressions for cpm, per click for cpc or per action for cpa",
+		},
+		&cli.Int64Flag{
+			Name: "bookedUnits",
+		},
+		&cli.Int64Flag{
+			Name: "deliveredUnits",
+		},
 	},
 }
 
@@ -56,6 +70,10 @@ func (i *createCampaignLineItemCommand) Run(c *cli.Context) error {
 	campaignLineItem.Booked = c.Float64("booked")
 	campaignLineItem.ID = c.Int("id")
 	campaignLineItem.CampaignID = c.Int("campaignId")
+	campaignLineItem.PricingModel = c.String("pricingModel")
+	campaignLineItem.UnitRate = c.Float64("unitRate")
+	campaignLineItem.BookedUnits = c.Int64("bookedUnits")
+	campaignLineItem.DeliveredUnits = c.Int64("deliveredUnits")
 
 	omsClient := client.NewClient(i.serviceURL)
 
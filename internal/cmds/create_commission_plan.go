@@ -0,0 +1,65 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var CreateCommissionPlan = &cli.Command{
+	Name:    "create-commission-plan",
+	Aliases: []string{"ccp"},
+	Usage:   "Create a commission plan for sales reps",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newCreateCommissionPlanCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Name of the commission plan",
+		},
+		&cli.StringFlag{
+			Name:  "basis",
+			Value: models.CommissionBasisBilled,
+			Usage: "Revenue the commission is paid on, billed or collected",
+		},
+		&cli.Float64Flag{
+			Name:  "rate",
+			Usage: "Commission rate as a percentage of the revenue",
+		},
+	},
+}
+
+type createCommissionPlanCommand struct {
+	serviceURL string
+}
+
+func newCreateCommissionPlanCommand(serviceURL string) *createCommissionPlanCommand {
+	return &createCommissionPlanCommand{serviceURL: serviceURL}
+}
+
+func (i *createCommissionPlanCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id, err := omsClient.CreateCommissionPlan(&models.CommissionPlan{
+		Name:        c.String("name"),
+		Basis:       c.String("basis"),
+		RatePercent: c.Float64("rate"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot create commission plan")
+	}
+
+	fmt.Printf("Commission plan %d was created\n", id)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var CreateSalesRep = &cli.Command{
+	Name:    "create-sales-rep",
+	Aliases: []string{"csr"},
+	Usage:   "Create a sales rep that can own campaigns",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newCreateSalesRepCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name: "name",
+		},
+		&cli.StringFlag{
+			Name: "email",
+		},
+		&cli.IntFlag{
+			Name:  "commissionPlanId",
+			Usage: "Id of the commission plan of the sales rep",
+		},
+	},
+}
+
+type createSalesRepCommand struct {
+	serviceURL string
+}
+
+func newCreateSalesRepCommand(serviceURL string) *createSalesRepCommand {
+	return &createSalesRepCommand{serviceURL: serviceURL}
+}
+
+func (i *createSalesRepCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	rep := &models.SalesRep{Name: c.String("name"), Email: c.String("email")}
+
+	if c.IsSet("commissionPlanId") {
+		planID := c.Int("commissionPlanId")
+		rep.CommissionPlanID = &planID
+	}
+
+	id, err := omsClient.CreateSalesRep(rep)
+	if err != nil {
+		return errors.Wrap(err, "Cannot create sales rep")
+	}
+
+	fmt.Printf("Sales rep %d was created\n", id)
+
+	return nil
+}
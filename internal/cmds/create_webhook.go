@@ -0,0 +1,66 @@
+package cmds
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var CreateWebhook = &cli.Command{
+	Name:    "create-webhook",
+	Aliases: []string{"cw"},
+	Usage:   "Subscribe a URL to campaign, line item and invoice events",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newCreateWebhookCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "target",
+			Usage: "URL the events are posted to",
+		},
+		&cli.StringSliceFlag{
+			Name:  "event",
+			Usage: "Event type to send, repeat for more: " + strings.Join(models.WebhookEventTypes, ", "),
+		},
+		&cli.StringFlag{
+			Name:  "secret",
+			Usage: "Secret the deliveries are signed with, a random one is generated when it is not set",
+		},
+	},
+}
+
+type createWebhookCommand struct {
+	serviceURL string
+}
+
+func newCreateWebhookCommand(serviceURL string) *createWebhookCommand {
+	return &createWebhookCommand{serviceURL: serviceURL}
+}
+
+func (i *createWebhookCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	subscription, err := omsClient.CreateWebhook(&models.WebhookSubscription{
+		URL:        c.String("target"),
+		EventTypes: c.StringSlice("event"),
+		Secret:     c.String("secret"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot create webhook")
+	}
+
+	fmt.Printf("Webhook %d was created, its deliveries are signed with the secret %s\n", subscription.ID,
+		subscription.Secret)
+
+	return nil
+}
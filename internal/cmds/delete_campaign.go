@@ -0,0 +1,65 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var DeleteCampaign = &cli.Command{
+	Name:    "delete-campaign",
+	Aliases: []string{"dc"},
+	Usage:   "Delete a campaign",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newDeleteCampaignCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the campaign",
+		},
+		&cli.BoolFlag{
+			Name:  "cascade",
+			Usage: "Also delete the line items and draft invoices of the campaign",
+		},
+		newVersionFlag(),
+	},
+}
+
+type deleteCampaignCommand struct {
+	serviceURL string
+}
+
+func newDeleteCampaignCommand(serviceURL string) *deleteCampaignCommand {
+	return &deleteCampaignCommand{serviceURL: serviceURL}
+}
+
+func (i *deleteCampaignCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	err := omsClient.DeleteCampaign(&client.DeleteCampaignRequest{
+		ID:      id,
+		Version: c.Int("version"),
+		Cascade: c.Bool("cascade"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot delete campaign")
+	}
+
+	fmt.Printf("Campaign %d was moved to the trash\n", id)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var DeleteWebhook = &cli.Command{
+	Name:    "delete-webhook",
+	Aliases: []string{"dw"},
+	Usage:   "Delete a webhook subscription and its delivery log",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newDeleteWebhookCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the webhook subscription",
+		},
+		newVersionFlag(),
+	},
+}
+
+type deleteWebhookCommand struct {
+	serviceURL string
+}
+
+func newDeleteWebhookCommand(serviceURL string) *deleteWebhookCommand {
+	return &deleteWebhookCommand{serviceURL: serviceURL}
+}
+
+func (i *deleteWebhookCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	if err := omsClient.DeleteWebhook(id, c.Int("version")); err != nil {
+		return errors.Wrap(err, "Cannot delete webhook")
+	}
+
+	fmt.Printf("Webhook %d was deleted\n", id)
+
+	return nil
+}
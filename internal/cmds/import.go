@@ -41,6 +41,11 @@ var Import = &cli.Command{
 		&cli.BoolFlag{
 			Name: "generateInvoices",
 		},
+		&cli.IntFlag{
+			Name:  "batchSize",
+			Value: 500,
+			Usage: "Number of campaign line items created per request",
+		},
 	},
 }
 
@@ -80,20 +85,25 @@ func (i *importCommand) Run(c *cli.Context) error {
 	}
 
 	genInvoices := c.Bool("generateInvoices")
+	batch := newLineItemBatch(omsClient, c.Int("batchSize"))
 
 	for decoder.More() {
 		var item map[string]interface{}
 		if decodeErr := decoder.Decode(&item); decodeErr != nil {
-			return errors.Wrap(err, "Cannot decode json")
+			return errors.Wrap(decodeErr, "Cannot decode json")
 		}
 
-		uploadErr := uploadData(omsClient, campaignsCreatedIDMap, item)
+		uploadErr := uploadData(omsClient, campaignsCreatedIDMap, batch, item)
 		if uploadErr != nil {
-			return err
+			return uploadErr
 		}
 		campaignLineItemsCount++
 	}
 
+	if err := batch.flush(); err != nil {
+		return err
+	}
+
 	createdInvoices := 0
 
 	if genInvoices {
@@ -110,7 +120,8 @@ func (i *importCommand) Run(c *cli.Context) error {
 	return nil
 }
 
-func uploadData(omsClient *client.Client, campaignsCreatedIDMap map[int]struct{}, item map[string]interface{}) error {
+func uploadData(omsClient *client.Client, campaignsCreatedIDMap map[int]struct{}, batch *lineItemBatch,
+	item map[string]interface{}) error {
 	campaign, campaignLineItem := processLine(item)
 	campaignID := campaign.ID
 
@@ -123,11 +134,49 @@ func uploadData(omsClient *client.Client, campaignsCreatedIDMap map[int]struct{}
 		campaignsCreatedIDMap[campaignID] = struct{}{}
 	}
 
-	_, err := omsClient.CreateCampaignOrderLine(campaignLineItem)
+	return batch.add(campaignLineItem)
+}
+
+// lineItemBatch collects campaign line items to create them with a single request per batch.
+type lineItemBatch struct {
+	omsClient  *client.Client
+	size       int
+	operations []*models.CampaignLineItemOperation
+}
+
+func newLineItemBatch(omsClient *client.Client, size int) *lineItemBatch {
+	if size <= 0 {
+		size = 500
+	}
+
+	return &lineItemBatch{omsClient: omsClient, size: size}
+}
+
+func (b *lineItemBatch) add(campaignLineItem *models.CampaignLineItem) error {
+	b.operations = append(b.operations, &models.CampaignLineItemOperation{
+		Op:       models.BatchOperationCreate,
+		LineItem: campaignLineItem,
+	})
+
+	if len(b.operations) < b.size {
+		return nil
+	}
+
+	return b.flush()
+}
+
+func (b *lineItemBatch) flush() error {
+	if len(b.operations) == 0 {
+		return nil
+	}
+
+	_, err := b.omsClient.BatchCampaignLineItems(b.operations)
 	if err != nil {
-		return errors.Wrapf(err, "Cannot create a campaign line item")
+		return errors.Wrapf(err, "Cannot create a batch of %d campaign line items", len(b.operations))
 	}
 
+	b.operations = nil
+
 	return nil
 }
 
@@ -171,9 +220,8 @@ func processLine(item map[string]interface{}) (*models.Campaign, *models.Campaig
 	actualAmount := item["actual_amount"].(float64)
 	adjustments := item["adjustments"].(float64)
 
-	// ideally should have more of a bulk apis to do this.
-	// but just doing this "the longer way" at the moment,
-	// will perf optimize if bring in 10k is slower
+	// campaigns are still created one request each, the line items
+	// are sent in batches by uploadData.
 	campaign := &models.Campaign{
 		ID:   int(campaignID),
 		Name: campaignName,
@@ -0,0 +1,267 @@
+package cmds
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var IngestDelivery = &cli.Command{
+	Name:    "ingest-delivery",
+	Aliases: []string{"ind"},
+	Usage:   "Ingest daily line item delivery from a CSV or NDJSON file and recompute the actuals",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		filePath := c.String("file")
+		if filePath == "" {
+			return NewMissingError("file")
+		}
+
+		cmd := newIngestDeliveryCommand(url, filePath)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name: "file",
+			Usage: "Records with line_item_id, date (YYYY-MM-DD), impressions, clicks, conversions and spend, " +
+				"as a CSV with a header or one JSON object per line",
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "csv or ndjson, defaults to the extension of the file",
+		},
+		&cli.IntFlag{
+			Name:  "batchSize",
+			Value: 1000,
+			Usage: "Number of records sent per request",
+		},
+	},
+}
+
+var (
+	errUnknownDeliveryFormat = errors.New("format must be csv or ndjson")
+	errMissingDeliveryColumn = errors.New("missing column")
+)
+
+const deliveryDateLayout = "2006-01-02"
+
+// deliveryRecord is a line of a delivery file.
+type deliveryRecord struct {
+	LineItemID  int     `json:"line_item_id"`
+	Date        string  `json:"date"`
+	Impressions int64   `json:"impressions"`
+	Clicks      int64   `json:"clicks"`
+	Conversions int64   `json:"conversions"`
+	Spend       float64 `json:"spend"`
+}
+
+func (r *deliveryRecord) toModel() (*models.LineItemDelivery, error) {
+	date, err := time.Parse(deliveryDateLayout, r.Date)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid date %q for line item %d", r.Date, r.LineItemID)
+	}
+
+	return &models.LineItemDelivery{
+		LineItemID:  r.LineItemID,
+		Date:        date,
+		Impressions: r.Impressions,
+		Clicks:      r.Clicks,
+		Conversions: r.Conversions,
+		Spend:       r.Spend,
+	}, nil
+}
+
+type ingestDeliveryCommand struct {
+	serviceURL string
+	filePath   string
+}
+
+func newIngestDeliveryCommand(serviceURL, filePath string) *ingestDeliveryCommand {
+	return &ingestDeliveryCommand{serviceURL: serviceURL, filePath: filePath}
+}
+
+func (i *ingestDeliveryCommand) Run(c *cli.Context) error {
+	file, err := os.Open(i.filePath)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot open file at path %s", i.filePath)
+	}
+
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Printf("Error closing delivery file: %s\n", closeErr)
+		}
+	}()
+
+	format := c.String("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(i.filePath)), ".")
+	}
+
+	var records []*models.LineItemDelivery
+
+	switch format {
+	case "csv":
+		records, err = readDeliveryCSV(file)
+	case "ndjson", "jsonl", "json":
+		records, err = readDeliveryNDJSON(file)
+	default:
+		return errUnknownDeliveryFormat
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return i.send(client.NewClient(i.serviceURL), records, c.Int("batchSize"))
+}
+
+func (i *ingestDeliveryCommand) send(omsClient *client.Client, records []*models.LineItemDelivery,
+	batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	lineItems := map[int]struct{}{}
+
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+
+		if _, err := omsClient.IngestDelivery(records[start:end]); err != nil {
+			return errors.Wrapf(err, "Cannot ingest records %d to %d", start+1, end)
+		}
+
+		for _, record := range records[start:end] {
+			lineItems[record.LineItemID] = struct{}{}
+		}
+	}
+
+	fmt.Printf("Ingested %d delivery records for %d line items\n", len(records), len(lineItems))
+
+	return nil
+}
+
+func readDeliveryNDJSON(reader io.Reader) ([]*models.LineItemDelivery, error) {
+	var records []*models.LineItemDelivery
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record deliveryRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, errors.Wrapf(err, "Cannot decode line %d", lineNumber)
+		}
+
+		model, err := record.toModel()
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+
+		records = append(records, model)
+	}
+
+	return records, errors.Wrap(scanner.Err(), "Cannot read file")
+}
+
+func readDeliveryCSV(reader io.Reader) ([]*models.LineItemDelivery, error) {
+	csvReader := csv.NewReader(reader)
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read the header")
+	}
+
+	columns := map[string]int{}
+	for index, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = index
+	}
+
+	for _, required := range []string{"line_item_id", "date"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.Wrap(errMissingDeliveryColumn, required)
+		}
+	}
+
+	var records []*models.LineItemDelivery
+
+	for lineNumber := 2; ; lineNumber++ {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot read line %d", lineNumber)
+		}
+
+		model, err := parseDeliveryRow(columns, row)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+
+		records = append(records, model)
+	}
+}
+
+func parseDeliveryRow(columns map[string]int, row []string) (*models.LineItemDelivery, error) {
+	value := func(name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(row) {
+			return ""
+		}
+
+		return strings.TrimSpace(row[index])
+	}
+
+	var err error
+
+	record := deliveryRecord{Date: value("date")}
+
+	if record.LineItemID, err = strconv.Atoi(value("line_item_id")); err != nil {
+		return nil, errors.Wrap(err, "invalid line_item_id")
+	}
+
+	for name, target := range map[string]*int64{
+		"impressions": &record.Impressions,
+		"clicks":      &record.Clicks,
+		"conversions": &record.Conversions,
+	} {
+		if raw := value(name); raw != "" {
+			if *target, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", name)
+			}
+		}
+	}
+
+	if raw := value("spend"); raw != "" {
+		if record.Spend, err = strconv.ParseFloat(raw, 64); err != nil {
+			return nil, errors.Wrap(err, "invalid spend")
+		}
+	}
+
+	return record.toModel()
+}
@@ -36,6 +36,22 @@ var ListCampaignItemLine = &cli.Command{
 			Name:    "followNextPage",
 			Aliases: []string{"fnp"},
 		},
+		&cli.BoolFlag{
+			Name:  "includeDeleted",
+			Usage: "Include records that are in the trash",
+		},
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "Only list records matching the filter, e.g. \"contains(name,'spring') and started_at gt 2024-01-01\"",
+		},
+		&cli.StringFlag{
+			Name:  "orderBy",
+			Usage: "Sort by fields, e.g. \"started_at desc, name\"",
+		},
+		&cli.IntFlag{
+			Name:  "campaignId",
+			Usage: "Only list the line items of this campaign",
+		},
 	},
 }
 
@@ -53,8 +69,13 @@ func (i *listCampaignItemLineCommand) Run(c *cli.Context) error {
 	limit := c.Int("limit")
 	token := c.String("token")
 	pageThrough := c.Bool("followNextPage")
+	includeDeleted := c.Bool("includeDeleted")
+	campaignID := c.Int("campaignId")
 
-	req := buildListCampaignLineItemRequest(limit, token)
+	req := buildListCampaignLineItemRequest(limit, token, includeDeleted, campaignID)
+	req.Filter = c.String("filter")
+	req.OrderBy = c.String("orderBy")
+	req.Count = true
 
 	resp, err := omsClient.ListCampaignLineItems(req)
 	if err != nil {
@@ -63,19 +84,27 @@ func (i *listCampaignItemLineCommand) Run(c *cli.Context) error {
 
 	printCampaignLineItems(resp.Items, true)
 
-	if pageThrough {
-		err = paginateCampaignLineItems(omsClient, resp.NextPageToken)
-		if err != nil {
-			return errors.Wrap(err, "failed to paginate campaign line items")
-		}
+	if !pageThrough {
+		printListFooter(len(resp.Items), resp.TotalCount, resp.NextPageToken, resp.PreviousPageToken)
+		return nil
+	}
+
+	shown, err := paginateCampaignLineItems(omsClient, resp.NextPageToken, includeDeleted, campaignID)
+	if err != nil {
+		return errors.Wrap(err, "failed to paginate campaign line items")
 	}
 
+	printListFooter(len(resp.Items)+shown, resp.TotalCount, "", "")
+
 	return nil
 }
 
-func buildListCampaignLineItemRequest(limit int, token string) *client.ListCampaignLineItemRequest {
+func buildListCampaignLineItemRequest(limit int, token string,
+	includeDeleted bool, campaignID int) *client.ListCampaignLineItemRequest {
 	req := &client.ListCampaignLineItemRequest{
-		Size: limit,
+		Size:           limit,
+		IncludeDeleted: includeDeleted,
+		CampaignID:     campaignID,
 	}
 
 	if token != "" {
@@ -103,16 +132,24 @@ func printCampaignLineItems(lineItems []*models.CampaignLineItem, writeHeader bo
 	}
 }
 
-func paginateCampaignLineItems(omsClient *client.Client, nextPageToken string) error {
+func paginateCampaignLineItems(omsClient *client.Client, nextPageToken string, includeDeleted bool,
+	campaignID int) (int, error) {
+	shown := 0
+
 	for nextPageToken != "" {
-		resp, err := omsClient.ListCampaignLineItems(&client.ListCampaignLineItemRequest{Token: &nextPageToken})
+		resp, err := omsClient.ListCampaignLineItems(&client.ListCampaignLineItemRequest{
+			Token:          &nextPageToken,
+			IncludeDeleted: includeDeleted,
+			CampaignID:     campaignID,
+		})
 		if err != nil {
-			return err
+			return shown, err
 		}
 
 		printCampaignLineItems(resp.Items, false)
+		shown += len(resp.Items)
 		nextPageToken = resp.NextPageToken
 	}
 
-	return nil
+	return shown, nil
 }
@@ -37,6 +37,18 @@ var ListCampaign = &cli.Command{
 			Name:    "followNextPage",
 			Aliases: []string{"fnp"},
 		},
+		&cli.BoolFlag{
+			Name:  "includeDeleted",
+			Usage: "Include records that are in the trash",
+		},
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "Only list records matching the filter, e.g. \"contains(name,'spring') and started_at gt 2024-01-01\"",
+		},
+		&cli.StringFlag{
+			Name:  "orderBy",
+			Usage: "Sort by fields, e.g. \"started_at desc, name\"",
+		},
 	},
 }
 
@@ -54,8 +66,13 @@ func (i *listCampaignCommand) Run(c *cli.Context) error {
 	limit := c.Int("limit")
 	token := c.String("token")
 	pageThrough := c.Bool("followNextPage")
+	includeDeleted := c.Bool("includeDeleted")
+
+	req := buildListCampaignRequest(limit, token, includeDeleted)
+	req.Filter = c.String("filter")
+	req.OrderBy = c.String("orderBy")
+	req.Count = true
 
-	req := buildListCampaignRequest(limit, token)
 	resp, err := omsClient.ListCampaigns(req)
 
 	if err != nil {
@@ -64,19 +81,25 @@ func (i *listCampaignCommand) Run(c *cli.Context) error {
 
 	printCampaigns(resp.Items, true)
 
-	if pageThrough {
-		err = paginateCampaigns(omsClient, resp.NextPageToken)
-		if err != nil {
-			return errors.Wrap(err, "failed to paginate campaigns")
-		}
+	if !pageThrough {
+		printListFooter(len(resp.Items), resp.TotalCount, resp.NextPageToken, resp.PreviousPageToken)
+		return nil
+	}
+
+	shown, err := paginateCampaigns(omsClient, resp.NextPageToken, includeDeleted)
+	if err != nil {
+		return errors.Wrap(err, "failed to paginate campaigns")
 	}
 
+	printListFooter(len(resp.Items)+shown, resp.TotalCount, "", "")
+
 	return nil
 }
 
-func buildListCampaignRequest(limit int, token string) *client.ListCampaignRequest {
+func buildListCampaignRequest(limit int, token string, includeDeleted bool) *client.ListCampaignRequest {
 	req := &client.ListCampaignRequest{
-		Size: limit,
+		Size:           limit,
+		IncludeDeleted: includeDeleted,
 	}
 
 	if token != "" {
@@ -104,18 +127,44 @@ func printCampaigns(campaigns []*models.Campaign, writeHeader bool) {
 	}
 }
 
-func paginateCampaigns(omsClient *client.Client, nextPageToken string) error {
+func paginateCampaigns(omsClient *client.Client, nextPageToken string, includeDeleted bool) (int, error) {
+	shown := 0
+
 	for nextPageToken != "" {
-		resp, err := omsClient.ListCampaigns(&client.ListCampaignRequest{Token: &nextPageToken})
+		resp, err := omsClient.ListCampaigns(&client.ListCampaignRequest{
+			Token:          &nextPageToken,
+			IncludeDeleted: includeDeleted,
+		})
 		if err != nil {
-			return err
+			return shown, err
 		}
 
 		printCampaigns(resp.Items, false)
+		shown += len(resp.Items)
 		nextPageToken = resp.NextPageToken
 	}
 
-	return nil
+	return shown, nil
+}
+
+// printListFooter prints how many records were shown out of how many match, and
+// the tokens to continue from when the pages were not followed.
+func printListFooter(shown int, total *int64, nextPageToken, previousPageToken string) {
+	fmt.Println()
+
+	if total != nil {
+		fmt.Printf("Showing %d of %d\n", shown, *total)
+	} else {
+		fmt.Printf("Showing %d\n", shown)
+	}
+
+	if nextPageToken != "" {
+		fmt.Printf("Next page:\t--token %s\n", nextPageToken)
+	}
+
+	if previousPageToken != "" {
+		fmt.Printf("Previous page:\t--token %s\n", previousPageToken)
+	}
 }
 
 func toCompactTime(t *time.Time) string {
@@ -36,6 +36,18 @@ var ListInvoices = &cli.Command{
 			Name:    "followNextPage",
 			Aliases: []string{"fnp"},
 		},
+		&cli.BoolFlag{
+			Name:  "includeDeleted",
+			Usage: "Include records that are in the trash",
+		},
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "Only list records matching the filter, e.g. \"contains(name,'spring') and started_at gt 2024-01-01\"",
+		},
+		&cli.StringFlag{
+			Name:  "orderBy",
+			Usage: "Sort by fields, e.g. \"started_at desc, name\"",
+		},
 		&cli.BoolFlag{
 			Name: "allFields",
 		},
@@ -56,8 +68,12 @@ func (i *listInvoicesCommand) Run(c *cli.Context) error {
 	limit := c.Int("limit")
 	token := c.String("token")
 	pageThrough := c.Bool("followNextPage")
+	includeDeleted := c.Bool("includeDeleted")
 
-	req := i.buildListInvoicesRequest(limit, token)
+	req := i.buildListInvoicesRequest(limit, token, includeDeleted)
+	req.Filter = c.String("filter")
+	req.OrderBy = c.String("orderBy")
+	req.Count = true
 
 	resp, err := omsClient.ListInvoices(req)
 	if err != nil {
@@ -68,19 +84,26 @@ func (i *listInvoicesCommand) Run(c *cli.Context) error {
 
 	i.printInvoices(resp.Items, true, allFields)
 
-	if pageThrough {
-		err = i.paginateInvoices(omsClient, resp.NextPageToken, allFields)
-		if err != nil {
-			return errors.Wrap(err, "failed to paginate invoices")
-		}
+	if !pageThrough {
+		printListFooter(len(resp.Items), resp.TotalCount, resp.NextPageToken, resp.PreviousPageToken)
+		return nil
+	}
+
+	shown, err := i.paginateInvoices(omsClient, resp.NextPageToken, allFields, includeDeleted)
+	if err != nil {
+		return errors.Wrap(err, "failed to paginate invoices")
 	}
 
+	printListFooter(len(resp.Items)+shown, resp.TotalCount, "", "")
+
 	return nil
 }
 
-func (i *listInvoicesCommand) buildListInvoicesRequest(limit int, token string) *client.ListInvoicesRequest {
+func (i *listInvoicesCommand) buildListInvoicesRequest(limit int, token string,
+	includeDeleted bool) *client.ListInvoicesRequest {
 	req := &client.ListInvoicesRequest{
-		Size: limit,
+		Size:           limit,
+		IncludeDeleted: includeDeleted,
 	}
 
 	if token != "" {
@@ -120,16 +143,23 @@ func (i *listInvoicesCommand) printInvoices(invoices []*models.Invoice, writeHea
 	}
 }
 
-func (i *listInvoicesCommand) paginateInvoices(omsClient *client.Client, nextPageToken string, allFields bool) error {
+func (i *listInvoicesCommand) paginateInvoices(omsClient *client.Client, nextPageToken string,
+	allFields, includeDeleted bool) (int, error) {
+	shown := 0
+
 	for nextPageToken != "" {
-		resp, err := omsClient.ListInvoices(&client.ListInvoicesRequest{Token: &nextPageToken})
+		resp, err := omsClient.ListInvoices(&client.ListInvoicesRequest{
+			Token:          &nextPageToken,
+			IncludeDeleted: includeDeleted,
+		})
 		if err != nil {
-			return err
+			return shown, err
 		}
 
 		i.printInvoices(resp.Items, false, allFields)
+		shown += len(resp.Items)
 		nextPageToken = resp.NextPageToken
 	}
 
-	return nil
+	return shown, nil
 }
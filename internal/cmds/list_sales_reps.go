@@ -0,0 +1,73 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var ListSalesReps = &cli.Command{
+	Name:    "list-sales-reps",
+	Aliases: []string{"lsr"},
+	Usage:   "List sales reps and their commission plans",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newListSalesRepsCommand(url)
+		return cmd.Run(c)
+	},
+}
+
+type listSalesRepsCommand struct {
+	serviceURL string
+}
+
+func newListSalesRepsCommand(serviceURL string) *listSalesRepsCommand {
+	return &listSalesRepsCommand{serviceURL: serviceURL}
+}
+
+func (i *listSalesRepsCommand) Run(_ *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	reps, err := omsClient.ListSalesReps()
+	if err != nil {
+		return errors.Wrap(err, "failed to list sales reps")
+	}
+
+	plans, err := omsClient.ListCommissionPlans()
+	if err != nil {
+		return errors.Wrap(err, "failed to list commission plans")
+	}
+
+	planNames := map[int]string{}
+	for _, plan := range plans.Items {
+		planNames[plan.ID] = plan.Name + " (" + strconv.FormatFloat(plan.RatePercent, 'f', -1, 64) + "% " +
+			plan.Basis + ")"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tName\tEmail\tCommissionPlan\n")
+
+	for _, rep := range reps.Items {
+		plan := ""
+		if rep.CommissionPlanID != nil {
+			plan = planNames[*rep.CommissionPlanID]
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", rep.ID, rep.Name, rep.Email, plan)
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
+
+	return nil
+}
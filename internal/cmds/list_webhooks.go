@@ -0,0 +1,58 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var ListWebhooks = &cli.Command{
+	Name:    "list-webhooks",
+	Aliases: []string{"lw"},
+	Usage:   "List webhook subscriptions",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newListWebhooksCommand(url)
+		return cmd.Run(c)
+	},
+}
+
+type listWebhooksCommand struct {
+	serviceURL string
+}
+
+func newListWebhooksCommand(serviceURL string) *listWebhooksCommand {
+	return &listWebhooksCommand{serviceURL: serviceURL}
+}
+
+func (i *listWebhooksCommand) Run(_ *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	subscriptions, err := omsClient.ListWebhooks()
+	if err != nil {
+		return errors.Wrap(err, "failed to list webhooks")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tURL\tEvents\tVersion\n")
+
+	for _, subscription := range subscriptions.Items {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", subscription.ID, subscription.URL, strings.Join(subscription.EventTypes, ","),
+			subscription.Version)
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var errNothingToUpdate = errors.New("no fields to update, set at least one flag")
+
+var errNotClearable = errors.New("field cannot be cleared")
+
+// clearFlag lets the update commands send null for fields that can be emptied.
+func clearFlag(fields map[string]string) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:  "clear",
+		Usage: "Clear a field instead of setting it, one of " + strings.Join(flagNames(fields), ", "),
+	}
+}
+
+// applyClearFlag sets the fields named by the clear flag to null in the merge patch.
+func applyClearFlag(c *cli.Context, patch map[string]interface{}, fields map[string]string) error {
+	for _, name := range c.StringSlice("clear") {
+		field, ok := fields[name]
+		if !ok {
+			return errors.Wrapf(errNotClearable, "%s, expected one of %s", name, strings.Join(flagNames(fields), ", "))
+		}
+
+		if _, set := patch[field]; set {
+			return fmt.Errorf("cannot both set and clear %s", name)
+		}
+
+		patch[field] = nil
+	}
+
+	if len(patch) == 0 {
+		return errNothingToUpdate
+	}
+
+	return nil
+}
+
+func flagNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,73 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var MoveLineItem = &cli.Command{
+	Name:    "move-line-item",
+	Aliases: []string{"mli"},
+	Usage:   "Move a campaign line item to another campaign, keeping its id and history",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newMoveLineItemCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the campaign line item",
+		},
+		&cli.IntFlag{
+			Name:  "campaignId",
+			Usage: "Id of the campaign to move the line item to",
+		},
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Why the line item is moved, kept in its history",
+		},
+	},
+}
+
+type moveLineItemCommand struct {
+	serviceURL string
+}
+
+func newMoveLineItemCommand(serviceURL string) *moveLineItemCommand {
+	return &moveLineItemCommand{serviceURL: serviceURL}
+}
+
+func (i *moveLineItemCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	campaignID := c.Int("campaignId")
+	if campaignID == 0 {
+		return NewMissingError("campaignId")
+	}
+
+	err := omsClient.MoveCampaignLineItem(&client.MoveCampaignLineItemRequest{
+		ID:         id,
+		CampaignID: campaignID,
+		Reason:     c.String("reason"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot move campaign line item")
+	}
+
+	fmt.Printf("CampaignLineItem %d was moved to campaign %d\n", id, campaignID)
+
+	return nil
+}
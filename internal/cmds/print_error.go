@@ -0,0 +1,58 @@
+package cmds
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+)
+
+// PrintError prints why a command failed. A problem of the service is printed with
+// its code, one line per rejected field or operation and the request id to quote
+// when reporting it.
+func PrintError(w io.Writer, err error) {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	// Keep what the command was doing, the wrapping in front of the problem.
+	doing := strings.TrimSuffix(err.Error(), apiErr.Error())
+
+	detail := apiErr.Detail
+	if detail == "" {
+		detail = apiErr.Title
+	}
+
+	fmt.Fprintf(w, "Error: %s%s\n", doing, detail)
+	fmt.Fprintf(w, "  Code: %s (%d)\n", apiErr.Code, apiErr.StatusCode)
+
+	for _, field := range apiErr.Errors {
+		fmt.Fprintf(w, "  %s: %s\n", field.Field, field.Message)
+	}
+
+	for _, result := range apiErr.Results {
+		if result.Status == http.StatusOK {
+			continue
+		}
+
+		fmt.Fprintf(w, "  Operation %d (%s %d): %s\n", result.Index, result.Op, result.ID, result.Error)
+
+		for _, field := range result.Fields {
+			fmt.Fprintf(w, "    %s: %s\n", field.Field, field.Message)
+		}
+	}
+
+	if apiErr.Dependents != nil {
+		fmt.Fprintf(w, "  Dependents: %d line items, %d draft invoices, %d issued invoices\n",
+			apiErr.Dependents.LineItems, apiErr.Dependents.DraftInvoices, apiErr.Dependents.IssuedInvoices)
+	}
+
+	if apiErr.RequestID != "" {
+		fmt.Fprintf(w, "  Request ID: %s\n", apiErr.RequestID)
+	}
+}
@@ -0,0 +1,77 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var Restore = &cli.Command{
+	Name:  "restore",
+	Usage: "Restore a campaign, campaign line item or invoice from the trash",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newRestoreCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "resource",
+			Value: "campaign",
+			Usage: "Type of the record to restore: campaign, lineItem or invoice",
+		},
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the record to restore",
+		},
+	},
+}
+
+var errUnknownResource = errors.New("resource must be one of campaign, lineItem or invoice")
+
+type restoreCommand struct {
+	serviceURL string
+}
+
+func newRestoreCommand(serviceURL string) *restoreCommand {
+	return &restoreCommand{serviceURL: serviceURL}
+}
+
+func (i *restoreCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	resource := c.String("resource")
+	req := &client.RestoreRequest{ID: id}
+
+	var err error
+
+	switch resource {
+	case "campaign":
+		err = omsClient.RestoreCampaign(req)
+	case "lineItem":
+		err = omsClient.RestoreCampaignLineItem(req)
+	case "invoice":
+		err = omsClient.RestoreInvoice(req)
+	default:
+		return errUnknownResource
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "Cannot restore %s", resource)
+	}
+
+	fmt.Printf("Restored %s %d\n", resource, id)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var RetryWebhookDelivery = &cli.Command{
+	Name:    "retry-webhook-delivery",
+	Aliases: []string{"rwd"},
+	Usage:   "Send a dead webhook delivery again",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newRetryWebhookDeliveryCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the webhook delivery",
+		},
+	},
+}
+
+type retryWebhookDeliveryCommand struct {
+	serviceURL string
+}
+
+func newRetryWebhookDeliveryCommand(serviceURL string) *retryWebhookDeliveryCommand {
+	return &retryWebhookDeliveryCommand{serviceURL: serviceURL}
+}
+
+func (i *retryWebhookDeliveryCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	if err := omsClient.RetryWebhookDelivery(id); err != nil {
+		return errors.Wrap(err, "Cannot retry webhook delivery")
+	}
+
+	fmt.Printf("Webhook delivery %d will be sent again\n", id)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var SetCampaignOwners = &cli.Command{
+	Name:    "set-campaign-owners",
+	Aliases: []string{"sco"},
+	Usage:   "Replace the sales reps owning a campaign, e.g. --owner 3:60 --owner 4:40",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newSetCampaignOwnersCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the campaign",
+		},
+		&cli.StringSliceFlag{
+			Name:  "owner",
+			Usage: "Owner as salesRepId:splitPercent, a single owner may omit the split. None removes all owners",
+		},
+		newVersionFlag(),
+	},
+}
+
+var errInvalidOwner = errors.New("owner must be salesRepId or salesRepId:splitPercent")
+
+type setCampaignOwnersCommand struct {
+	serviceURL string
+}
+
+func newSetCampaignOwnersCommand(serviceURL string) *setCampaignOwnersCommand {
+	return &setCampaignOwnersCommand{serviceURL: serviceURL}
+}
+
+func (i *setCampaignOwnersCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	owners := []*models.CampaignOwner{}
+
+	for _, value := range c.StringSlice("owner") {
+		owner, err := parseOwner(value)
+		if err != nil {
+			return err
+		}
+
+		owners = append(owners, owner)
+	}
+
+	err := omsClient.SetCampaignOwners(&client.SetCampaignOwnersRequest{
+		CampaignID: id,
+		Version:    c.Int("version"),
+		Owners:     owners,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot set campaign owners")
+	}
+
+	fmt.Printf("Campaign %d now has %d owners\n", id, len(owners))
+
+	return nil
+}
+
+func parseOwner(value string) (*models.CampaignOwner, error) {
+	repPart, splitPart, hasSplit := strings.Cut(value, ":")
+
+	repID, err := strconv.Atoi(repPart)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidOwner, value)
+	}
+
+	split := 100.0
+
+	if hasSplit {
+		split, err = strconv.ParseFloat(splitPart, 64)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidOwner, value)
+		}
+	}
+
+	return &models.CampaignOwner{SalesRepID: repID, SplitPercent: split}, nil
+}
@@ -2,9 +2,12 @@ package cmds
 
 import (
 	"fmt"
+	"os"
 	"strconv"
+	"text/tabwriter"
 
 	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 )
@@ -27,6 +30,14 @@ var ShowCampaign = &cli.Command{
 			Name:  "id",
 			Usage: "Id of the campaign",
 		},
+		&cli.BoolFlag{
+			Name:  "summary",
+			Usage: "Include the aggregated financials of the campaign",
+		},
+		&cli.StringSliceFlag{
+			Name:  "expand",
+			Usage: "Include related records in the same request: line_items, invoices",
+		},
 	},
 }
 
@@ -48,7 +59,7 @@ func (i *showCampaignCommand) Run(c *cli.Context) error {
 		return errMissingID
 	}
 
-	resp, err := omsClient.ShowCampaign(&client.ShowCampaignRequest{ID: id})
+	resp, err := omsClient.ShowExpandedCampaign(&client.ShowCampaignRequest{ID: id, Expand: c.StringSlice("expand")})
 	if err != nil {
 		return errors.Wrap(err, "Cannot show campaign")
 	}
@@ -61,6 +72,53 @@ func (i *showCampaignCommand) Run(c *cli.Context) error {
 	fmt.Printf("Name:\t\t%s\n", resp.Name)
 	fmt.Printf("StartedAt:\t%s\n", toCompactTime(resp.StartedAt))
 	fmt.Printf("UpdatedAt:\t%s\n", toCompactTime(&resp.UpdatedAt))
+	fmt.Printf("Version:\t%d\n", resp.Version)
+
+	if resp.LineItems != nil {
+		fmt.Printf("\nLineItems\n")
+		printCampaignLineItems(resp.LineItems, true)
+	}
+
+	if resp.Invoices != nil {
+		fmt.Printf("\nInvoices\n")
+		printCampaignInvoices(resp.Invoices)
+	}
+
+	if !c.Bool("summary") {
+		return nil
+	}
+
+	summary, err := omsClient.ShowCampaignSummary(&client.ShowCampaignRequest{ID: id})
+	if err != nil {
+		return errors.Wrap(err, "Cannot show campaign summary")
+	}
+
+	fmt.Printf("\nSummary\n")
+	fmt.Printf("LineItems:\t%d\n", summary.LineItemCount)
+	fmt.Printf("Booked:\t\t%f\n", summary.TotalBooked)
+	fmt.Printf("Actual:\t\t%f\n", summary.TotalActual)
+	fmt.Printf("Adjustments:\t%f\n", summary.TotalAdjustments)
+	fmt.Printf("Variance:\t%f\n", summary.Variance)
+	fmt.Printf("Delivered:\t%.2f%%\n", summary.PercentDelivered)
+	fmt.Printf("Invoiced:\t%f\n", summary.InvoicedToDate)
+	fmt.Printf("Uninvoiced:\t%f\n", summary.Uninvoiced)
 
 	return nil
 }
+
+func printCampaignInvoices(invoices []*models.Invoice) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer func() {
+		err := w.Flush()
+		if err != nil {
+			fmt.Printf("Unexpected flush error: %v", err)
+		}
+	}()
+
+	fmt.Fprintf(w, "ID\tTotalActual\tTotalAdjustments\tIssuedAt\n")
+
+	for _, inv := range invoices {
+		fmt.Fprintf(w, "%d\t%f\t%f\t%s\n", inv.ID, inv.TotalActualAmount, inv.TotalAdjustments,
+			toCompactTime(&inv.IssuedAt))
+	}
+}
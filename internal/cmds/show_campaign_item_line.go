@@ -2,6 +2,9 @@ package cmds
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
 
 	"github.com/chrisrob11/oms/internal/client"
 	"github.com/pkg/errors"
@@ -26,6 +29,10 @@ var ShowCampaignItemLine = &cli.Command{
 			Name:  "id",
 			Usage: "Id of the campaign",
 		},
+		&cli.BoolFlag{
+			Name:  "history",
+			Usage: "Include the recorded changes of the line item",
+		},
 	},
 }
 
@@ -57,10 +64,52 @@ func (i *showCampaignItemLineCommand) Run(c *cli.Context) error {
 	fmt.Printf("Actual:\t\t%f\n", resp.Actual)
 	fmt.Printf("Booked:\t\t%f\n", resp.Booked)
 	fmt.Printf("Adjustments:\t%f\n", resp.Adjustments)
+	fmt.Printf("PricingModel:\t%s\n", resp.PricingModel)
+	fmt.Printf("UnitRate:\t%f\n", resp.UnitRate)
+	fmt.Printf("BookedUnits:\t%d\n", resp.BookedUnits)
+	fmt.Printf("DeliveredUnits:\t%d\n", resp.DeliveredUnits)
 	fmt.Printf("CreatedAt:\t%s\n", toCompactTime(&resp.CreatedAt))
 	fmt.Printf("EndedAt:\t%s\n", toCompactTime(resp.EndedAt))
 	fmt.Printf("StartedAt:\t%s\n", toCompactTime(resp.StartedAt))
 	fmt.Printf("UpdatedAt:\t%s\n", toCompactTime(&resp.UpdatedAt))
+	fmt.Printf("Version:\t%d\n", resp.Version)
+
+	if resp.LockedAt != nil {
+		fmt.Printf("Locked:\t\tyes, since %s (unlock-line-item to change it)\n", toCompactTime(resp.LockedAt))
+	} else {
+		fmt.Printf("Locked:\t\tno\n")
+	}
+
+	if !c.Bool("history") {
+		return nil
+	}
+
+	history, err := omsClient.ListCampaignLineItemHistory(&client.ShowCampaignOrderLineRequest{ID: id})
+	if err != nil {
+		return errors.Wrap(err, "Cannot show campaign line item history")
+	}
+
+	fmt.Printf("\nHistory\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "At\tEvent\tFromCampaignID\tToCampaignID\tReason\n")
+
+	for _, entry := range history.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", toCompactTime(&entry.CreatedAt), entry.Event,
+			optionalID(entry.FromCampaignID), optionalID(entry.ToCampaignID), entry.Reason)
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
 
 	return nil
 }
+
+func optionalID(id *int) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*id)
+}
@@ -53,10 +53,12 @@ func (i *showInvoiceCommand) Run(c *cli.Context) error {
 	fmt.Printf("Invoice\n")
 	fmt.Printf("ID:\t\t\t%d\n", resp.ID)
 	fmt.Printf("IssuedAt:\t\t%s\n", toCompactTime(&resp.IssuedAt))
+	fmt.Printf("CollectedAt:\t\t%s\n", toCompactTime(resp.CollectedAt))
 	fmt.Printf("CreatedAt:\t\t%s\n", toCompactTime(&resp.CreatedAt))
 	fmt.Printf("EndedAt:\t\t%s\n", toCompactTime(resp.EndedAt))
 	fmt.Printf("StartedAt:\t\t%s\n", toCompactTime(resp.StartedAt))
 	fmt.Printf("UpdatedAt:\t\t%s\n", toCompactTime(&resp.UpdatedAt))
+	fmt.Printf("Version:\t\t%d\n", resp.Version)
 	fmt.Printf("TotalActual:\t\t%f\n", resp.TotalActualAmount)
 	fmt.Printf("TotalBooked:\t\t%f\n", resp.TotalBookedAmount)
 	fmt.Printf("TotalAdjustments:\t%f\n", resp.TotalAdjustments)
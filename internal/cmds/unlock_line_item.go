@@ -0,0 +1,65 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var UnlockLineItem = &cli.Command{
+	Name:    "unlock-line-item",
+	Aliases: []string{"uli"},
+	Usage:   "Unlock a campaign line item billed by an issued invoice so it can be changed again",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newUnlockLineItemCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the campaign line item",
+		},
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Why the billed line item has to change, kept in its history",
+		},
+	},
+}
+
+type unlockLineItemCommand struct {
+	serviceURL string
+}
+
+func newUnlockLineItemCommand(serviceURL string) *unlockLineItemCommand {
+	return &unlockLineItemCommand{serviceURL: serviceURL}
+}
+
+func (i *unlockLineItemCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	id := c.Int("id")
+	if id == 0 {
+		return errMissingID
+	}
+
+	reason := c.String("reason")
+	if reason == "" {
+		return NewMissingError("reason")
+	}
+
+	err := omsClient.UnlockCampaignLineItem(&client.UnlockCampaignLineItemRequest{ID: id, Reason: reason})
+	if err != nil {
+		return errors.Wrap(err, "Cannot unlock campaign line item")
+	}
+
+	fmt.Printf("CampaignLineItem %d was unlocked\n", id)
+
+	return nil
+}
@@ -38,9 +38,20 @@ var UpdateCampaign = &cli.Command{
 			Name:   "endedAt",
 			Layout: time.DateTime,
 		},
+		&cli.BoolFlag{
+			Name: "archiving",
+		},
+		clearFlag(campaignClearableFields),
+		newVersionFlag(),
 	},
 }
 
+// campaignClearableFields maps the flags that can be cleared to their campaign fields.
+var campaignClearableFields = map[string]string{
+	"startedAt": "StartedAt",
+	"endedAt":   "EndedAt",
+}
+
 type updateCampaignCommand struct {
 	serviceURL string
 }
@@ -57,30 +68,30 @@ func (i *updateCampaignCommand) Run(c *cli.Context) error {
 		return errMissingID
 	}
 
-	foundCampaign, err := omsClient.ShowCampaign(&client.ShowCampaignRequest{
-		ID: id,
-	})
+	// Only the flags that were given are sent, the service keeps every other field.
+	patch := map[string]interface{}{}
 
-	if err != nil {
-		return errors.Wrap(err, "Error finding Campaign")
+	if c.IsSet("name") {
+		patch["Name"] = c.String("name")
+	}
+
+	if c.IsSet("startedAt") {
+		patch["StartedAt"] = c.Timestamp("startedAt")
 	}
 
-	name := c.String("name")
-	if name != "" {
-		foundCampaign.Name = name
+	if c.IsSet("endedAt") {
+		patch["EndedAt"] = c.Timestamp("endedAt")
 	}
 
-	startedAt := c.Timestamp("startedAt")
-	if startedAt != nil {
-		foundCampaign.StartedAt = startedAt
+	if c.IsSet("archiving") {
+		patch["Archiving"] = c.Bool("archiving")
 	}
 
-	endedAt := c.Timestamp("endedAt")
-	if endedAt != nil {
-		foundCampaign.EndedAt = endedAt
+	if err := applyClearFlag(c, patch, campaignClearableFields); err != nil {
+		return err
 	}
 
-	err = omsClient.UpdateCampaign(*foundCampaign)
+	_, err := omsClient.PatchCampaign(id, c.Int("version"), patch)
 	if err != nil {
 		return errors.Wrap(err, "Cannot update campaign")
 	}
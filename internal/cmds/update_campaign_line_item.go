@@ -47,9 +47,31 @@ var UpdateCampaignLineItem = &cli.Command{
 		&cli.Float64Flag{
 			Name: "adjustments",
 		},
+		&cli.StringFlag{
+			Name:  "pricingModel",
+			Usage: "cpm, cpc, cpa or flat, booked and actual are computed from the units unless flat",
+		},
+		&cli.Float64Flag{
+			Name:  "unitRate",
+			Usage: "Price per thousand impressions for cpm, per click for cpc or per action for cpa",
+		},
+		&cli.Int64Flag{
+			Name: "bookedUnits",
+		},
+		&cli.Int64Flag{
+			Name: "deliveredUnits",
+		},
+		clearFlag(campaignLineItemClearableFields),
+		newVersionFlag(),
 	},
 }
 
+// campaignLineItemClearableFields maps the flags that can be cleared to their line item fields.
+var campaignLineItemClearableFields = map[string]string{
+	"startedAt": "StartedAt",
+	"endedAt":   "EndedAt",
+}
+
 type updateCampaignLineItemCommand struct {
 	serviceURL string
 }
@@ -66,49 +88,56 @@ func (i *updateCampaignLineItemCommand) Run(c *cli.Context) error {
 		return errMissingID
 	}
 
-	foundCampaignLineItem, err := omsClient.ShowCampaignLineItem(&client.ShowCampaignOrderLineRequest{
-		ID: id,
-	})
+	// Only the flags that were given are sent, so zero is a value that can be set.
+	patch := map[string]interface{}{}
 
-	if err != nil {
-		return errors.Wrap(err, "Error finding Campaign")
+	if c.IsSet("name") {
+		patch["Name"] = c.String("name")
+	}
+
+	if c.IsSet("startedAt") {
+		patch["StartedAt"] = c.Timestamp("startedAt")
+	}
+
+	if c.IsSet("endedAt") {
+		patch["EndedAt"] = c.Timestamp("endedAt")
+	}
+
+	if c.IsSet("booked") {
+		patch["Booked"] = c.Float64("booked")
+	}
+
+	if c.IsSet("actual") {
+		patch["Actual"] = c.Float64("actual")
 	}
 
-	name := c.String("name")
-	if name != "" {
-		foundCampaignLineItem.Name = name
+	if c.IsSet("adjustments") {
+		patch["Adjustments"] = c.Float64("adjustments")
 	}
 
-	startedAt := c.Timestamp("startedAt")
-	if startedAt != nil {
-		foundCampaignLineItem.StartedAt = startedAt
+	if c.IsSet("pricingModel") {
+		patch["PricingModel"] = c.String("pricingModel")
 	}
 
-	endedAt := c.Timestamp("endedAt")
-	if endedAt != nil {
-		foundCampaignLineItem.EndedAt = endedAt
+	if c.IsSet("unitRate") {
+		patch["UnitRate"] = c.Float64("unitRate")
 	}
 
-	booked := c.Float64("booked")
-	// NOTE: this is kind of a bug, we might want any of these to actually be 0
-	// just noting it. Probably would need to add another flag to handle this
-	if booked != 0 {
-		foundCampaignLineItem.Booked = booked
+	if c.IsSet("bookedUnits") {
+		patch["BookedUnits"] = c.Int64("bookedUnits")
 	}
 
-	actual := c.Float64("actual")
-	if actual != 0 {
-		foundCampaignLineItem.Actual = actual
+	if c.IsSet("deliveredUnits") {
+		patch["DeliveredUnits"] = c.Int64("deliveredUnits")
 	}
 
-	adjustments := c.Float64("adjustments")
-	if adjustments != 0 {
-		foundCampaignLineItem.Adjustments = adjustments
+	if err := applyClearFlag(c, patch, campaignLineItemClearableFields); err != nil {
+		return err
 	}
 
-	err = omsClient.UpdateCampaignLineItem(*foundCampaignLineItem)
+	_, err := omsClient.PatchCampaignLineItem(id, c.Int("version"), patch)
 	if err != nil {
-		return errors.Wrap(err, "Cannot update campaign")
+		return errors.Wrap(err, "Cannot update campaign line item")
 	}
 
 	fmt.Println("Update processed.")
@@ -0,0 +1,137 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var Validate = &cli.Command{
+	Name:  "validate",
+	Usage: "Report existing campaigns and campaign line items that break the flight date rules",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newValidateCommand(url)
+		return cmd.Run(c)
+	},
+}
+
+var errFlightDateViolations = errors.New("flight date violations found")
+
+type validateCommand struct {
+	serviceURL string
+	campaigns  map[int]*models.Campaign
+	violations int
+	writer     *tabwriter.Writer
+}
+
+func newValidateCommand(serviceURL string) *validateCommand {
+	return &validateCommand{serviceURL: serviceURL, campaigns: map[int]*models.Campaign{}}
+}
+
+func (i *validateCommand) Run(_ *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	i.writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(i.writer, "Resource\tID\tField\tProblem\n")
+
+	if err := i.validateCampaigns(omsClient); err != nil {
+		return err
+	}
+
+	if err := i.validateCampaignLineItems(omsClient); err != nil {
+		return err
+	}
+
+	if err := i.writer.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
+
+	if i.violations > 0 {
+		return errors.Wrapf(errFlightDateViolations, "%d problems", i.violations)
+	}
+
+	fmt.Println("No flight date violations found.")
+
+	return nil
+}
+
+func (i *validateCommand) validateCampaigns(omsClient *client.Client) error {
+	req := &client.ListCampaignRequest{Size: 500}
+
+	for {
+		resp, err := omsClient.ListCampaigns(req)
+		if err != nil {
+			return errors.Wrap(err, "failed to list campaigns")
+		}
+
+		for _, campaign := range resp.Items {
+			i.campaigns[campaign.ID] = campaign
+			i.report("Campaign", campaign.ID, campaign.ValidateFlight())
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+
+		req = &client.ListCampaignRequest{Token: &resp.NextPageToken}
+	}
+}
+
+func (i *validateCommand) validateCampaignLineItems(omsClient *client.Client) error {
+	req := &client.ListCampaignLineItemRequest{Size: 500}
+
+	for {
+		resp, err := omsClient.ListCampaignLineItems(req)
+		if err != nil {
+			return errors.Wrap(err, "failed to list campaign line items")
+		}
+
+		for _, lineItem := range resp.Items {
+			campaign, err := i.findCampaign(omsClient, lineItem.CampaignID)
+			if err != nil {
+				return err
+			}
+
+			i.report("CampaignLineItem", lineItem.ID, lineItem.ValidateFlight(campaign))
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+
+		req = &client.ListCampaignLineItemRequest{Token: &resp.NextPageToken}
+	}
+}
+
+// findCampaign looks up campaigns the list skipped, such as archived ones.
+func (i *validateCommand) findCampaign(omsClient *client.Client, id int) (*models.Campaign, error) {
+	if campaign, ok := i.campaigns[id]; ok {
+		return campaign, nil
+	}
+
+	campaign, err := omsClient.ShowCampaign(&client.ShowCampaignRequest{ID: id})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to show campaign %d", id)
+	}
+
+	i.campaigns[id] = campaign
+
+	return campaign, nil
+}
+
+func (i *validateCommand) report(resource string, id int, fieldErrors []models.FieldError) {
+	for _, fieldError := range fieldErrors {
+		fmt.Fprintf(i.writer, "%s\t%d\t%s\t%s\n", resource, id, fieldError.Field, fieldError.Message)
+		i.violations++
+	}
+}
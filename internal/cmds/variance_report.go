@@ -0,0 +1,101 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var VarianceReport = &cli.Command{
+	Name:    "variance-report",
+	Aliases: []string{"vr"},
+	Usage:   "List line items or campaigns that under or over delivered against booked",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newVarianceReportCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "threshold",
+			Value: "5%",
+			Usage: "Smallest variance to report, an amount (100) or a percentage of booked (5%)",
+		},
+		&cli.StringFlag{
+			Name:  "groupBy",
+			Value: models.VarianceGroupByLineItem,
+			Usage: "lineItem or campaign",
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Value: models.VarianceSortAbsolute,
+			Usage: "Order by the absolute or the percent variance, largest first",
+		},
+		&cli.StringFlag{
+			Name:  "status",
+			Usage: "Only report under or over delivery",
+		},
+	},
+}
+
+type varianceReportCommand struct {
+	serviceURL string
+}
+
+func newVarianceReportCommand(serviceURL string) *varianceReportCommand {
+	return &varianceReportCommand{serviceURL: serviceURL}
+}
+
+func (i *varianceReportCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	report, err := omsClient.VarianceReport(&client.VarianceReportRequest{
+		Threshold: c.String("threshold"),
+		GroupBy:   c.String("groupBy"),
+		Sort:      c.String("sort"),
+		Status:    c.String("status"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot get variance report")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if report.GroupBy == models.VarianceGroupByCampaign {
+		fmt.Fprintf(w, "CampaignID\tCampaign\tLineItems\tBooked\tActual\tVariance\tVariance%%\tStatus\n")
+	} else {
+		fmt.Fprintf(w, "LineItemID\tLineItem\tCampaignID\tBooked\tActual\tVariance\tVariance%%\tStatus\n")
+	}
+
+	for _, item := range report.Items {
+		percent := "n/a"
+		if item.VariancePercent != nil {
+			percent = fmt.Sprintf("%.2f%%", *item.VariancePercent)
+		}
+
+		if report.GroupBy == models.VarianceGroupByCampaign {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%f\t%f\t%f\t%s\t%s\n", item.CampaignID, item.CampaignName,
+				item.LineItemCount, item.Booked, item.Actual, item.Variance, percent, item.Status)
+		} else {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%f\t%f\t%f\t%s\t%s\n", item.LineItemID, item.LineItemName,
+				item.CampaignID, item.Booked, item.Actual, item.Variance, percent, item.Status)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
+
+	fmt.Printf("\n%d reported\n", len(report.Items))
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package cmds
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// newVersionFlag makes a command change a record only while it is at a version seen
+// earlier. It is required, reading the current version instead would let the change
+// overwrite whatever happened since the record was looked at.
+func newVersionFlag() *cli.IntFlag {
+	return &cli.IntFlag{
+		Name:     "version",
+		Usage:    "Only change the record while it is at this version, as printed by the show command",
+		Required: true,
+	}
+}
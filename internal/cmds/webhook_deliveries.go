@@ -0,0 +1,82 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var WebhookDeliveries = &cli.Command{
+	Name:    "webhook-deliveries",
+	Aliases: []string{"wd"},
+	Usage:   "List the newest webhook deliveries, --status dead lists the dead letters",
+	Action: func(c *cli.Context) error {
+		url := c.String("url")
+		if url == "" {
+			return NewMissingError("url")
+		}
+
+		cmd := newWebhookDeliveriesCommand(url)
+		return cmd.Run(c)
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "Id of the webhook subscription, all subscriptions when it is not set",
+		},
+		&cli.StringFlag{
+			Name:  "status",
+			Usage: "Status of the deliveries: pending, delivered or dead",
+		},
+	},
+}
+
+type webhookDeliveriesCommand struct {
+	serviceURL string
+}
+
+func newWebhookDeliveriesCommand(serviceURL string) *webhookDeliveriesCommand {
+	return &webhookDeliveriesCommand{serviceURL: serviceURL}
+}
+
+func (i *webhookDeliveriesCommand) Run(c *cli.Context) error {
+	omsClient := client.NewClient(i.serviceURL)
+
+	deliveries, err := omsClient.ListWebhookDeliveries(&client.ListWebhookDeliveriesRequest{
+		SubscriptionID: c.Int("id"),
+		Status:         c.String("status"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list webhook deliveries")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tWebhook\tEvent\tStatus\tAttempts\tResponse\tNextAttempt\tLastError\n")
+
+	for _, delivery := range deliveries.Items {
+		response := ""
+		if delivery.ResponseStatus != nil {
+			response = strconv.Itoa(*delivery.ResponseStatus)
+		}
+
+		nextAttempt := ""
+		if delivery.NextAttemptAt != nil {
+			nextAttempt = delivery.NextAttemptAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%s\t%s\t%s\n", delivery.ID, delivery.SubscriptionID,
+			delivery.EventType, delivery.Status, delivery.Attempts, response, nextAttempt, delivery.LastError)
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("Unexpected flush error: %v", err)
+	}
+
+	return nil
+}
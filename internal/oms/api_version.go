@@ -0,0 +1,66 @@
+package oms
+
+import (
+	"strings"
+
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// apiVersion is the version of the API a request was sent to. The handlers are
+// shared, only the wire format of the bodies differs.
+type apiVersion int
+
+const (
+	// apiUnversioned are the routes without a version prefix. Their bodies are what
+	// encoding/json makes of the models, they are kept for the clients that have not
+	// moved to /v1 yet.
+	apiUnversioned apiVersion = iota
+	apiV1
+)
+
+// requestAPIVersion reads the version from the path, so requests that match no
+// route and panics are answered in the format of the version as well.
+func requestAPIVersion(c *gin.Context) apiVersion {
+	if strings.HasPrefix(c.Request.URL.Path, v1.BasePath+"/") {
+		return apiV1
+	}
+
+	return apiUnversioned
+}
+
+// deprecated marks the responses of the unversioned routes as deprecated and
+// links the route that replaces them.
+func deprecated(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", "<"+v1.BasePath+c.Request.URL.Path+`>; rel="successor-version"`)
+	c.Next()
+}
+
+// respond answers the request with body in the wire format of its version.
+func respond(c *gin.Context, status int, body interface{}) {
+	c.JSON(status, encodeBody(c, body))
+}
+
+func encodeBody(c *gin.Context, body interface{}) interface{} {
+	if requestAPIVersion(c) == apiV1 {
+		return v1.Encode(body)
+	}
+
+	return body
+}
+
+// bindJSON decodes the JSON body of the request from the wire format of its
+// version into out.
+func bindJSON(c *gin.Context, out interface{}) error {
+	if requestAPIVersion(c) != apiV1 {
+		return c.ShouldBindJSON(out)
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+
+	return v1.Decode(data, out)
+}
@@ -0,0 +1,100 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+// cloneCampaign copies a campaign and all of its line items in a single transaction.
+func cloneCampaign(ctx context.Context, dbQueries *db.Queries, id int32,
+	req *models.CloneCampaignRequest) (db.OmsCampaign, error) {
+	var clone db.OmsCampaign
+
+	offset := time.Duration(req.OffsetDays) * 24 * time.Hour
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		source, err := q.GetCampaign(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		name := req.Name
+		if name == "" {
+			name = source.Name
+		}
+
+		clone, err = q.CreateCampaign(ctx, db.CreateCampaignParams{
+			Name:      name,
+			StartedAt: shiftSQLTime(source.StartedAt, offset),
+			EndedAt:   shiftSQLTime(source.EndedAt, offset),
+			Archiving: source.Archiving,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot create cloned campaign")
+		}
+
+		lineItems, err := q.ListCampaignLinesForCampaign(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "cannot list line items of source campaign")
+		}
+
+		if err := emitCampaignEvent(ctx, q, models.WebhookEventCampaignCreated, clone.ID); err != nil {
+			return err
+		}
+
+		for i := range lineItems {
+			params := cloneLineItemParams(&lineItems[i], clone.ID, offset, req.ResetActuals)
+
+			lineItemID, err := q.CreateCampaignLine(ctx, params)
+			if err != nil {
+				return errors.Wrapf(err, "cannot clone line item %d", lineItems[i].ID)
+			}
+
+			if err := emitLineItemEvent(ctx, q, models.WebhookEventLineItemCreated, lineItemID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return clone, err
+}
+
+func cloneLineItemParams(item *db.OmsCampaignLineItem, campaignID int32, offset time.Duration,
+	resetActuals bool) db.CreateCampaignLineParams {
+	params := db.CreateCampaignLineParams{
+		CampaignID:     campaignID,
+		Name:           item.Name,
+		Booked:         item.Booked,
+		Actual:         item.Actual,
+		Adjustments:    item.Adjustments,
+		StartedAt:      shiftSQLTime(item.StartedAt, offset),
+		EndedAt:        shiftSQLTime(item.EndedAt, offset),
+		PricingModel:   item.PricingModel,
+		UnitRate:       item.UnitRate,
+		BookedUnits:    item.BookedUnits,
+		DeliveredUnits: item.DeliveredUnits,
+	}
+
+	if resetActuals {
+		params.Actual = sql.NullString{Valid: true, String: "0"}
+		params.Adjustments = sql.NullString{Valid: true, String: "0"}
+		params.DeliveredUnits = 0
+	}
+
+	return params
+}
+
+func shiftSQLTime(t sql.NullTime, offset time.Duration) sql.NullTime {
+	if !t.Valid {
+		return t
+	}
+
+	return sql.NullTime{Valid: true, Time: t.Time.Add(offset)}
+}
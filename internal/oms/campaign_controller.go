@@ -2,219 +2,339 @@ package oms
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
-	"time"
 
 	"github.com/chrisrob11/oms/internal/oms/db"
 	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
 	"github.com/gin-gonic/gin"
 )
 
 type campaignsController struct {
 	dbQueries *db.Queries
 	logger    *slog.Logger
+	tokens    *tokenCodec
 }
 
-func newCampaignsController(logger *slog.Logger, engine *gin.Engine, dbQueries *db.Queries) *campaignsController {
-	controller := &campaignsController{dbQueries: dbQueries, logger: logger}
-	engine.POST("/campaigns", controller.create)
-	engine.GET("/campaigns/:id", controller.get)
-	engine.GET("/campaigns", controller.list)
-	engine.PUT("/campaigns/:id", controller.update)
-	engine.POST("/campaigns/:id/generateInvoice", controller.generateInvoice)
-	engine.DELETE("/campaigns/:id", controller.delete)
+func newCampaignsController(logger *slog.Logger, dbQueries *db.Queries, tokens *tokenCodec) *campaignsController {
+	return &campaignsController{dbQueries: dbQueries, logger: logger, tokens: tokens}
+}
 
-	return controller
+// register adds the routes of the controller to the router, the server registers
+// them once for every version of the API.
+func (s *campaignsController) register(router gin.IRoutes) {
+	router.POST("/campaigns", s.create)
+	router.GET("/campaigns/:id", s.get)
+	router.GET("/campaigns", s.list)
+	router.PUT("/campaigns/:id", s.update)
+	router.PATCH("/campaigns/:id", s.patch)
+	router.POST("/campaigns/:id/generateInvoice", s.generateInvoice)
+	router.POST("/campaigns/:id/clone", s.clone)
+	router.GET("/campaigns/:id/summary", s.summary)
+	router.DELETE("/campaigns/:id", s.delete)
+	router.POST("/campaigns/:id/restore", s.restore)
 }
 
 func (s *campaignsController) create(c *gin.Context) {
 	var req models.Campaign
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
 		return
 	}
 
-	var err error
+	campaign, err := createCampaign(c.Request.Context(), s.logger, s.dbQueries, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
-	var campaign db.OmsCampaign
+	respond(c, http.StatusOK, campaign)
+}
 
-	if req.ID > 0 {
-		s.logger.Info("Creating campaign with id")
+func (s *campaignsController) get(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
 
-		params := req.ToCreateCampaignWithID()
+	includeDeleted, hasError := extractIncludeDeleted(c)
+	if hasError {
+		return
+	}
 
-		campaign, err = s.dbQueries.CreateCampaignWithID(c.Request.Context(), *params)
-		if err == nil {
-			resetErr := s.dbQueries.ResetCampaignID(c.Request.Context())
-			if resetErr != nil {
-				s.logger.Error("Cannot reset the serial id after manual insert")
-			}
-		}
-	} else {
-		s.logger.Info("Creating campaign without id")
-		params := req.ToCreateCampaign()
-		campaign, err = s.dbQueries.CreateCampaign(c.Request.Context(), *params)
+	shape, hasError := extractCampaignShape(c)
+	if hasError {
+		return
+	}
+
+	campaign, err := getCampaign(c.Request.Context(), s.dbQueries, id, includeDeleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
 	}
 
 	if err != nil {
-		s.logger.Error("Error creating campaign", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		s.logger.Error("error occurred getting campaign", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		respondError(c, err)
+
+		return
+	}
 
+	setETag(c, campaign.Version)
+
+	if shape.isFull() {
+		respond(c, http.StatusOK, campaign)
+		return
+	}
+
+	items, err := shape.apply(c.Request.Context(), s.dbQueries, []*models.Campaign{campaign})
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.NewCampaignFromDB(&campaign))
+	respond(c, http.StatusOK, items[0])
 }
 
-func (s *campaignsController) get(c *gin.Context) {
+func (s *campaignsController) summary(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
 		return
 	}
 
-	campaign, err := s.dbQueries.GetCampaign(c.Request.Context(), id)
+	summary, err := getCampaignSummary(c.Request.Context(), s.dbQueries, id)
 	if errors.Is(err, sql.ErrNoRows) {
-		c.JSON(http.StatusNotFound, gin.H{"error": err})
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
 		return
 	}
 
 	if err != nil {
-		s.logger.Error("error occurred getting campaign", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.NewCampaignFromDB(&campaign))
+	respond(c, http.StatusOK, summary)
 }
 
+// list pages through the campaigns that are not archiving, $filter and $orderby
+// narrow and order them while $select and $expand shape every item. A page with
+// $expand holds at most maxExpandedListLimit campaigns.
 func (s *campaignsController) list(c *gin.Context) {
-	params := db.ListCampaignsParams{
-		Limit: 100,
+	req, hasError := extractListRequest(c, s.tokens, campaignListSchema)
+	if hasError {
+		return
 	}
 
-	limitInt, hasError := extractLimit(c)
+	shape, hasError := extractCampaignShape(c)
 	if hasError {
 		return
 	}
 
-	if limitInt != nil {
-		params.Limit = *limitInt
+	if shape.isExpanded() {
+		req.limit = min(req.limit, maxExpandedListLimit)
 	}
 
-	pageInfo, hasError := extractTokenFromQuery(c)
-	if hasError {
+	campaignsResp, err := listCampaigns(c.Request.Context(), s.dbQueries, req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	if pageInfo != nil {
-		s.logger.Info("Token info", slog.Attr{Key: "starting_id", Value: slog.IntValue(pageInfo.StartID)})
-		params.ID = int32(pageInfo.StartID)
-		params.Limit = int32(pageInfo.Size)
+	if shape.isFull() {
+		respond(c, http.StatusOK, campaignsResp)
+		return
 	}
 
-	campaigns, err := s.dbQueries.ListCampaigns(c.Request.Context(), params)
+	items, err := shape.apply(c.Request.Context(), s.dbQueries, campaignsResp.Items)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, &models.List[json.RawMessage]{
+		Items:             items,
+		NextPageToken:     campaignsResp.NextPageToken,
+		PreviousPageToken: campaignsResp.PreviousPageToken,
+		TotalCount:        campaignsResp.TotalCount,
+	})
+}
+
+func (s *campaignsController) update(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
+	if hasError {
 		return
 	}
 
-	numItems := len(campaigns)
-	campaignsResp := &models.List[models.Campaign]{}
-	campaignsResp.Items = make([]*models.Campaign, len(campaigns))
+	var req models.Campaign
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
 
-	for i := 0; i < len(campaigns); i++ {
-		campaignsResp.Items[i] = models.NewCampaignFromDB(&campaigns[i])
+	campaign, err := replaceCampaign(c.Request.Context(), s.dbQueries, id, &req, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
 	}
 
-	if numItems >= int(params.Limit) {
-		token := EncodeToken(PaginationToken{StartID: int(campaigns[numItems-1].ID), Size: int(params.Limit)})
-		campaignsResp.NextPageToken = token
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, campaignsResp)
+	setETag(c, campaign.Version)
+	c.Status(http.StatusOK)
 }
 
-func (s *campaignsController) update(c *gin.Context) {
+// patch changes only the fields present in a JSON merge patch, null clears a field.
+func (s *campaignsController) patch(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
 		return
 	}
 
-	var req models.Campaign
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	match, hasError := requireIfMatch(c)
+	if hasError {
 		return
 	}
 
-	campaignDB := req.ToCreateCampaign()
+	patch, hasError := readMergePatch(c, v1.CampaignNames)
+	if hasError {
+		return
+	}
 
-	// Have the problem where if some parameters are not specified
-	// then empty will overwrite the value. Have another problem
-	// where if empty is the value we don't know to set. There needs
-	// to be more changes to fix these bugs.
-	update := db.UpdateCampaignParams{
-		Name:      campaignDB.Name,
-		StartedAt: campaignDB.StartedAt,
-		EndedAt:   campaignDB.EndedAt,
-		Archiving: campaignDB.Archiving,
-		ID:        id,
+	campaign, err := patchCampaign(c.Request.Context(), s.dbQueries, id, patch, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
 	}
 
-	if err := s.dbQueries.UpdateCampaign(c.Request.Context(), update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	setETag(c, campaign.Version)
+	respond(c, http.StatusOK, campaign)
 }
 
 func (s *campaignsController) delete(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	mode := c.DefaultQuery("mode", deleteModeRestrict)
+	if mode != deleteModeRestrict && mode != deleteModeCascade {
+		respondError(c, errInvalidDeleteMode)
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
+	}
+
+	err = deleteCampaign(c.Request.Context(), s.dbQueries, id, mode, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
 		return
 	}
 
-	err = s.dbQueries.DeleteCampaign(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
 
+func (s *campaignsController) restore(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	err = restoreCampaign(c.Request.Context(), s.dbQueries, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, int(id))
+}
+
 func (s *campaignsController) generateInvoice(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
 		return
 	}
 
-	s.logger.Info("Building Campaign Invoice")
-	invoice, err := s.dbQueries.BuildCampaignInvoice(c.Request.Context(), db.BuildCampaignInvoiceParams{
-		CampaignID: id,
-	})
+	s.logger.Info("Generating Campaign Invoice")
+
+	createdID, err := generateCampaignInvoice(c.Request.Context(), s.dbQueries, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
+	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	s.logger.Info("Creating the Invoice")
+	respond(c, http.StatusOK, createdID)
+}
+
+func (s *campaignsController) clone(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	req := models.CloneCampaignRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := bindJSON(c, &req); err != nil {
+			respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+			return
+		}
+	}
 
-	invoice.IssuedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	s.logger.Info("Cloning campaign", slog.Attr{Key: "campaign_id", Value: slog.IntValue(int(id))})
+
+	campaign, err := cloneCampaign(c.Request.Context(), s.dbQueries, id, &req)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
+	}
 
-	createdID, err := s.dbQueries.CreateInvoice(c.Request.Context(), invoice)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		s.logger.Error("Error cloning campaign", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		respondError(c, err)
+
 		return
 	}
 
-	c.JSON(http.StatusOK, createdID)
+	respond(c, http.StatusOK, models.NewCampaignFromDB(&campaign))
 }
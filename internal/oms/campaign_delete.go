@@ -0,0 +1,95 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	deleteModeRestrict = "restrict"
+	deleteModeCascade  = "cascade"
+)
+
+var errInvalidDeleteMode = fmt.Errorf("mode must be %q or %q", deleteModeRestrict, deleteModeCascade)
+
+// campaignDependentsError is returned when a campaign cannot be deleted
+// because other records still reference it.
+type campaignDependentsError struct {
+	reason     string
+	dependents *models.CampaignDependents
+}
+
+func (e *campaignDependentsError) Error() string {
+	return fmt.Sprintf("%s (line items: %d, draft invoices: %d, issued invoices: %d)", e.reason,
+		e.dependents.LineItems, e.dependents.DraftInvoices, e.dependents.IssuedInvoices)
+}
+
+// deleteCampaign moves a campaign to the trash. In restrict mode any line items or
+// invoices block the deletion, in cascade mode line items and draft invoices are
+// trashed with the campaign. Issued invoices always block the deletion.
+func deleteCampaign(ctx context.Context, dbQueries *db.Queries, id int32, mode string, match *ifMatch) error {
+	// Everything trashed by a cascade shares one timestamp so a restore of the
+	// campaign can bring back exactly those rows.
+	deletedAt := sql.NullTime{Valid: true, Time: time.Now().UTC()}
+
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		// Locking the campaign row stops line items and invoices from being
+		// attached to it while the dependents are counted and removed.
+		campaign, err := q.GetCampaignForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(campaign.Version); err != nil {
+			return err
+		}
+
+		counts, err := q.CountCampaignDependents(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "cannot count campaign dependents")
+		}
+
+		dependents := models.NewCampaignDependentsFromDB(&counts)
+
+		if dependents.IssuedInvoices > 0 {
+			return &campaignDependentsError{reason: "campaign has issued invoices", dependents: dependents}
+		}
+
+		if mode != deleteModeCascade && (dependents.LineItems > 0 || dependents.DraftInvoices > 0) {
+			return &campaignDependentsError{
+				reason:     "campaign has line items or draft invoices, delete with mode=cascade to remove them",
+				dependents: dependents,
+			}
+		}
+
+		err = q.SoftDeleteDraftInvoicesForCampaign(ctx, db.SoftDeleteDraftInvoicesForCampaignParams{
+			CampaignID: id,
+			DeletedAt:  deletedAt,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot delete draft invoices")
+		}
+
+		err = q.SoftDeleteCampaignLinesForCampaign(ctx, db.SoftDeleteCampaignLinesForCampaignParams{
+			CampaignID: id,
+			DeletedAt:  deletedAt,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot delete line items")
+		}
+
+		err = q.SoftDeleteCampaign(ctx, db.SoftDeleteCampaignParams{ID: id, DeletedAt: deletedAt})
+		if err != nil {
+			return errors.Wrap(err, "cannot delete campaign")
+		}
+
+		// The line items and invoices trashed with the campaign are part of its event.
+		return emitCampaignEvent(ctx, q, models.WebhookEventCampaignDeleted, id)
+	})
+}
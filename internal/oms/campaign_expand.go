@@ -0,0 +1,286 @@
+package oms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	selectQueryParamName = "$select"
+	expandQueryParamName = "$expand"
+	// maxExpandedListLimit caps a page of campaigns with $expand, every campaign of
+	// the page brings its relations along.
+	maxExpandedListLimit = 20
+	// maxExpandedRelationItems caps an expanded relation of one campaign, the rest
+	// is paged through /campaigns/{id}/lineItems and /invoices.
+	maxExpandedRelationItems = 100
+)
+
+// campaignWire is how a version of the API names the fields and relations of a
+// campaign in $select, $expand and the response.
+type campaignWire struct {
+	// fields maps the lower case name of every field to its key in the response,
+	// $select names fields without regard to case.
+	fields     map[string]string
+	fieldNames []string
+	// lineItems and invoices are the names of the relations in $expand, their keys
+	// in the response are lineItemsKey and invoicesKey.
+	lineItems, invoices       string
+	lineItemsKey, invoicesKey string
+	encode                    func(*models.ExpandedCampaign) interface{}
+}
+
+var campaignWires = map[apiVersion]*campaignWire{
+	apiUnversioned: newCampaignWire(reflect.TypeOf(models.Campaign{}), false, "lineItems", "invoices",
+		"LineItems", "Invoices", func(c *models.ExpandedCampaign) interface{} { return c }),
+	apiV1: newCampaignWire(reflect.TypeOf(v1.Campaign{}), true, "line_items", "invoices",
+		"line_items", "invoices", func(c *models.ExpandedCampaign) interface{} { return v1.NewExpandedCampaign(c) }),
+}
+
+func newCampaignWire(t reflect.Type, byKey bool, lineItems, invoices, lineItemsKey, invoicesKey string,
+	encode func(*models.ExpandedCampaign) interface{}) *campaignWire {
+	fields, fieldNames := jsonFieldKeys(t, byKey)
+
+	return &campaignWire{
+		fields:       fields,
+		fieldNames:   fieldNames,
+		lineItems:    lineItems,
+		invoices:     invoices,
+		lineItemsKey: lineItemsKey,
+		invoicesKey:  invoicesKey,
+		encode:       encode,
+	}
+}
+
+// campaignShape is the $select and $expand of a campaign request. A nil keys
+// returns every field, the ID is always returned.
+type campaignShape struct {
+	wire      *campaignWire
+	keys      []string
+	lineItems bool
+	invoices  bool
+}
+
+func extractCampaignShape(c *gin.Context) (*campaignShape, bool) {
+	wire := campaignWires[requestAPIVersion(c)]
+	shape := &campaignShape{wire: wire}
+
+	if selected, ok := c.GetQuery(selectQueryParamName); ok {
+		shape.keys = []string{wire.fields["id"]}
+
+		for _, name := range strings.Split(selected, ",") {
+			key, ok := wire.fields[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				respondProblem(c, http.StatusBadRequest, models.ProblemInvalidQuery,
+					fmt.Sprintf("unknown field %q in $select, expected one of %s",
+						strings.TrimSpace(name), strings.Join(wire.fieldNames, ", ")))
+
+				return nil, true
+			}
+
+			shape.keys = append(shape.keys, key)
+		}
+	}
+
+	if expanded, ok := c.GetQuery(expandQueryParamName); ok {
+		for _, name := range strings.Split(expanded, ",") {
+			switch name = strings.TrimSpace(name); {
+			case strings.EqualFold(name, wire.lineItems):
+				shape.lineItems = true
+			case strings.EqualFold(name, wire.invoices):
+				shape.invoices = true
+			default:
+				respondProblem(c, http.StatusBadRequest, models.ProblemInvalidQuery,
+					fmt.Sprintf("unknown relation %q in $expand, expected %s or %s", name, wire.lineItems, wire.invoices))
+
+				return nil, true
+			}
+		}
+	}
+
+	return shape, false
+}
+
+// isExpanded reports whether the request asked for a relation of the campaign.
+func (s *campaignShape) isExpanded() bool {
+	return s.lineItems || s.invoices
+}
+
+// isFull reports whether the request asked for the campaign as it is stored.
+func (s *campaignShape) isFull() bool {
+	return s.keys == nil && !s.isExpanded()
+}
+
+// apply loads the expanded relations of all the campaigns with one query per
+// relation and trims every campaign to the selected fields. An expanded relation
+// holds the first maxExpandedRelationItems records of the campaign by id.
+func (s *campaignShape) apply(ctx context.Context, dbQueries *db.Queries,
+	campaigns []*models.Campaign) ([]*json.RawMessage, error) {
+	ids := make([]int32, len(campaigns))
+	expanded := make([]*models.ExpandedCampaign, len(campaigns))
+	byID := make(map[int]*models.ExpandedCampaign, len(campaigns))
+
+	for i, campaign := range campaigns {
+		ids[i] = int32(campaign.ID)
+		expanded[i] = &models.ExpandedCampaign{Campaign: *campaign}
+		byID[campaign.ID] = expanded[i]
+	}
+
+	if s.lineItems {
+		if err := expandCampaignLineItems(ctx, dbQueries, ids, byID); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.invoices {
+		if err := expandCampaignInvoices(ctx, dbQueries, ids, byID); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]*json.RawMessage, len(expanded))
+
+	for i, campaign := range expanded {
+		item, err := s.project(campaign)
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = &item
+	}
+
+	return items, nil
+}
+
+func expandCampaignLineItems(ctx context.Context, dbQueries *db.Queries, ids []int32,
+	byID map[int]*models.ExpandedCampaign) error {
+	for _, campaign := range byID {
+		campaign.LineItems = []*models.CampaignLineItem{}
+	}
+
+	lines, err := dbQueries.ListCampaignLinesForCampaigns(ctx, db.ListCampaignLinesForCampaignsParams{
+		CampaignIds:    ids,
+		MaxPerCampaign: maxExpandedRelationItems,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range lines {
+		line, err := models.NewCampaignLineItemFromDB(&lines[i])
+		if err != nil {
+			return err
+		}
+
+		campaign := byID[line.CampaignID]
+		campaign.LineItems = append(campaign.LineItems, line)
+	}
+
+	return nil
+}
+
+func expandCampaignInvoices(ctx context.Context, dbQueries *db.Queries, ids []int32,
+	byID map[int]*models.ExpandedCampaign) error {
+	for _, campaign := range byID {
+		campaign.Invoices = []*models.Invoice{}
+	}
+
+	invoices, err := dbQueries.ListInvoicesForCampaigns(ctx, db.ListInvoicesForCampaignsParams{
+		CampaignIds:    ids,
+		MaxPerCampaign: maxExpandedRelationItems,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range invoices {
+		invoice, err := models.NewInvoiceFromDB(invoices[i])
+		if err != nil {
+			return err
+		}
+
+		campaign := byID[invoice.CampaignID]
+		campaign.Invoices = append(campaign.Invoices, invoice)
+	}
+
+	return nil
+}
+
+// project keeps the selected fields and the expanded relations of a campaign in the
+// wire format of the request.
+func (s *campaignShape) project(campaign *models.ExpandedCampaign) (json.RawMessage, error) {
+	data, err := json.Marshal(s.wire.encode(campaign))
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	out := all
+
+	if s.keys != nil {
+		out = make(map[string]json.RawMessage, len(s.keys)+2)
+		for _, key := range s.keys {
+			out[key] = all[key]
+		}
+
+		out[s.wire.lineItemsKey], out[s.wire.invoicesKey] = all[s.wire.lineItemsKey], all[s.wire.invoicesKey]
+	}
+
+	if !s.lineItems {
+		delete(out, s.wire.lineItemsKey)
+	}
+
+	if !s.invoices {
+		delete(out, s.wire.invoicesKey)
+	}
+
+	return json.Marshal(out)
+}
+
+// jsonFieldKeys returns the response key of every exported field of t by lower
+// case field name, and the field names in declaration order. With byKey the fields
+// are named by their response keys instead, as the DTOs of /v1 are.
+func jsonFieldKeys(t reflect.Type, byKey bool) (map[string]string, []string) {
+	keys := map[string]string{}
+
+	var names []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "-" {
+			continue
+		}
+
+		if key == "" {
+			key = field.Name
+		}
+
+		name := field.Name
+		if byKey {
+			name = key
+		}
+
+		keys[strings.ToLower(name)] = key
+		names = append(names, name)
+	}
+
+	return keys, names
+}
@@ -0,0 +1,129 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+const maxBatchOperations = 1000
+
+var errBatchFailed = errors.New("batch failed, no operations were applied")
+
+// runCampaignLineBatch applies the operations in order in a single transaction.
+// Operations keep running after one is rejected so every problem is reported,
+// but any rejected operation rolls the whole batch back.
+func runCampaignLineBatch(ctx context.Context, dbQueries *db.Queries,
+	operations []*models.CampaignLineItemOperation) (*models.CampaignLineItemBatchResult, error) {
+	if len(operations) > maxBatchOperations {
+		return nil, newValidationError([]models.FieldError{{
+			Field:   "Operations",
+			Message: fmt.Sprintf("at most %d operations can be sent at once", maxBatchOperations),
+		}})
+	}
+
+	result := &models.CampaignLineItemBatchResult{
+		Results: make([]*models.CampaignLineItemOperationResult, len(operations)),
+	}
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		failed := false
+
+		for i, operation := range operations {
+			opResult, err := runCampaignLineOperation(ctx, q, i, operation)
+			if err != nil {
+				return err
+			}
+
+			result.Results[i] = opResult
+			failed = failed || opResult.Status != http.StatusOK
+		}
+
+		if failed {
+			return errBatchFailed
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// runCampaignLineOperation applies a single operation, rejected operations are
+// reported in the result while database failures abort the batch.
+func runCampaignLineOperation(ctx context.Context, dbQueries *db.Queries, index int,
+	operation *models.CampaignLineItemOperation) (*models.CampaignLineItemOperationResult, error) {
+	result := &models.CampaignLineItemOperationResult{
+		Index:  index,
+		Op:     operation.Op,
+		ID:     operation.ID,
+		Status: http.StatusOK,
+	}
+
+	var (
+		match *ifMatch
+		err   error
+	)
+
+	if operation.Op == models.BatchOperationUpdate || operation.Op == models.BatchOperationDelete {
+		match, err = versionMatch(int32(operation.Version))
+	}
+
+	switch {
+	case err != nil:
+	case operation.Op == models.BatchOperationDelete:
+		err = deleteCampaignLine(ctx, dbQueries, int32(operation.ID), match)
+	case operation.LineItem == nil &&
+		(operation.Op == models.BatchOperationCreate || operation.Op == models.BatchOperationUpdate):
+		err = newValidationError([]models.FieldError{{Field: "LineItem", Message: "is required"}})
+	case operation.Op == models.BatchOperationCreate:
+		var id int32
+		id, err = createCampaignLine(ctx, dbQueries, operation.LineItem)
+		result.ID = int(id)
+	case operation.Op == models.BatchOperationUpdate:
+		err = updateCampaignLine(ctx, dbQueries, int32(operation.ID), operation.LineItem, match)
+	default:
+		err = newValidationError([]models.FieldError{{Field: "Op", Message: "must be create, update or delete"}})
+	}
+
+	return operationResult(result, err)
+}
+
+// operationResult reports a rejected operation in its result and returns the
+// errors that abort the batch.
+func operationResult(result *models.CampaignLineItemOperationResult,
+	err error) (*models.CampaignLineItemOperationResult, error) {
+	var (
+		vErr        *validationError
+		mismatchErr *versionMismatchError
+	)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, sql.ErrNoRows):
+		result.Status = http.StatusNotFound
+		result.Error = "campaign line item not found"
+	case errors.Is(err, errLineItemLocked):
+		result.Status = http.StatusConflict
+		result.Error = err.Error()
+	case errors.Is(err, errVersionRequired):
+		result.Status = http.StatusPreconditionRequired
+		result.Error = err.Error()
+	case errors.As(err, &mismatchErr):
+		result.Status = http.StatusPreconditionFailed
+		result.Error = err.Error()
+	case errors.As(err, &vErr):
+		result.Status = http.StatusBadRequest
+		result.Error = "validation failed"
+		result.Fields = vErr.fields
+	default:
+		return nil, errors.Wrapf(err, "operation %d failed", result.Index)
+	}
+
+	return result, nil
+}
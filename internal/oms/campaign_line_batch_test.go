@@ -0,0 +1,38 @@
+package oms
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCampaignLineOperationRequiresVersion(t *testing.T) {
+	for _, op := range []string{models.BatchOperationUpdate, models.BatchOperationDelete} {
+		t.Run(op, func(t *testing.T) {
+			// The version is checked before the database is used, so there is none.
+			result, err := runCampaignLineOperation(context.Background(), nil, 2, &models.CampaignLineItemOperation{
+				Op:       op,
+				ID:       5,
+				LineItem: &models.CampaignLineItem{Name: "x"},
+			})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusPreconditionRequired, result.Status)
+			require.Equal(t, errVersionRequired.Error(), result.Error)
+		})
+	}
+}
+
+func TestOperationResult(t *testing.T) {
+	result, err := operationResult(&models.CampaignLineItemOperationResult{Status: http.StatusOK},
+		&versionMismatchError{current: 4})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPreconditionFailed, result.Status)
+	require.Contains(t, result.Error, "its current version is 4")
+
+	result, err = operationResult(&models.CampaignLineItemOperationResult{Status: http.StatusOK}, errLineItemLocked)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, result.Status)
+}
@@ -0,0 +1,264 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+// campaignLinePatchFields are the line item fields a merge patch may change.
+var campaignLinePatchFields = patchableFields{
+	"Name":           false,
+	"Booked":         false,
+	"Actual":         false,
+	"Adjustments":    false,
+	"StartedAt":      true,
+	"EndedAt":        true,
+	"PricingModel":   false,
+	"UnitRate":       false,
+	"BookedUnits":    false,
+	"DeliveredUnits": false,
+}
+
+// createCampaignLine prices, validates and stores a new line item. A line item
+// created with its own id moves the id sequence past it. Run it in a transaction,
+// it records the line_item.created event.
+func createCampaignLine(ctx context.Context, dbQueries *db.Queries, item *models.CampaignLineItem) (int32, error) {
+	if err := newValidationError(item.ApplyPricing()); err != nil {
+		return 0, err
+	}
+
+	// A campaign that does not exist at all is reported by the flight validation.
+	err := ensureCampaignNotDeleted(ctx, dbQueries, int32(item.CampaignID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	if err := validateCampaignLineFlight(ctx, dbQueries, item); err != nil {
+		return 0, err
+	}
+
+	var id int32
+
+	if item.ID <= 0 {
+		id, err = dbQueries.CreateCampaignLine(ctx, item.ToCreateCampaignLineItem())
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		id, err = dbQueries.CreateCampaignLineWithID(ctx, item.ToCreateCampaignLineItemWithID())
+		if err != nil {
+			return 0, err
+		}
+
+		if err := dbQueries.ResetCampaignLineItemID(ctx); err != nil {
+			return 0, errors.Wrap(err, "resetting the serial id for campaign line item failed")
+		}
+	}
+
+	return id, emitLineItemEvent(ctx, dbQueries, models.WebhookEventLineItemCreated, id)
+}
+
+// updateCampaignLine replaces the fields of an unlocked line item, its campaign cannot be changed.
+// Run it in a transaction so the line item stays locked from the version check to the update.
+func updateCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32, item *models.CampaignLineItem,
+	match *ifMatch) error {
+	existing, err := dbQueries.GetCampaignLineForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := match.check(existing.Version); err != nil {
+		return err
+	}
+
+	if err := ensureCampaignLineUnlocked(&existing); err != nil {
+		return err
+	}
+
+	item.CampaignID = int(existing.CampaignID)
+
+	if err := newValidationError(item.ApplyPricing()); err != nil {
+		return err
+	}
+
+	if err := validateCampaignLineFlight(ctx, dbQueries, item); err != nil {
+		return err
+	}
+
+	col := item.ToCreateCampaignLineItem()
+
+	err = dbQueries.UpdateCampaignLine(ctx, db.UpdateCampaignLineParams{
+		ID:             id,
+		Name:           item.Name,
+		Booked:         col.Booked,
+		Actual:         col.Actual,
+		Adjustments:    col.Adjustments,
+		StartedAt:      col.StartedAt,
+		EndedAt:        col.EndedAt,
+		PricingModel:   col.PricingModel,
+		UnitRate:       col.UnitRate,
+		BookedUnits:    col.BookedUnits,
+		DeliveredUnits: col.DeliveredUnits,
+	})
+	if err != nil {
+		return err
+	}
+
+	return emitLineItemEvent(ctx, dbQueries, models.WebhookEventLineItemUpdated, id)
+}
+
+// replaceCampaignLine updates a line item in a transaction and returns the updated line item.
+func replaceCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32, item *models.CampaignLineItem,
+	match *ifMatch) (*models.CampaignLineItem, error) {
+	var updated db.OmsCampaignLineItem
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := updateCampaignLine(ctx, q, id, item, match); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = q.GetCampaignLine(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignLineItemFromDB(&updated)
+}
+
+// patchCampaignLine applies a merge patch to an unlocked line item and returns the patched line item.
+func patchCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32, patch map[string]interface{},
+	match *ifMatch) (*models.CampaignLineItem, error) {
+	var campaignLine db.OmsCampaignLineItem
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetCampaignLineForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		current, err := models.NewCampaignLineItemFromDB(&existing)
+		if err != nil {
+			return err
+		}
+
+		var patched models.CampaignLineItem
+		if err := applyMergePatch(current, patch, campaignLinePatchFields, &patched); err != nil {
+			return err
+		}
+
+		// The amounts of a priced line item follow its units unless the patch sets them.
+		if patched.PricingModel != models.PricingModelFlat {
+			if _, ok := patch["Booked"]; !ok {
+				patched.Booked = 0
+			}
+
+			if _, ok := patch["Actual"]; !ok {
+				patched.Actual = 0
+			}
+		}
+
+		if err := updateCampaignLine(ctx, q, id, &patched, match); err != nil {
+			return err
+		}
+
+		campaignLine, err = q.GetCampaignLine(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignLineItemFromDB(&campaignLine)
+}
+
+// deleteCampaignLine moves an unlocked line item to the trash. Run it in a
+// transaction, it records the line_item.deleted event.
+func deleteCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32, match *ifMatch) error {
+	campaignLine, err := dbQueries.GetCampaignLineForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := match.check(campaignLine.Version); err != nil {
+		return err
+	}
+
+	if err := ensureCampaignLineUnlocked(&campaignLine); err != nil {
+		return err
+	}
+
+	err = dbQueries.SoftDeleteCampaignLine(ctx, db.SoftDeleteCampaignLineParams{
+		ID:        id,
+		DeletedAt: sql.NullTime{Valid: true, Time: time.Now().UTC()},
+	})
+	if err != nil {
+		return err
+	}
+
+	return emitLineItemEvent(ctx, dbQueries, models.WebhookEventLineItemDeleted, id)
+}
+
+// getCampaignLine returns a line item, a deleted line item only when includeDeleted is set.
+func getCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32,
+	includeDeleted bool) (*models.CampaignLineItem, error) {
+	get := dbQueries.GetCampaignLine
+	if includeDeleted {
+		get = dbQueries.GetCampaignLineWithDeleted
+	}
+
+	campaignLine, err := get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignLineItemFromDB(&campaignLine)
+}
+
+// listCampaignLines returns a page of the line items of a campaign, or of every
+// campaign when campaignID is 0.
+func listCampaignLines(ctx context.Context, dbQueries *db.Queries, req *listRequest,
+	campaignID int32) (*models.List[models.CampaignLineItem], error) {
+	if campaignID != 0 {
+		if _, err := getCampaign(ctx, dbQueries, campaignID, req.includeDeleted); err != nil {
+			return nil, err
+		}
+	}
+
+	return pageCampaignLines(ctx, dbQueries, req, campaignID)
+}
+
+// pageCampaignLines returns a page of the line items of a campaign the caller
+// already found.
+func pageCampaignLines(ctx context.Context, dbQueries *db.Queries, req *listRequest,
+	campaignID int32) (*models.List[models.CampaignLineItem], error) {
+	base, baseArgs := campaignLineListBase(req, campaignID)
+
+	return listPage(ctx, req, dbQueries.QueryCampaignLineItems, dbQueries.CountCampaignLineItems,
+		models.NewCampaignLineItemFromDB, func(l *models.CampaignLineItem) int { return l.ID }, base, baseArgs...)
+}
+
+// countCampaignLines counts the line items of the list across all pages.
+func countCampaignLines(ctx context.Context, dbQueries *db.Queries, req *listRequest,
+	campaignID int32) (*int64, error) {
+	base, baseArgs := campaignLineListBase(req, campaignID)
+
+	return req.countAll(ctx, dbQueries.CountCampaignLineItems, base, baseArgs...)
+}
+
+func campaignLineListBase(req *listRequest, campaignID int32) (string, []interface{}) {
+	if campaignID == 0 {
+		return "($1::boolean OR deleted_at IS NULL)", []interface{}{req.includeDeleted}
+	}
+
+	return "campaign_id = $1 AND ($2::boolean OR deleted_at IS NULL)", []interface{}{campaignID, req.includeDeleted}
+}
@@ -9,186 +9,243 @@ import (
 
 	"github.com/chrisrob11/oms/internal/oms/db"
 	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
 	"github.com/gin-gonic/gin"
 )
 
 type campaignLineItemsController struct {
 	dbQueries *db.Queries
 	logger    *slog.Logger
+	tokens    *tokenCodec
 }
 
-func newCampaignLineItemsController(logger *slog.Logger, engine *gin.Engine,
-	dbQueries *db.Queries) *campaignLineItemsController {
-	controller := &campaignLineItemsController{dbQueries: dbQueries, logger: logger}
-	engine.POST("/campaignLineItems", controller.create)
-	engine.GET("/campaignLineItems", controller.list)
-	engine.GET("/campaignLineItems/:id", controller.get)
-	engine.PUT("/campaignLineItems/:id", controller.update)
-	engine.DELETE("/campaignLineItems/:id", controller.delete)
+func newCampaignLineItemsController(logger *slog.Logger, dbQueries *db.Queries,
+	tokens *tokenCodec) *campaignLineItemsController {
+	return &campaignLineItemsController{dbQueries: dbQueries, logger: logger, tokens: tokens}
+}
 
-	return controller
+// register adds the line item routes, including the line items of a campaign.
+func (s *campaignLineItemsController) register(router gin.IRoutes) {
+	router.POST("/campaignLineItems", s.create)
+	router.POST("/campaignLineItems/batch", s.batch)
+	router.GET("/campaignLineItems", s.list)
+	router.GET("/campaignLineItems/:id", s.get)
+	router.PUT("/campaignLineItems/:id", s.update)
+	router.PATCH("/campaignLineItems/:id", s.patch)
+	router.DELETE("/campaignLineItems/:id", s.delete)
+	router.POST("/campaignLineItems/:id/restore", s.restore)
+	router.POST("/campaignLineItems/:id/move", s.move)
+	router.POST("/campaignLineItems/:id/unlock", s.unlock)
+	router.GET("/campaignLineItems/:id/history", s.history)
+	router.GET("/campaigns/:id/lineItems", s.listForCampaign)
 }
 
 func (s *campaignLineItemsController) create(c *gin.Context) {
 	var modelReq models.CampaignLineItem
-	if err := c.BindJSON(&modelReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &modelReq); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
 		return
 	}
 
 	var campaignLineID int32
 
-	var err error
-
-	if modelReq.ID > 0 {
-		req := modelReq.ToCreateCampaignLineItemWithID()
+	err := s.dbQueries.ExecTx(c.Request.Context(), func(q *db.Queries) error {
+		var err error
+		campaignLineID, err = createCampaignLine(c.Request.Context(), q, &modelReq)
 
-		campaignLineID, err = s.dbQueries.CreateCampaignLineWithID(c.Request.Context(), req)
-		if err == nil {
-			resetErr := s.dbQueries.ResetCampaignLineItemID(c.Request.Context())
-			if resetErr != nil {
-				s.logger.Error("resetting the serial id for campaign line item failed")
-			}
-		}
-	} else {
-		req := modelReq.ToCreateCampaignLineItem()
-		campaignLineID, err = s.dbQueries.CreateCampaignLine(c.Request.Context(), req)
-	}
+		return err
+	})
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, campaignLineID)
+	respond(c, http.StatusOK, campaignLineID)
 }
 
 func (s *campaignLineItemsController) get(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
 		return
 	}
 
-	campaignLine, err := s.dbQueries.GetCampaignLine(c.Request.Context(), id)
-	if errors.Is(err, sql.ErrNoRows) {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	includeDeleted, hasError := extractIncludeDeleted(c)
+	if hasError {
 		return
 	}
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	clm, err := getCampaignLine(c.Request.Context(), s.dbQueries, id, includeDeleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
 		return
 	}
 
-	clm, err := models.NewCampaignLineItemFromDB(&campaignLine)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, clm)
+	setETag(c, clm.Version)
+	respond(c, http.StatusOK, clm)
 }
 
 func (s *campaignLineItemsController) list(c *gin.Context) {
-	params := db.ListCampaignLineItemsParams{
-		Limit: 100,
+	req, hasError := extractListRequest(c, s.tokens, campaignLineItemListSchema)
+	if hasError {
+		return
 	}
 
-	limitInt, hasError := extractLimit(c)
+	s.respondList(c, req, 0)
+}
+
+// listForCampaign pages through the line items of a single campaign.
+func (s *campaignLineItemsController) listForCampaign(c *gin.Context) {
+	campaignID, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	req, hasError := extractListRequest(c, s.tokens, campaignLineItemListSchema)
 	if hasError {
 		return
 	}
 
-	if limitInt != nil {
-		params.Limit = *limitInt
+	s.respondList(c, req, campaignID)
+}
+
+// respondList writes a page of the line items of a campaign, or of every campaign
+// when campaignID is 0.
+func (s *campaignLineItemsController) respondList(c *gin.Context, req *listRequest, campaignID int32) {
+	campaignLineItemsResp, err := listCampaignLines(c.Request.Context(), s.dbQueries, req, campaignID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	pageInfo, hasError := extractTokenFromQuery(c)
+	respond(c, http.StatusOK, campaignLineItemsResp)
+}
+
+func (s *campaignLineItemsController) update(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
 	if hasError {
 		return
 	}
 
-	if pageInfo != nil {
-		s.logger.Info("Token info", slog.Attr{Key: "starting_id", Value: slog.IntValue(pageInfo.StartID)})
-		params.ID = int32(pageInfo.StartID)
-		params.Limit = int32(pageInfo.Size)
+	var req models.CampaignLineItem
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	updated, err := replaceCampaignLine(c.Request.Context(), s.dbQueries, id, &req, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
 	}
 
-	campaignLineItems, err := s.dbQueries.ListCampaignLineItems(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	numItems := len(campaignLineItems)
-	campaignLineItemsResp := &models.List[models.CampaignLineItem]{}
-	campaignLineItemsResp.Items = make([]*models.CampaignLineItem, numItems)
+	setETag(c, updated.Version)
+	c.Status(http.StatusOK)
+}
+
+// patch changes only the fields present in a JSON merge patch, null clears a field.
+func (s *campaignLineItemsController) patch(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
 
-	for i := 0; i < numItems; i++ {
-		v, err := models.NewCampaignLineItemFromDB(&campaignLineItems[i])
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
+	}
 
-		campaignLineItemsResp.Items[i] = v
+	patch, hasError := readMergePatch(c, v1.CampaignLineItemNames)
+	if hasError {
+		return
 	}
 
-	if numItems >= int(params.Limit) {
-		token := EncodeToken(PaginationToken{StartID: int(campaignLineItems[numItems-1].ID), Size: int(params.Limit)})
-		campaignLineItemsResp.NextPageToken = token
+	campaignLine, err := patchCampaignLine(c.Request.Context(), s.dbQueries, id, patch, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
 	}
 
-	c.JSON(http.StatusOK, campaignLineItemsResp)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, campaignLine.Version)
+	respond(c, http.StatusOK, campaignLine)
 }
 
-func (s *campaignLineItemsController) update(c *gin.Context) {
+func (s *campaignLineItemsController) delete(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
 		return
 	}
 
-	var req models.CampaignLineItem
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	match, hasError := requireIfMatch(c)
+	if hasError {
 		return
 	}
 
-	col := req.ToCreateCampaignLineItem()
-	update := db.UpdateCampaignLineParams{
-		ID:          id,
-		Name:        req.Name,
-		Booked:      col.Booked,
-		Actual:      col.Actual,
-		Adjustments: col.Adjustments,
-		StartedAt:   col.StartedAt,
-		EndedAt:     col.EndedAt,
+	err = s.dbQueries.ExecTx(c.Request.Context(), func(q *db.Queries) error {
+		return deleteCampaignLine(c.Request.Context(), q, id, match)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
 	}
 
-	if err := s.dbQueries.UpdateCampaignLine(c.Request.Context(), update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
 
-func (s *campaignLineItemsController) delete(c *gin.Context) {
+func (s *campaignLineItemsController) restore(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	err = restoreCampaignLine(c.Request.Context(), s.dbQueries, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
 		return
 	}
 
-	err = s.dbQueries.DeleteCampaignLine(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	respond(c, http.StatusOK, int(id))
 }
 
 func toInt32(v string) (int32, error) {
@@ -199,3 +256,116 @@ func toInt32(v string) (int32, error) {
 
 	return int32(id64), nil
 }
+
+func (s *campaignLineItemsController) batch(c *gin.Context) {
+	var req []*models.CampaignLineItemOperation
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	result, err := runCampaignLineBatch(c.Request.Context(), s.dbQueries, req)
+
+	if errors.Is(err, errBatchFailed) {
+		problem := problemFor(err)
+		problem.Results = result.Results
+		writeProblem(c, problem)
+
+		return
+	}
+
+	if err != nil {
+		s.logger.Error("Error running line item batch", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		respondError(c, err)
+
+		return
+	}
+
+	respond(c, http.StatusOK, result)
+}
+
+func (s *campaignLineItemsController) move(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	var req models.MoveCampaignLineItemRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	err = moveCampaignLine(c.Request.Context(), s.dbQueries, id, &req)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, int(id))
+}
+
+func (s *campaignLineItemsController) history(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	_, err = s.dbQueries.GetCampaignLineWithDeleted(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entries, err := s.dbQueries.ListLineItemHistory(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	historyResp := &models.List[models.LineItemHistoryEntry]{Items: make([]*models.LineItemHistoryEntry, len(entries))}
+	for i := range entries {
+		historyResp.Items[i] = models.NewLineItemHistoryEntryFromDB(&entries[i])
+	}
+
+	respond(c, http.StatusOK, historyResp)
+}
+
+func (s *campaignLineItemsController) unlock(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	var req models.UnlockCampaignLineItemRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	err = unlockCampaignLine(c.Request.Context(), s.dbQueries, id, &req)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, int(id))
+}
@@ -0,0 +1,62 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+var errLineItemLocked = errors.New(
+	"the line item is locked because an issued invoice bills it, unlock it with a reason before changing it")
+
+var errLineItemNotLocked = newAPIError(http.StatusConflict, models.ProblemInvalidState,
+	"the line item is not locked, nothing was unlocked and the reason was not recorded")
+
+func ensureCampaignLineUnlocked(campaignLine *db.OmsCampaignLineItem) error {
+	if campaignLine.LockedAt.Valid {
+		return errLineItemLocked
+	}
+
+	return nil
+}
+
+// unlockCampaignLine lets a billed line item change again, the reason is kept in its history.
+// Unlocking a line item that is not locked is a conflict, no reason is recorded for it.
+func unlockCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32,
+	req *models.UnlockCampaignLineItemRequest) error {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return newValidationError([]models.FieldError{{Field: "Reason", Message: "is required to unlock a line item"}})
+	}
+
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		campaignLine, err := q.GetCampaignLineForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !campaignLine.LockedAt.Valid {
+			return errLineItemNotLocked
+		}
+
+		if err := q.UnlockCampaignLine(ctx, id); err != nil {
+			return errors.Wrap(err, "cannot unlock line item")
+		}
+
+		err = q.CreateLineItemHistory(ctx, db.CreateLineItemHistoryParams{
+			LineItemID: id,
+			Event:      models.LineItemEventUnlocked,
+			Reason:     sql.NullString{Valid: true, String: reason},
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot record the unlock")
+		}
+
+		return emitLineItemEvent(ctx, q, models.WebhookEventLineItemUpdated, id)
+	})
+}
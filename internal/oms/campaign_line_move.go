@@ -0,0 +1,71 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+var errLineItemInvoiced = errors.New("the line item is billed by an issued invoice and cannot be moved")
+
+// moveCampaignLine moves a line item, keeping its id, to another campaign and
+// records the move in the history of the line item.
+func moveCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32,
+	req *models.MoveCampaignLineItemRequest) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		// Locking the line item keeps an invoice generation from billing it between
+		// the count of its issued invoices and the move.
+		campaignLine, err := q.GetCampaignLineForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if campaignLine.CampaignID == int32(req.CampaignID) {
+			return newValidationError([]models.FieldError{{
+				Field:   "CampaignID",
+				Message: "the line item already belongs to this campaign",
+			}})
+		}
+
+		issuedInvoices, err := q.CountIssuedInvoicesForLineItem(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "cannot count the issued invoices of the line item")
+		}
+
+		if issuedInvoices > 0 {
+			return errLineItemInvoiced
+		}
+
+		lineItem, err := models.NewCampaignLineItemFromDB(&campaignLine)
+		if err != nil {
+			return err
+		}
+
+		lineItem.CampaignID = req.CampaignID
+
+		if err := validateCampaignLineFlight(ctx, q, lineItem); err != nil {
+			return err
+		}
+
+		err = q.MoveCampaignLine(ctx, db.MoveCampaignLineParams{ID: id, CampaignID: int32(req.CampaignID)})
+		if err != nil {
+			return errors.Wrap(err, "cannot move line item")
+		}
+
+		err = q.CreateLineItemHistory(ctx, db.CreateLineItemHistoryParams{
+			LineItemID:     id,
+			Event:          models.LineItemEventMoved,
+			FromCampaignID: sql.NullInt32{Valid: true, Int32: campaignLine.CampaignID},
+			ToCampaignID:   sql.NullInt32{Valid: true, Int32: int32(req.CampaignID)},
+			Reason:         sql.NullString{Valid: req.Reason != "", String: req.Reason},
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot record the move")
+		}
+
+		return emitLineItemEvent(ctx, q, models.WebhookEventLineItemUpdated, id)
+	})
+}
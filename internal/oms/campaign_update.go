@@ -0,0 +1,105 @@
+package oms
+
+import (
+	"context"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+// campaignPatchFields are the campaign fields a merge patch may change.
+var campaignPatchFields = patchableFields{
+	"Name":      false,
+	"StartedAt": true,
+	"EndedAt":   true,
+	"Archiving": false,
+}
+
+// updateCampaign replaces the editable fields of a campaign after checking its flight.
+// Run it in a transaction, it records the campaign.updated event.
+func updateCampaign(ctx context.Context, dbQueries *db.Queries, id int32, campaign *models.Campaign) error {
+	if err := validateCampaignFlight(ctx, dbQueries, id, campaign); err != nil {
+		return err
+	}
+
+	campaignDB := campaign.ToCreateCampaign()
+
+	err := dbQueries.UpdateCampaign(ctx, db.UpdateCampaignParams{
+		Name:      campaignDB.Name,
+		StartedAt: campaignDB.StartedAt,
+		EndedAt:   campaignDB.EndedAt,
+		Archiving: campaignDB.Archiving,
+		ID:        id,
+	})
+	if err != nil {
+		return err
+	}
+
+	return emitCampaignEvent(ctx, dbQueries, models.WebhookEventCampaignUpdated, id)
+}
+
+// replaceCampaign updates a campaign that is still at a version the request named
+// and returns the updated campaign.
+func replaceCampaign(ctx context.Context, dbQueries *db.Queries, id int32, campaign *models.Campaign,
+	match *ifMatch) (*models.Campaign, error) {
+	var updated db.OmsCampaign
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetCampaignForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(existing.Version); err != nil {
+			return err
+		}
+
+		if err := updateCampaign(ctx, q, id, campaign); err != nil {
+			return err
+		}
+
+		updated, err = q.GetCampaign(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignFromDB(&updated), nil
+}
+
+// patchCampaign applies a merge patch to a campaign and returns the patched campaign.
+func patchCampaign(ctx context.Context, dbQueries *db.Queries, id int32, patch map[string]interface{},
+	match *ifMatch) (*models.Campaign, error) {
+	var campaign db.OmsCampaign
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetCampaignForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(existing.Version); err != nil {
+			return err
+		}
+
+		var patched models.Campaign
+		if err := applyMergePatch(models.NewCampaignFromDB(&existing), patch, campaignPatchFields, &patched); err != nil {
+			return err
+		}
+
+		if err := updateCampaign(ctx, q, id, &patched); err != nil {
+			return err
+		}
+
+		campaign, err = q.GetCampaign(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignFromDB(&campaign), nil
+}
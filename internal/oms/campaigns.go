@@ -0,0 +1,108 @@
+package oms
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+// createCampaign validates the flight of a campaign and creates it. A campaign
+// with an ID keeps it, the serial is moved past it afterwards.
+func createCampaign(ctx context.Context, logger *slog.Logger, dbQueries *db.Queries,
+	req *models.Campaign) (*models.Campaign, error) {
+	if err := newValidationError(req.ValidateFlight()); err != nil {
+		return nil, err
+	}
+
+	var campaign db.OmsCampaign
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		if req.ID > 0 {
+			logger.Info("Creating campaign with id")
+
+			params := req.ToCreateCampaignWithID()
+
+			campaign, err = q.CreateCampaignWithID(ctx, *params)
+			if err == nil {
+				resetErr := q.ResetCampaignID(ctx)
+				if resetErr != nil {
+					logger.Error("Cannot reset the serial id after manual insert")
+				}
+			}
+		} else {
+			logger.Info("Creating campaign without id")
+			params := req.ToCreateCampaign()
+			campaign, err = q.CreateCampaign(ctx, *params)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return emitCampaignEvent(ctx, q, models.WebhookEventCampaignCreated, campaign.ID)
+	})
+	if err != nil {
+		logger.Error("Error creating campaign", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		return nil, err
+	}
+
+	return models.NewCampaignFromDB(&campaign), nil
+}
+
+// getCampaign returns a campaign, a deleted campaign only when includeDeleted is set.
+func getCampaign(ctx context.Context, dbQueries *db.Queries, id int32, includeDeleted bool) (*models.Campaign, error) {
+	get := dbQueries.GetCampaign
+	if includeDeleted {
+		get = dbQueries.GetCampaignWithDeleted
+	}
+
+	campaign, err := get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignFromDB(&campaign), nil
+}
+
+// getCampaignSummary aggregates the line items and issued invoices of a campaign.
+// Every generated invoice bills the cumulative totals of the campaign, so the
+// latest issued invoice holds everything invoiced to date.
+func getCampaignSummary(ctx context.Context, dbQueries *db.Queries, id int32) (*models.CampaignSummary, error) {
+	if _, err := dbQueries.GetCampaign(ctx, id); err != nil {
+		return nil, err
+	}
+
+	totals, err := dbQueries.GetCampaignLineTotals(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiced, err := dbQueries.GetCampaignInvoicedTotal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignSummaryFromDB(id, &totals, invoiced)
+}
+
+// campaignListBase is the condition of the campaign list, campaigns that are
+// archiving are never listed.
+const campaignListBase = "archiving = false AND ($1::boolean OR deleted_at IS NULL)"
+
+// listCampaigns returns a page of the campaigns that are not archiving.
+func listCampaigns(ctx context.Context, dbQueries *db.Queries,
+	req *listRequest) (*models.List[models.Campaign], error) {
+	toModel := func(c *db.OmsCampaign) (*models.Campaign, error) { return models.NewCampaignFromDB(c), nil }
+
+	return listPage(ctx, req, dbQueries.QueryCampaigns, dbQueries.CountCampaigns, toModel,
+		func(c *models.Campaign) int { return c.ID }, campaignListBase, req.includeDeleted)
+}
+
+// countCampaigns counts the campaigns of the list across all pages.
+func countCampaigns(ctx context.Context, dbQueries *db.Queries, req *listRequest) (*int64, error) {
+	return req.countAll(ctx, dbQueries.CountCampaigns, campaignListBase, req.includeDeleted)
+}
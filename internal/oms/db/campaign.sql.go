@@ -8,12 +8,37 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
+const countCampaignDependents = `-- name: CountCampaignDependents :one
+SELECT
+    (SELECT COUNT(*) FROM oms.campaign_line_items li
+        WHERE li.campaign_id = $1 AND li.deleted_at IS NULL) AS line_item_count,
+    (SELECT COUNT(*) FROM oms.invoices i
+        WHERE i.campaign_id = $1 AND i.issued_at IS NULL AND i.deleted_at IS NULL) AS draft_invoice_count,
+    (SELECT COUNT(*) FROM oms.invoices i
+        WHERE i.campaign_id = $1 AND i.issued_at IS NOT NULL AND i.deleted_at IS NULL) AS issued_invoice_count
+`
+
+type CountCampaignDependentsRow struct {
+	LineItemCount      int64
+	DraftInvoiceCount  int64
+	IssuedInvoiceCount int64
+}
+
+func (q *Queries) CountCampaignDependents(ctx context.Context, campaignID int32) (CountCampaignDependentsRow, error) {
+	row := q.db.QueryRowContext(ctx, countCampaignDependents, campaignID)
+	var i CountCampaignDependentsRow
+	err := row.Scan(&i.LineItemCount, &i.DraftInvoiceCount, &i.IssuedInvoiceCount)
+	return i, err
+}
+
 const createCampaign = `-- name: CreateCampaign :one
 INSERT INTO oms.campaigns (name, started_at, ended_at, archiving)
 VALUES ($1, $2, $3, $4)
-RETURNING id, name, started_at, ended_at, archiving, created_at, updated_at
+RETURNING id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version
 `
 
 type CreateCampaignParams struct {
@@ -39,6 +64,8 @@ func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams)
 		&i.Archiving,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -47,7 +74,7 @@ const createCampaignWithID = `-- name: CreateCampaignWithID :one
 
 INSERT INTO oms.campaigns (name, id, started_at, ended_at, archiving)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, started_at, ended_at, archiving, created_at, updated_at
+RETURNING id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version
 `
 
 type CreateCampaignWithIDParams struct {
@@ -76,25 +103,39 @@ func (q *Queries) CreateCampaignWithID(ctx context.Context, arg CreateCampaignWi
 		&i.Archiving,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const deleteCampaign = `-- name: DeleteCampaign :exec
-DELETE FROM oms.campaigns WHERE id = $1
+const getCampaign = `-- name: GetCampaign :one
+SELECT id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version FROM oms.campaigns WHERE id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteCampaign(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteCampaign, id)
-	return err
+func (q *Queries) GetCampaign(ctx context.Context, id int32) (OmsCampaign, error) {
+	row := q.db.QueryRowContext(ctx, getCampaign, id)
+	var i OmsCampaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Archiving,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
-const getCampaign = `-- name: GetCampaign :one
-SELECT id, name, started_at, ended_at, archiving, created_at, updated_at FROM oms.campaigns WHERE id = $1
+const getCampaignForUpdate = `-- name: GetCampaignForUpdate :one
+SELECT id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version FROM oms.campaigns WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
 `
 
-func (q *Queries) GetCampaign(ctx context.Context, id int32) (OmsCampaign, error) {
-	row := q.db.QueryRowContext(ctx, getCampaign, id)
+func (q *Queries) GetCampaignForUpdate(ctx context.Context, id int32) (OmsCampaign, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignForUpdate, id)
 	var i OmsCampaign
 	err := row.Scan(
 		&i.ID,
@@ -104,24 +145,40 @@ func (q *Queries) GetCampaign(ctx context.Context, id int32) (OmsCampaign, error
 		&i.Archiving,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const listCampaigns = `-- name: ListCampaigns :many
-SELECT id, name, started_at, ended_at, archiving, created_at, updated_at FROM oms.campaigns 
-WHERE archiving = false AND id > $1
-Order by id
-LIMIT $2
+const getCampaignWithDeleted = `-- name: GetCampaignWithDeleted :one
+SELECT id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version FROM oms.campaigns WHERE id = $1
 `
 
-type ListCampaignsParams struct {
-	ID    int32
-	Limit int32
+func (q *Queries) GetCampaignWithDeleted(ctx context.Context, id int32) (OmsCampaign, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignWithDeleted, id)
+	var i OmsCampaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Archiving,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
-func (q *Queries) ListCampaigns(ctx context.Context, arg ListCampaignsParams) ([]OmsCampaign, error) {
-	rows, err := q.db.QueryContext(ctx, listCampaigns, arg.ID, arg.Limit)
+const listCampaignsByIDs = `-- name: ListCampaignsByIDs :many
+SELECT id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version FROM oms.campaigns WHERE id = ANY($1::int[])
+ORDER BY id
+`
+
+func (q *Queries) ListCampaignsByIDs(ctx context.Context, ids []int32) ([]OmsCampaign, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignsByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +194,8 @@ func (q *Queries) ListCampaigns(ctx context.Context, arg ListCampaignsParams) ([
 			&i.Archiving,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -151,10 +210,86 @@ func (q *Queries) ListCampaigns(ctx context.Context, arg ListCampaignsParams) ([
 	return items, nil
 }
 
+const purgeCampaigns = `-- name: PurgeCampaigns :many
+DELETE FROM oms.campaigns c
+WHERE c.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM oms.campaign_line_items li WHERE li.campaign_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM oms.invoices i WHERE i.campaign_id = c.id)
+RETURNING id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version
+`
+
+func (q *Queries) PurgeCampaigns(ctx context.Context, deletedAt sql.NullTime) ([]OmsCampaign, error) {
+	rows, err := q.db.QueryContext(ctx, purgeCampaigns, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OmsCampaign
+	for rows.Next() {
+		var i OmsCampaign
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Archiving,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreCampaign = `-- name: RestoreCampaign :exec
+UPDATE oms.campaigns SET deleted_at = NULL WHERE id = $1
+`
+
+func (q *Queries) RestoreCampaign(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, restoreCampaign, id)
+	return err
+}
+
+const softDeleteCampaign = `-- name: SoftDeleteCampaign :exec
+UPDATE oms.campaigns SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteCampaignParams struct {
+	ID        int32
+	DeletedAt sql.NullTime
+}
+
+func (q *Queries) SoftDeleteCampaign(ctx context.Context, arg SoftDeleteCampaignParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteCampaign, arg.ID, arg.DeletedAt)
+	return err
+}
+
+const touchCampaign = `-- name: TouchCampaign :one
+UPDATE oms.campaigns SET updated_at = CURRENT_TIMESTAMP WHERE id = $1
+RETURNING version
+`
+
+func (q *Queries) TouchCampaign(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, touchCampaign, id)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
 const updateCampaign = `-- name: UpdateCampaign :exec
 UPDATE oms.campaigns
 SET name = $1, started_at = $2, ended_at = $3, archiving = $4
-WHERE id = $5
+WHERE id = $5 AND deleted_at IS NULL
 `
 
 type UpdateCampaignParams struct {
@@ -2,11 +2,16 @@ package db
 
 import (
 	"context"
-	"fmt"
+
+	"github.com/lib/pq"
 )
 
+// BuildCampaignInvoiceParams names the line items to bill, the ones
+// LockCampaignLinesForInvoice locked, so the totals are those of the rows the
+// invoice links.
 type BuildCampaignInvoiceParams struct {
-	CampaignID int32
+	CampaignID  int32
+	LineItemIds []int32
 }
 
 // NOTE: not using sqlc as encountered an error, creating by hand in the same pattern
@@ -15,17 +20,15 @@ type BuildCampaignInvoiceParams struct {
 //
 //nolint:lll //Why: valid sql statement that is required to be long
 const buildCampaignInvoice = `select oms.campaigns.ID as campaign_id, sum(actual) as total_actual_amount, sum(booked) as total_booked_amount, sum(adjustments) as total_adjustments_amount from oms.campaigns
-LEFT JOIN oms.campaign_line_items on oms.campaigns.ID = oms.campaign_line_items.campaign_id
-where oms.campaigns.archiving = false and oms.campaigns.ID = %d
+LEFT JOIN oms.campaign_line_items on oms.campaigns.ID = oms.campaign_line_items.campaign_id and oms.campaign_line_items.id = ANY($2::int[])
+where oms.campaigns.archiving = false and oms.campaigns.deleted_at is null and oms.campaigns.ID = $1
 group by oms.campaigns.ID
 order by oms.campaigns.ID`
 
 func (q *Queries) BuildCampaignInvoice(ctx context.Context,
 	arg BuildCampaignInvoiceParams) (CreateInvoiceParams, error) {
 	p := CreateInvoiceParams{}
-	query := fmt.Sprintf(buildCampaignInvoice, arg.CampaignID)
-
-	row := q.db.QueryRowContext(ctx, query)
+	row := q.db.QueryRowContext(ctx, buildCampaignInvoice, arg.CampaignID, pq.Array(arg.LineItemIds))
 
 	if err := row.Scan(
 		&p.CampaignID,
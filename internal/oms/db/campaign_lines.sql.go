@@ -8,22 +8,29 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createCampaignLine = `-- name: CreateCampaignLine :one
-INSERT INTO oms.campaign_line_items (campaign_id, name, booked, actual, adjustments, started_at, ended_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO oms.campaign_line_items (campaign_id, name, booked, actual, adjustments, started_at, ended_at,
+    pricing_model, unit_rate, booked_units, delivered_units)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING id
 `
 
 type CreateCampaignLineParams struct {
-	CampaignID  int32
-	Name        string
-	Booked      string
-	Actual      sql.NullString
-	Adjustments sql.NullString
-	StartedAt   sql.NullTime
-	EndedAt     sql.NullTime
+	CampaignID     int32
+	Name           string
+	Booked         string
+	Actual         sql.NullString
+	Adjustments    sql.NullString
+	StartedAt      sql.NullTime
+	EndedAt        sql.NullTime
+	PricingModel   string
+	UnitRate       string
+	BookedUnits    int64
+	DeliveredUnits int64
 }
 
 func (q *Queries) CreateCampaignLine(ctx context.Context, arg CreateCampaignLineParams) (int32, error) {
@@ -35,6 +42,10 @@ func (q *Queries) CreateCampaignLine(ctx context.Context, arg CreateCampaignLine
 		arg.Adjustments,
 		arg.StartedAt,
 		arg.EndedAt,
+		arg.PricingModel,
+		arg.UnitRate,
+		arg.BookedUnits,
+		arg.DeliveredUnits,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -43,20 +54,25 @@ func (q *Queries) CreateCampaignLine(ctx context.Context, arg CreateCampaignLine
 
 const createCampaignLineWithID = `-- name: CreateCampaignLineWithID :one
 
-INSERT INTO oms.campaign_line_items (id, campaign_id, name, booked, actual, adjustments, started_at, ended_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO oms.campaign_line_items (id, campaign_id, name, booked, actual, adjustments, started_at, ended_at,
+    pricing_model, unit_rate, booked_units, delivered_units)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING id
 `
 
 type CreateCampaignLineWithIDParams struct {
-	ID          int32
-	CampaignID  int32
-	Name        string
-	Booked      string
-	Actual      sql.NullString
-	Adjustments sql.NullString
-	StartedAt   sql.NullTime
-	EndedAt     sql.NullTime
+	ID             int32
+	CampaignID     int32
+	Name           string
+	Booked         string
+	Actual         sql.NullString
+	Adjustments    sql.NullString
+	StartedAt      sql.NullTime
+	EndedAt        sql.NullTime
+	PricingModel   string
+	UnitRate       string
+	BookedUnits    int64
+	DeliveredUnits int64
 }
 
 // campaign_line.sql
@@ -70,23 +86,42 @@ func (q *Queries) CreateCampaignLineWithID(ctx context.Context, arg CreateCampai
 		arg.Adjustments,
 		arg.StartedAt,
 		arg.EndedAt,
+		arg.PricingModel,
+		arg.UnitRate,
+		arg.BookedUnits,
+		arg.DeliveredUnits,
 	)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
-const deleteCampaignLine = `-- name: DeleteCampaignLine :exec
-DELETE FROM oms.campaign_line_items WHERE id = $1
+const createLineItemHistory = `-- name: CreateLineItemHistory :exec
+INSERT INTO oms.line_item_history (line_item_id, event, from_campaign_id, to_campaign_id, reason)
+VALUES ($1, $2, $3, $4, $5)
 `
 
-func (q *Queries) DeleteCampaignLine(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteCampaignLine, id)
+type CreateLineItemHistoryParams struct {
+	LineItemID     int32
+	Event          string
+	FromCampaignID sql.NullInt32
+	ToCampaignID   sql.NullInt32
+	Reason         sql.NullString
+}
+
+func (q *Queries) CreateLineItemHistory(ctx context.Context, arg CreateLineItemHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createLineItemHistory,
+		arg.LineItemID,
+		arg.Event,
+		arg.FromCampaignID,
+		arg.ToCampaignID,
+		arg.Reason,
+	)
 	return err
 }
 
 const getCampaignLine = `-- name: GetCampaignLine :one
-SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at FROM oms.campaign_line_items WHERE id = $1
+SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version FROM oms.campaign_line_items WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetCampaignLine(ctx context.Context, id int32) (OmsCampaignLineItem, error) {
@@ -103,24 +138,340 @@ func (q *Queries) GetCampaignLine(ctx context.Context, id int32) (OmsCampaignLin
 		&i.EndedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PricingModel,
+		&i.UnitRate,
+		&i.BookedUnits,
+		&i.DeliveredUnits,
+		&i.LockedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getCampaignLineFlightBounds = `-- name: GetCampaignLineFlightBounds :one
+SELECT LEAST(MIN(started_at), MIN(ended_at))::timestamptz AS earliest,
+    GREATEST(MAX(started_at), MAX(ended_at))::timestamptz AS latest
+FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL
+`
+
+type GetCampaignLineFlightBoundsRow struct {
+	Earliest sql.NullTime
+	Latest   sql.NullTime
+}
+
+func (q *Queries) GetCampaignLineFlightBounds(ctx context.Context, campaignID int32) (GetCampaignLineFlightBoundsRow, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignLineFlightBounds, campaignID)
+	var i GetCampaignLineFlightBoundsRow
+	err := row.Scan(&i.Earliest, &i.Latest)
+	return i, err
+}
+
+const getCampaignLineForUpdate = `-- name: GetCampaignLineForUpdate :one
+SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version FROM oms.campaign_line_items WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetCampaignLineForUpdate(ctx context.Context, id int32) (OmsCampaignLineItem, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignLineForUpdate, id)
+	var i OmsCampaignLineItem
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Booked,
+		&i.Actual,
+		&i.Adjustments,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PricingModel,
+		&i.UnitRate,
+		&i.BookedUnits,
+		&i.DeliveredUnits,
+		&i.LockedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const listCampaignLineItems = `-- name: ListCampaignLineItems :many
-SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at FROM oms.campaign_line_items 
-WHERE id > $1
+const getCampaignLineTotals = `-- name: GetCampaignLineTotals :one
+SELECT COUNT(*) AS line_item_count,
+    COALESCE(SUM(booked), 0)::numeric AS total_booked,
+    COALESCE(SUM(actual), 0)::numeric AS total_actual,
+    COALESCE(SUM(adjustments), 0)::numeric AS total_adjustments
+FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL
+`
+
+type GetCampaignLineTotalsRow struct {
+	LineItemCount    int64
+	TotalBooked      string
+	TotalActual      string
+	TotalAdjustments string
+}
+
+func (q *Queries) GetCampaignLineTotals(ctx context.Context, campaignID int32) (GetCampaignLineTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignLineTotals, campaignID)
+	var i GetCampaignLineTotalsRow
+	err := row.Scan(
+		&i.LineItemCount,
+		&i.TotalBooked,
+		&i.TotalActual,
+		&i.TotalAdjustments,
+	)
+	return i, err
+}
+
+const getCampaignLineWithDeleted = `-- name: GetCampaignLineWithDeleted :one
+SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version FROM oms.campaign_line_items WHERE id = $1
+`
+
+func (q *Queries) GetCampaignLineWithDeleted(ctx context.Context, id int32) (OmsCampaignLineItem, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignLineWithDeleted, id)
+	var i OmsCampaignLineItem
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Booked,
+		&i.Actual,
+		&i.Adjustments,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PricingModel,
+		&i.UnitRate,
+		&i.BookedUnits,
+		&i.DeliveredUnits,
+		&i.LockedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const listCampaignLinesForCampaign = `-- name: ListCampaignLinesForCampaign :many
+SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL
+Order by id
+`
+
+func (q *Queries) ListCampaignLinesForCampaign(ctx context.Context, campaignID int32) ([]OmsCampaignLineItem, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignLinesForCampaign, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OmsCampaignLineItem
+	for rows.Next() {
+		var i OmsCampaignLineItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Booked,
+			&i.Actual,
+			&i.Adjustments,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PricingModel,
+			&i.UnitRate,
+			&i.BookedUnits,
+			&i.DeliveredUnits,
+			&i.LockedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignLinesForCampaigns = `-- name: ListCampaignLinesForCampaigns :many
+SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version FROM oms.campaign_line_items
+WHERE id IN (
+    SELECT kept.id FROM unnest($1::int[]) AS campaign(id)
+    CROSS JOIN LATERAL (
+        SELECT id FROM oms.campaign_line_items
+        WHERE campaign_id = campaign.id AND deleted_at IS NULL
+        ORDER BY id LIMIT $2::integer
+    ) AS kept
+)
+ORDER BY campaign_id, id
+`
+
+type ListCampaignLinesForCampaignsParams struct {
+	CampaignIds    []int32
+	MaxPerCampaign int32
+}
+
+func (q *Queries) ListCampaignLinesForCampaigns(ctx context.Context, arg ListCampaignLinesForCampaignsParams) ([]OmsCampaignLineItem, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignLinesForCampaigns, pq.Array(arg.CampaignIds), arg.MaxPerCampaign)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OmsCampaignLineItem
+	for rows.Next() {
+		var i OmsCampaignLineItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Booked,
+			&i.Actual,
+			&i.Adjustments,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PricingModel,
+			&i.UnitRate,
+			&i.BookedUnits,
+			&i.DeliveredUnits,
+			&i.LockedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLineItemHistory = `-- name: ListLineItemHistory :many
+SELECT id, line_item_id, event, from_campaign_id, to_campaign_id, reason, created_at FROM oms.line_item_history
+WHERE line_item_id = $1
 Order by id
-LIMIT $2
 `
 
-type ListCampaignLineItemsParams struct {
-	ID    int32
-	Limit int32
+func (q *Queries) ListLineItemHistory(ctx context.Context, lineItemID int32) ([]OmsLineItemHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listLineItemHistory, lineItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OmsLineItemHistory
+	for rows.Next() {
+		var i OmsLineItemHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.LineItemID,
+			&i.Event,
+			&i.FromCampaignID,
+			&i.ToCampaignID,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockCampaignLinesForInvoice = `-- name: LockCampaignLinesForInvoice :many
+SELECT id FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockCampaignLinesForInvoice(ctx context.Context, campaignID int32) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, lockCampaignLinesForInvoice, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockInvoiceLineItems = `-- name: LockInvoiceLineItems :exec
+UPDATE oms.campaign_line_items
+SET locked_at = $2
+WHERE id IN (SELECT line_item_id FROM oms.invoice_line_items WHERE invoice_id = $1) AND locked_at IS NULL
+`
+
+type LockInvoiceLineItemsParams struct {
+	InvoiceID int32
+	LockedAt  sql.NullTime
+}
+
+func (q *Queries) LockInvoiceLineItems(ctx context.Context, arg LockInvoiceLineItemsParams) error {
+	_, err := q.db.ExecContext(ctx, lockInvoiceLineItems, arg.InvoiceID, arg.LockedAt)
+	return err
+}
+
+const moveCampaignLine = `-- name: MoveCampaignLine :exec
+UPDATE oms.campaign_line_items
+SET campaign_id = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type MoveCampaignLineParams struct {
+	ID         int32
+	CampaignID int32
+}
+
+func (q *Queries) MoveCampaignLine(ctx context.Context, arg MoveCampaignLineParams) error {
+	_, err := q.db.ExecContext(ctx, moveCampaignLine, arg.ID, arg.CampaignID)
+	return err
 }
 
-func (q *Queries) ListCampaignLineItems(ctx context.Context, arg ListCampaignLineItemsParams) ([]OmsCampaignLineItem, error) {
-	rows, err := q.db.QueryContext(ctx, listCampaignLineItems, arg.ID, arg.Limit)
+const purgeCampaignLines = `-- name: PurgeCampaignLines :many
+DELETE FROM oms.campaign_line_items li
+WHERE li.deleted_at < $1
+    AND NOT EXISTS (
+        SELECT 1 FROM oms.invoice_line_items il
+        JOIN oms.invoices i ON i.id = il.invoice_id
+        WHERE il.line_item_id = li.id AND i.issued_at IS NOT NULL
+    )
+RETURNING id, campaign_id, name, booked, actual, adjustments, started_at, ended_at, created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version
+`
+
+func (q *Queries) PurgeCampaignLines(ctx context.Context, deletedAt sql.NullTime) ([]OmsCampaignLineItem, error) {
+	rows, err := q.db.QueryContext(ctx, purgeCampaignLines, deletedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +490,13 @@ func (q *Queries) ListCampaignLineItems(ctx context.Context, arg ListCampaignLin
 			&i.EndedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PricingModel,
+			&i.UnitRate,
+			&i.BookedUnits,
+			&i.DeliveredUnits,
+			&i.LockedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -153,20 +511,116 @@ func (q *Queries) ListCampaignLineItems(ctx context.Context, arg ListCampaignLin
 	return items, nil
 }
 
+const recordInvoiceLineItemsLocked = `-- name: RecordInvoiceLineItemsLocked :exec
+INSERT INTO oms.line_item_history (line_item_id, event, reason)
+SELECT line_item_id, 'locked', 'billed by invoice ' || $1::integer
+FROM oms.invoice_line_items
+WHERE invoice_id = $1
+`
+
+func (q *Queries) RecordInvoiceLineItemsLocked(ctx context.Context, invoiceID int32) error {
+	_, err := q.db.ExecContext(ctx, recordInvoiceLineItemsLocked, invoiceID)
+	return err
+}
+
+const restoreCampaignLine = `-- name: RestoreCampaignLine :exec
+UPDATE oms.campaign_line_items SET deleted_at = NULL WHERE id = $1
+`
+
+func (q *Queries) RestoreCampaignLine(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, restoreCampaignLine, id)
+	return err
+}
+
+const restoreCampaignLinesForCampaign = `-- name: RestoreCampaignLinesForCampaign :many
+UPDATE oms.campaign_line_items SET deleted_at = NULL WHERE campaign_id = $1 AND deleted_at = $2
+RETURNING id
+`
+
+type RestoreCampaignLinesForCampaignParams struct {
+	CampaignID int32
+	DeletedAt  sql.NullTime
+}
+
+func (q *Queries) RestoreCampaignLinesForCampaign(ctx context.Context, arg RestoreCampaignLinesForCampaignParams) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, restoreCampaignLinesForCampaign, arg.CampaignID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteCampaignLine = `-- name: SoftDeleteCampaignLine :exec
+UPDATE oms.campaign_line_items SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteCampaignLineParams struct {
+	ID        int32
+	DeletedAt sql.NullTime
+}
+
+func (q *Queries) SoftDeleteCampaignLine(ctx context.Context, arg SoftDeleteCampaignLineParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteCampaignLine, arg.ID, arg.DeletedAt)
+	return err
+}
+
+const softDeleteCampaignLinesForCampaign = `-- name: SoftDeleteCampaignLinesForCampaign :exec
+UPDATE oms.campaign_line_items SET deleted_at = $2 WHERE campaign_id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteCampaignLinesForCampaignParams struct {
+	CampaignID int32
+	DeletedAt  sql.NullTime
+}
+
+func (q *Queries) SoftDeleteCampaignLinesForCampaign(ctx context.Context, arg SoftDeleteCampaignLinesForCampaignParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteCampaignLinesForCampaign, arg.CampaignID, arg.DeletedAt)
+	return err
+}
+
+const unlockCampaignLine = `-- name: UnlockCampaignLine :exec
+UPDATE oms.campaign_line_items SET locked_at = NULL WHERE id = $1
+`
+
+func (q *Queries) UnlockCampaignLine(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, unlockCampaignLine, id)
+	return err
+}
+
 const updateCampaignLine = `-- name: UpdateCampaignLine :exec
 UPDATE oms.campaign_line_items
-SET name = $2, booked = $3, actual = $4, adjustments = $5, started_at = $6, ended_at = $7
-WHERE id = $1
+SET name = $2, booked = $3, actual = $4, adjustments = $5, started_at = $6, ended_at = $7,
+    pricing_model = $8, unit_rate = $9, booked_units = $10, delivered_units = $11
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 type UpdateCampaignLineParams struct {
-	ID          int32
-	Name        string
-	Booked      string
-	Actual      sql.NullString
-	Adjustments sql.NullString
-	StartedAt   sql.NullTime
-	EndedAt     sql.NullTime
+	ID             int32
+	Name           string
+	Booked         string
+	Actual         sql.NullString
+	Adjustments    sql.NullString
+	StartedAt      sql.NullTime
+	EndedAt        sql.NullTime
+	PricingModel   string
+	UnitRate       string
+	BookedUnits    int64
+	DeliveredUnits int64
 }
 
 func (q *Queries) UpdateCampaignLine(ctx context.Context, arg UpdateCampaignLineParams) error {
@@ -178,6 +632,10 @@ func (q *Queries) UpdateCampaignLine(ctx context.Context, arg UpdateCampaignLine
 		arg.Adjustments,
 		arg.StartedAt,
 		arg.EndedAt,
+		arg.PricingModel,
+		arg.UnitRate,
+		arg.BookedUnits,
+		arg.DeliveredUnits,
 	)
 	return err
 }
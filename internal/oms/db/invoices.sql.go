@@ -8,12 +8,14 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const adjustInvoice = `-- name: AdjustInvoice :exec
 UPDATE oms.invoices
 SET total_adjustments = $2
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 type AdjustInvoiceParams struct {
@@ -26,6 +28,35 @@ func (q *Queries) AdjustInvoice(ctx context.Context, arg AdjustInvoiceParams) er
 	return err
 }
 
+const collectInvoice = `-- name: CollectInvoice :exec
+UPDATE oms.invoices
+SET collected_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type CollectInvoiceParams struct {
+	ID          int32
+	CollectedAt sql.NullTime
+}
+
+func (q *Queries) CollectInvoice(ctx context.Context, arg CollectInvoiceParams) error {
+	_, err := q.db.ExecContext(ctx, collectInvoice, arg.ID, arg.CollectedAt)
+	return err
+}
+
+const countIssuedInvoicesForLineItem = `-- name: CountIssuedInvoicesForLineItem :one
+SELECT COUNT(*) FROM oms.invoice_line_items il
+JOIN oms.invoices i ON i.id = il.invoice_id
+WHERE il.line_item_id = $1 AND i.issued_at IS NOT NULL AND i.deleted_at IS NULL
+`
+
+func (q *Queries) CountIssuedInvoicesForLineItem(ctx context.Context, lineItemID int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countIssuedInvoicesForLineItem, lineItemID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createInvoice = `-- name: CreateInvoice :one
 
 INSERT INTO oms.invoices (campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at)
@@ -59,17 +90,25 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (i
 	return id, err
 }
 
-const deleteInvoice = `-- name: DeleteInvoice :exec
-DELETE FROM oms.invoices WHERE id = $1
+const getCampaignInvoicedTotal = `-- name: GetCampaignInvoicedTotal :one
+SELECT COALESCE((
+    SELECT COALESCE(total_actual_amount, 0) + COALESCE(total_adjustments, 0)
+    FROM oms.invoices
+    WHERE campaign_id = $1 AND issued_at IS NOT NULL AND deleted_at IS NULL
+    ORDER BY issued_at DESC, id DESC
+    LIMIT 1
+), 0)::numeric AS invoiced_total
 `
 
-func (q *Queries) DeleteInvoice(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteInvoice, id)
-	return err
+func (q *Queries) GetCampaignInvoicedTotal(ctx context.Context, campaignID int32) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignInvoicedTotal, campaignID)
+	var invoiced_total string
+	err := row.Scan(&invoiced_total)
+	return invoiced_total, err
 }
 
 const getInvoice = `-- name: GetInvoice :one
-SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at FROM oms.invoices WHERE id = $1
+SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at, deleted_at, collected_at, version FROM oms.invoices WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetInvoice(ctx context.Context, id int32) (OmsInvoice, error) {
@@ -86,24 +125,98 @@ func (q *Queries) GetInvoice(ctx context.Context, id int32) (OmsInvoice, error)
 		&i.IssuedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CollectedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getInvoiceForUpdate = `-- name: GetInvoiceForUpdate :one
+SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at, deleted_at, collected_at, version FROM oms.invoices WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetInvoiceForUpdate(ctx context.Context, id int32) (OmsInvoice, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceForUpdate, id)
+	var i OmsInvoice
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.TotalBookedAmount,
+		&i.TotalActualAmount,
+		&i.TotalAdjustments,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.IssuedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CollectedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getInvoiceWithDeleted = `-- name: GetInvoiceWithDeleted :one
+SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at, deleted_at, collected_at, version FROM oms.invoices WHERE id = $1
+`
+
+func (q *Queries) GetInvoiceWithDeleted(ctx context.Context, id int32) (OmsInvoice, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceWithDeleted, id)
+	var i OmsInvoice
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.TotalBookedAmount,
+		&i.TotalActualAmount,
+		&i.TotalAdjustments,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.IssuedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CollectedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const listInvoices = `-- name: ListInvoices :many
-SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at FROM oms.invoices 
-WHERE id > $1
-Order by id
-LIMIT $2
+const linkInvoiceLineItems = `-- name: LinkInvoiceLineItems :exec
+INSERT INTO oms.invoice_line_items (invoice_id, line_item_id)
+SELECT $1::integer, unnest($2::int[])
 `
 
-type ListInvoicesParams struct {
-	ID    int32
-	Limit int32
+type LinkInvoiceLineItemsParams struct {
+	InvoiceID   int32
+	LineItemIds []int32
 }
 
-func (q *Queries) ListInvoices(ctx context.Context, arg ListInvoicesParams) ([]OmsInvoice, error) {
-	rows, err := q.db.QueryContext(ctx, listInvoices, arg.ID, arg.Limit)
+func (q *Queries) LinkInvoiceLineItems(ctx context.Context, arg LinkInvoiceLineItemsParams) error {
+	_, err := q.db.ExecContext(ctx, linkInvoiceLineItems, arg.InvoiceID, pq.Array(arg.LineItemIds))
+	return err
+}
+
+const listInvoicesForCampaigns = `-- name: ListInvoicesForCampaigns :many
+SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at, deleted_at, collected_at, version FROM oms.invoices
+WHERE id IN (
+    SELECT kept.id FROM unnest($1::int[]) AS campaign(id)
+    CROSS JOIN LATERAL (
+        SELECT id FROM oms.invoices
+        WHERE campaign_id = campaign.id AND deleted_at IS NULL
+        ORDER BY id LIMIT $2::integer
+    ) AS kept
+)
+ORDER BY campaign_id, id
+`
+
+type ListInvoicesForCampaignsParams struct {
+	CampaignIds    []int32
+	MaxPerCampaign int32
+}
+
+func (q *Queries) ListInvoicesForCampaigns(ctx context.Context, arg ListInvoicesForCampaignsParams) ([]OmsInvoice, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoicesForCampaigns, pq.Array(arg.CampaignIds), arg.MaxPerCampaign)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +235,9 @@ func (q *Queries) ListInvoices(ctx context.Context, arg ListInvoicesParams) ([]O
 			&i.IssuedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollectedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -135,3 +251,138 @@ func (q *Queries) ListInvoices(ctx context.Context, arg ListInvoicesParams) ([]O
 	}
 	return items, nil
 }
+
+const purgeInvoices = `-- name: PurgeInvoices :many
+DELETE FROM oms.invoices WHERE deleted_at < $1 AND issued_at IS NULL
+RETURNING id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments, started_at, ended_at, issued_at, created_at, updated_at, deleted_at, collected_at, version
+`
+
+func (q *Queries) PurgeInvoices(ctx context.Context, deletedAt sql.NullTime) ([]OmsInvoice, error) {
+	rows, err := q.db.QueryContext(ctx, purgeInvoices, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OmsInvoice
+	for rows.Next() {
+		var i OmsInvoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.TotalBookedAmount,
+			&i.TotalActualAmount,
+			&i.TotalAdjustments,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.IssuedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollectedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreInvoice = `-- name: RestoreInvoice :exec
+UPDATE oms.invoices SET deleted_at = NULL WHERE id = $1
+`
+
+func (q *Queries) RestoreInvoice(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, restoreInvoice, id)
+	return err
+}
+
+const restoreInvoicesForCampaign = `-- name: RestoreInvoicesForCampaign :many
+UPDATE oms.invoices SET deleted_at = NULL WHERE campaign_id = $1 AND deleted_at = $2
+RETURNING id
+`
+
+type RestoreInvoicesForCampaignParams struct {
+	CampaignID int32
+	DeletedAt  sql.NullTime
+}
+
+func (q *Queries) RestoreInvoicesForCampaign(ctx context.Context, arg RestoreInvoicesForCampaignParams) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, restoreInvoicesForCampaign, arg.CampaignID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteDraftInvoicesForCampaign = `-- name: SoftDeleteDraftInvoicesForCampaign :exec
+UPDATE oms.invoices SET deleted_at = $2 WHERE campaign_id = $1 AND issued_at IS NULL AND deleted_at IS NULL
+`
+
+type SoftDeleteDraftInvoicesForCampaignParams struct {
+	CampaignID int32
+	DeletedAt  sql.NullTime
+}
+
+func (q *Queries) SoftDeleteDraftInvoicesForCampaign(ctx context.Context, arg SoftDeleteDraftInvoicesForCampaignParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteDraftInvoicesForCampaign, arg.CampaignID, arg.DeletedAt)
+	return err
+}
+
+const softDeleteInvoice = `-- name: SoftDeleteInvoice :exec
+UPDATE oms.invoices SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteInvoiceParams struct {
+	ID        int32
+	DeletedAt sql.NullTime
+}
+
+func (q *Queries) SoftDeleteInvoice(ctx context.Context, arg SoftDeleteInvoiceParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteInvoice, arg.ID, arg.DeletedAt)
+	return err
+}
+
+const updateInvoice = `-- name: UpdateInvoice :exec
+UPDATE oms.invoices
+SET total_adjustments = $2, started_at = $3, ended_at = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type UpdateInvoiceParams struct {
+	ID               int32
+	TotalAdjustments sql.NullString
+	StartedAt        sql.NullTime
+	EndedAt          sql.NullTime
+}
+
+func (q *Queries) UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoice,
+		arg.ID,
+		arg.TotalAdjustments,
+		arg.StartedAt,
+		arg.EndedAt,
+	)
+	return err
+}
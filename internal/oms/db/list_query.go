@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+)
+
+// ListQuery is a list query assembled at runtime for filters and orderings sqlc
+// cannot express. Where and OrderBy are SQL built from whitelisted columns only,
+// every value is passed in Args and referenced by placeholder.
+type ListQuery struct {
+	Where   string
+	OrderBy string
+	Args    []interface{}
+	Limit   int32
+}
+
+func (l ListQuery) sql(selectFrom string) (string, []interface{}) {
+	args := append(append([]interface{}{}, l.Args...), l.Limit)
+
+	return selectFrom + " WHERE " + l.Where + " ORDER BY " + l.OrderBy + " LIMIT $" + strconv.Itoa(len(args)), args
+}
+
+const queryCampaigns = `SELECT id, name, started_at, ended_at, archiving, created_at, updated_at, deleted_at, version
+FROM oms.campaigns`
+
+func (q *Queries) QueryCampaigns(ctx context.Context, l ListQuery) ([]OmsCampaign, error) {
+	query, args := l.sql(queryCampaigns)
+
+	return queryRows(ctx, q, query, args, func(rows *sql.Rows, i *OmsCampaign) error {
+		return rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Archiving,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		)
+	})
+}
+
+const queryCampaignLineItems = `SELECT id, campaign_id, name, booked, actual, adjustments, started_at, ended_at,
+created_at, updated_at, deleted_at, pricing_model, unit_rate, booked_units, delivered_units, locked_at, version
+FROM oms.campaign_line_items`
+
+func (q *Queries) QueryCampaignLineItems(ctx context.Context, l ListQuery) ([]OmsCampaignLineItem, error) {
+	query, args := l.sql(queryCampaignLineItems)
+
+	return queryRows(ctx, q, query, args, func(rows *sql.Rows, i *OmsCampaignLineItem) error {
+		return rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Booked,
+			&i.Actual,
+			&i.Adjustments,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PricingModel,
+			&i.UnitRate,
+			&i.BookedUnits,
+			&i.DeliveredUnits,
+			&i.LockedAt,
+			&i.Version,
+		)
+	})
+}
+
+const queryInvoices = `SELECT id, campaign_id, total_booked_amount, total_actual_amount, total_adjustments,
+started_at, ended_at, issued_at, created_at, updated_at, deleted_at, collected_at, version
+FROM oms.invoices`
+
+func (q *Queries) QueryInvoices(ctx context.Context, l ListQuery) ([]OmsInvoice, error) {
+	query, args := l.sql(queryInvoices)
+
+	return queryRows(ctx, q, query, args, func(rows *sql.Rows, i *OmsInvoice) error {
+		return rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.TotalBookedAmount,
+			&i.TotalActualAmount,
+			&i.TotalAdjustments,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.IssuedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollectedAt,
+			&i.Version,
+		)
+	})
+}
+
+func queryRows[T any](ctx context.Context, q *Queries, query string, args []interface{},
+	scan func(*sql.Rows, *T) error) ([]T, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []T
+
+	for rows.Next() {
+		var i T
+		if err := scan(rows, &i); err != nil {
+			return nil, err
+		}
+
+		items = append(items, i)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CountCampaigns counts the campaigns matching l.Where, the order and limit are ignored.
+func (q *Queries) CountCampaigns(ctx context.Context, l ListQuery) (int64, error) {
+	return q.countRows(ctx, "oms.campaigns", l)
+}
+
+// CountCampaignLineItems counts the line items matching l.Where, the order and limit are ignored.
+func (q *Queries) CountCampaignLineItems(ctx context.Context, l ListQuery) (int64, error) {
+	return q.countRows(ctx, "oms.campaign_line_items", l)
+}
+
+// CountInvoices counts the invoices matching l.Where, the order and limit are ignored.
+func (q *Queries) CountInvoices(ctx context.Context, l ListQuery) (int64, error) {
+	return q.countRows(ctx, "oms.invoices", l)
+}
+
+func (q *Queries) countRows(ctx context.Context, table string, l ListQuery) (int64, error) {
+	row := q.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE "+l.Where, l.Args...)
+
+	var count int64
+	err := row.Scan(&count)
+
+	return count, err
+}
@@ -6,6 +6,8 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 )
 
 type OmsCampaign struct {
@@ -16,17 +18,41 @@ type OmsCampaign struct {
 	Archiving sql.NullBool
 	CreatedAt sql.NullTime
 	UpdatedAt sql.NullTime
+	DeletedAt sql.NullTime
+	Version   int32
 }
 
 type OmsCampaignLineItem struct {
+	ID             int32
+	CampaignID     int32
+	Name           string
+	Booked         string
+	Actual         sql.NullString
+	Adjustments    sql.NullString
+	StartedAt      sql.NullTime
+	EndedAt        sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	DeletedAt      sql.NullTime
+	PricingModel   string
+	UnitRate       string
+	BookedUnits    int64
+	DeliveredUnits int64
+	LockedAt       sql.NullTime
+	Version        int32
+}
+
+type OmsCampaignOwner struct {
+	CampaignID   int32
+	SalesRepID   int32
+	SplitPercent string
+}
+
+type OmsCommissionPlan struct {
 	ID          int32
-	CampaignID  int32
 	Name        string
-	Booked      string
-	Actual      sql.NullString
-	Adjustments sql.NullString
-	StartedAt   sql.NullTime
-	EndedAt     sql.NullTime
+	Basis       string
+	RatePercent string
 	CreatedAt   sql.NullTime
 	UpdatedAt   sql.NullTime
 }
@@ -42,4 +68,74 @@ type OmsInvoice struct {
 	IssuedAt          sql.NullTime
 	CreatedAt         sql.NullTime
 	UpdatedAt         sql.NullTime
+	DeletedAt         sql.NullTime
+	CollectedAt       sql.NullTime
+	Version           int32
+}
+
+type OmsInvoiceLineItem struct {
+	InvoiceID  int32
+	LineItemID int32
+}
+
+type OmsLineItemDelivery struct {
+	LineItemID   int32
+	DeliveryDate time.Time
+	Impressions  int64
+	Clicks       int64
+	Conversions  int64
+	Spend        string
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+}
+
+type OmsLineItemHistory struct {
+	ID             int32
+	LineItemID     int32
+	Event          string
+	FromCampaignID sql.NullInt32
+	ToCampaignID   sql.NullInt32
+	Reason         sql.NullString
+	CreatedAt      sql.NullTime
+}
+
+type OmsSalesRep struct {
+	ID               int32
+	Name             string
+	Email            sql.NullString
+	CommissionPlanID sql.NullInt32
+	CreatedAt        sql.NullTime
+	UpdatedAt        sql.NullTime
+	Version          int32
+}
+
+type OmsWebhookDelivery struct {
+	ID             int64
+	SubscriptionID int32
+	EventID        int64
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	ResponseStatus sql.NullInt32
+	LastError      sql.NullString
+	DeliveredAt    sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+}
+
+type OmsWebhookEvent struct {
+	ID        int64
+	EventType string
+	Payload   json.RawMessage
+	CreatedAt sql.NullTime
+}
+
+type OmsWebhookSubscription struct {
+	ID         int32
+	Url        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  sql.NullTime
+	UpdatedAt  sql.NullTime
+	Version    int32
 }
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+var errTxNotSupported = errors.New("queries are not backed by a connection that can begin transactions")
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// ExecTx runs fn with queries bound to a single transaction. The transaction
+// is committed when fn returns nil and rolled back otherwise.
+func (q *Queries) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	beginner, ok := q.db.(txBeginner)
+	if !ok {
+		return errTxNotSupported
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot begin transaction")
+	}
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Wrapf(err, "rollback failed: %s", rbErr.Error())
+		}
+
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "cannot commit transaction")
+}
@@ -0,0 +1,145 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+const maxDeliveryRecords = 10000
+
+// ingestDelivery stores the daily delivery records, replacing days that were
+// ingested before, and recomputes the actuals of the line items they belong to.
+// Nothing is stored when any record is invalid.
+func ingestDelivery(ctx context.Context, dbQueries *db.Queries,
+	records []*models.LineItemDelivery) (*models.DeliveryIngestResult, error) {
+	if len(records) > maxDeliveryRecords {
+		return nil, newValidationError([]models.FieldError{{
+			Field:   "Records",
+			Message: fmt.Sprintf("at most %d records can be ingested at once", maxDeliveryRecords),
+		}})
+	}
+
+	var fieldErrors []models.FieldError
+
+	var lineItemIDs []int32
+
+	seen := map[int32]bool{}
+
+	for i, record := range records {
+		for _, fieldError := range record.Validate() {
+			fieldError.Field = fmt.Sprintf("Records[%d].%s", i, fieldError.Field)
+			fieldErrors = append(fieldErrors, fieldError)
+		}
+
+		id := int32(record.LineItemID)
+		if !seen[id] {
+			seen[id] = true
+
+			lineItemIDs = append(lineItemIDs, id)
+		}
+	}
+
+	if err := newValidationError(fieldErrors); err != nil {
+		return nil, err
+	}
+
+	// Line items are locked in id order, like invoice generation locks them.
+	slices.Sort(lineItemIDs)
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := ensureLineItemsExist(ctx, q, lineItemIDs); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if err := q.UpsertLineItemDelivery(ctx, record.ToUpsertLineItemDelivery()); err != nil {
+				return errors.Wrapf(err, "cannot store delivery of line item %d", record.LineItemID)
+			}
+		}
+
+		for _, id := range lineItemIDs {
+			if err := recomputeLineItemActual(ctx, q, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeliveryIngestResult{Records: len(records), LineItems: len(lineItemIDs)}, nil
+}
+
+// ensureLineItemsExist locks the line items and checks that they exist and, as
+// delivery changes their actuals, that none of them is locked by an invoice.
+func ensureLineItemsExist(ctx context.Context, dbQueries *db.Queries, ids []int32) error {
+	var fieldErrors []models.FieldError
+
+	for _, id := range ids {
+		campaignLine, err := dbQueries.GetCampaignLineForUpdate(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   "LineItemID",
+				Message: fmt.Sprintf("line item %d does not exist", id),
+			})
+
+			continue
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "cannot get line item %d", id)
+		}
+
+		if err := ensureCampaignLineUnlocked(&campaignLine); err != nil {
+			return errors.Wrapf(err, "line item %d", id)
+		}
+	}
+
+	return newValidationError(fieldErrors)
+}
+
+// recomputeLineItemActual derives the delivered units and actual of a line item
+// from all of its delivery and sends the line item as updated.
+func recomputeLineItemActual(ctx context.Context, dbQueries *db.Queries, id int32) error {
+	campaignLine, err := dbQueries.GetCampaignLine(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get line item %d", id)
+	}
+
+	lineItem, err := models.NewCampaignLineItemFromDB(&campaignLine)
+	if err != nil {
+		return err
+	}
+
+	totalsRow, err := dbQueries.GetLineItemDeliveryTotals(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "cannot sum the delivery of line item %d", id)
+	}
+
+	totals, err := models.NewDeliveryTotalsFromDB(&totalsRow)
+	if err != nil {
+		return err
+	}
+
+	lineItem.ApplyDelivery(totals)
+
+	err = dbQueries.UpdateCampaignLineDelivery(ctx, db.UpdateCampaignLineDeliveryParams{
+		ID:             id,
+		DeliveredUnits: lineItem.DeliveredUnits,
+		Actual:         sql.NullString{Valid: true, String: strconv.FormatFloat(lineItem.Actual, 'f', -1, 64)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "cannot update the actual of line item %d", id)
+	}
+
+	return emitLineItemEvent(ctx, dbQueries, models.WebhookEventLineItemUpdated, id)
+}
@@ -0,0 +1,83 @@
+package oms
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/gin-gonic/gin"
+)
+
+type deliveryController struct {
+	dbQueries *db.Queries
+	logger    *slog.Logger
+}
+
+func newDeliveryController(logger *slog.Logger, dbQueries *db.Queries) *deliveryController {
+	return &deliveryController{dbQueries: dbQueries, logger: logger}
+}
+
+// register adds the delivery ingestion and listing routes.
+func (s *deliveryController) register(router gin.IRoutes) {
+	router.POST("/lineItemDelivery", s.ingest)
+	router.GET("/campaignLineItems/:id/delivery", s.list)
+}
+
+func (s *deliveryController) ingest(c *gin.Context) {
+	var req []*models.LineItemDelivery
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	result, err := ingestDelivery(c.Request.Context(), s.dbQueries, req)
+
+	if err != nil {
+		s.logger.Error("Error ingesting delivery", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		respondError(c, err)
+
+		return
+	}
+
+	respond(c, http.StatusOK, result)
+}
+
+func (s *deliveryController) list(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	_, err = s.dbQueries.GetCampaignLine(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign line item not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	delivery, err := s.dbQueries.ListLineItemDelivery(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	deliveryResp := &models.List[models.LineItemDelivery]{Items: make([]*models.LineItemDelivery, len(delivery))}
+
+	for i := range delivery {
+		deliveryResp.Items[i], err = models.NewLineItemDeliveryFromDB(&delivery[i])
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	respond(c, http.StatusOK, deliveryResp)
+}
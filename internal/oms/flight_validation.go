@@ -0,0 +1,72 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+// validationError carries the field level problems that made a request invalid.
+type validationError struct {
+	fields []models.FieldError
+}
+
+func newValidationError(fields []models.FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &validationError{fields: fields}
+}
+
+func (e *validationError) Error() string {
+	problems := make([]string, len(e.fields))
+	for i, field := range e.fields {
+		problems[i] = field.Field + " " + field.Message
+	}
+
+	return "validation failed: " + strings.Join(problems, "; ")
+}
+
+// validateCampaignFlight checks the flight of an existing campaign, its line items
+// must still fall within the new flight.
+func validateCampaignFlight(ctx context.Context, dbQueries *db.Queries, id int32, campaign *models.Campaign) error {
+	fieldErrors := campaign.ValidateFlight()
+
+	bounds, err := dbQueries.GetCampaignLineFlightBounds(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "cannot get the flight bounds of the line items")
+	}
+
+	fieldErrors = append(fieldErrors,
+		campaign.ValidateLineItemBounds(nullTimeToPtr(bounds.Earliest), nullTimeToPtr(bounds.Latest))...)
+
+	return newValidationError(fieldErrors)
+}
+
+// validateCampaignLineFlight checks the flight of a line item against its campaign.
+func validateCampaignLineFlight(ctx context.Context, dbQueries *db.Queries, item *models.CampaignLineItem) error {
+	campaign, err := dbQueries.GetCampaign(ctx, int32(item.CampaignID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return newValidationError([]models.FieldError{{Field: "CampaignID", Message: "campaign does not exist"}})
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "cannot get the campaign of the line item")
+	}
+
+	return newValidationError(item.ValidateFlight(models.NewCampaignFromDB(&campaign)))
+}
+
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
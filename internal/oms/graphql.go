@@ -0,0 +1,112 @@
+package oms
+
+import (
+	"context"
+	_ "embed"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	graphQLPath     = "/graphql"
+	graphQLMaxDepth = 8
+)
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+// graphQLAPI serves the GraphQL API over the same domain functions as the REST
+// and gRPC APIs.
+type graphQLAPI struct {
+	schema   *graphql.Schema
+	resolver *graphQLResolver
+	logger   *slog.Logger
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func newGraphQLAPI(logger *slog.Logger, dbQueries *db.Queries, tokens *tokenCodec) (*graphQLAPI, error) {
+	resolver := newGraphQLResolver(logger, dbQueries, tokens)
+
+	schema, err := graphql.ParseSchema(graphQLSchema, resolver, graphql.MaxDepth(graphQLMaxDepth))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse the GraphQL schema")
+	}
+
+	return &graphQLAPI{schema: schema, resolver: resolver, logger: logger}, nil
+}
+
+func (a *graphQLAPI) register(router gin.IRoutes) {
+	router.POST(graphQLPath, a.serve)
+}
+
+// serve runs a query with loaders of its own, so the records of one query are
+// batched but never cached across queries.
+func (a *graphQLAPI) serve(c *gin.Context) {
+	var req graphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), graphQLLoadersKey{}, newGraphQLLoaders(a.resolver))
+
+	resp := a.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	for _, queryErr := range resp.Errors {
+		if queryErr.ResolverError == nil {
+			continue
+		}
+
+		problem := problemFor(queryErr.ResolverError)
+		if problem.Status >= http.StatusInternalServerError {
+			_ = c.Error(queryErr.ResolverError)
+		}
+
+		queryErr.Message = problem.Detail
+		queryErr.Extensions = map[string]interface{}{"code": problem.Code}
+
+		if len(problem.Errors) > 0 {
+			queryErr.Extensions["errors"] = graphQLFieldErrors(problem.Errors)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// graphQLFieldErrors names the fields of a failed validation like the schema does.
+func graphQLFieldErrors(fieldErrors []models.FieldError) []map[string]string {
+	renamed := make([]map[string]string, len(fieldErrors))
+
+	for i, fieldErr := range fieldErrors {
+		renamed[i] = map[string]string{"field": graphQLFieldName(fieldErr.Field), "message": fieldErr.Message}
+	}
+
+	return renamed
+}
+
+// graphQLFieldName turns the Go name of a field into its lowerCamel name, CampaignID
+// becomes campaignId.
+func graphQLFieldName(goName string) string {
+	words := strings.Split(v1.FieldName(goName), "_")
+
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	return strings.Join(words, "")
+}
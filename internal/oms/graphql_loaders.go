@@ -0,0 +1,240 @@
+package oms
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+type graphQLLoadersKey struct{}
+
+// batchLoader loads values by key in batches. The keys are primed as the records
+// they belong to are resolved, and the first load of any key fetches every primed
+// key that is not loaded yet with one query, so the resolvers of a list of
+// records share a query instead of running one each.
+type batchLoader[K comparable, V any] struct {
+	mu     sync.Mutex
+	fetch  func(ctx context.Context, keys []K) (map[K]V, error)
+	primed []K
+	loaded map[K]V
+}
+
+func newBatchLoader[K comparable, V any](fetch func(context.Context, []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{fetch: fetch, loaded: map[K]V{}}
+}
+
+func (l *batchLoader[K, V]) prime(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.primed = append(l.primed, keys...)
+}
+
+// load returns the value of the key, the zero value when the fetch found none.
+func (l *batchLoader[K, V]) load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if value, ok := l.loaded[key]; ok {
+		return value, nil
+	}
+
+	keys := []K{key}
+	seen := map[K]bool{key: true}
+
+	for _, primed := range l.primed {
+		if _, ok := l.loaded[primed]; !ok && !seen[primed] {
+			keys = append(keys, primed)
+			seen[primed] = true
+		}
+	}
+
+	values, err := l.fetch(ctx, keys)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	for _, k := range keys {
+		l.loaded[k] = values[k]
+	}
+
+	l.primed = nil
+
+	return l.loaded[key], nil
+}
+
+// graphQLLoaders batch the relations of the records of one GraphQL request.
+type graphQLLoaders struct {
+	resolver  *graphQLResolver
+	campaigns *batchLoader[int32, *models.Campaign]
+
+	// The first pages of the line items and invoices of the campaigns, a loader for
+	// every page size asked for. A loader is keyed by campaign and fetches one more
+	// record per campaign than the page holds, to tell whether there is a next page.
+	mu            sync.Mutex
+	campaignIDs   []int32
+	lineItemPages map[int32]*batchLoader[int32, []*models.CampaignLineItem]
+	invoicePages  map[int32]*batchLoader[int32, []*models.Invoice]
+}
+
+func newGraphQLLoaders(resolver *graphQLResolver) *graphQLLoaders {
+	return &graphQLLoaders{
+		resolver:      resolver,
+		lineItemPages: map[int32]*batchLoader[int32, []*models.CampaignLineItem]{},
+		invoicePages:  map[int32]*batchLoader[int32, []*models.Invoice]{},
+		campaigns: newBatchLoader(func(ctx context.Context, ids []int32) (map[int32]*models.Campaign, error) {
+			campaigns, err := resolver.dbQueries.ListCampaignsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			byID := make(map[int32]*models.Campaign, len(campaigns))
+			for i := range campaigns {
+				byID[campaigns[i].ID] = models.NewCampaignFromDB(&campaigns[i])
+			}
+
+			return byID, nil
+		}),
+	}
+}
+
+func graphQLLoadersFrom(ctx context.Context) *graphQLLoaders {
+	return ctx.Value(graphQLLoadersKey{}).(*graphQLLoaders)
+}
+
+// campaignResolvers wraps campaigns for the resolvers and primes the loaders of
+// the pages of their relations.
+func (l *graphQLLoaders) campaignResolvers(campaigns []*models.Campaign) []*campaignResolver {
+	resolvers := make([]*campaignResolver, len(campaigns))
+	ids := make([]int32, len(campaigns))
+
+	for i, campaign := range campaigns {
+		resolvers[i] = &campaignResolver{campaign: campaign, resolver: l.resolver}
+		ids[i] = int32(campaign.ID)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.campaignIDs = append(l.campaignIDs, ids...)
+
+	for _, loader := range l.lineItemPages {
+		loader.prime(ids...)
+	}
+
+	for _, loader := range l.invoicePages {
+		loader.prime(ids...)
+	}
+
+	return resolvers
+}
+
+// lineItemPage loads the first size line items of a campaign, and one more when
+// the campaign has more.
+func (l *graphQLLoaders) lineItemPage(ctx context.Context, campaignID, size int32) (
+	[]*models.CampaignLineItem, error) {
+	l.mu.Lock()
+	loader := pageLoader(l, l.lineItemPages, size, func(ctx context.Context, ids []int32) (
+		map[int32][]*models.CampaignLineItem, error) {
+		lines, err := l.resolver.dbQueries.ListCampaignLinesForCampaigns(ctx, db.ListCampaignLinesForCampaignsParams{
+			CampaignIds:    ids,
+			MaxPerCampaign: size + 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		byCampaign := make(map[int32][]*models.CampaignLineItem, len(ids))
+		for i := range lines {
+			line, err := models.NewCampaignLineItemFromDB(&lines[i])
+			if err != nil {
+				return nil, err
+			}
+
+			byCampaign[lines[i].CampaignID] = append(byCampaign[lines[i].CampaignID], line)
+		}
+
+		return byCampaign, nil
+	})
+	l.mu.Unlock()
+
+	return loader.load(ctx, campaignID)
+}
+
+// invoicePage loads the first size invoices of a campaign, and one more when the
+// campaign has more.
+func (l *graphQLLoaders) invoicePage(ctx context.Context, campaignID, size int32) ([]*models.Invoice, error) {
+	l.mu.Lock()
+	loader := pageLoader(l, l.invoicePages, size, func(ctx context.Context, ids []int32) (
+		map[int32][]*models.Invoice, error) {
+		invoices, err := l.resolver.dbQueries.ListInvoicesForCampaigns(ctx, db.ListInvoicesForCampaignsParams{
+			CampaignIds:    ids,
+			MaxPerCampaign: size + 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		byCampaign := make(map[int32][]*models.Invoice, len(ids))
+		for i := range invoices {
+			invoice, err := models.NewInvoiceFromDB(invoices[i])
+			if err != nil {
+				return nil, err
+			}
+
+			byCampaign[invoices[i].CampaignID] = append(byCampaign[invoices[i].CampaignID], invoice)
+		}
+
+		return byCampaign, nil
+	})
+	l.mu.Unlock()
+
+	return loader.load(ctx, campaignID)
+}
+
+// pageLoader returns the loader of the pages of size, a new one is primed with
+// every campaign resolved so far. The caller holds l.mu.
+func pageLoader[V any](l *graphQLLoaders, loaders map[int32]*batchLoader[int32, []*V], size int32,
+	fetch func(context.Context, []int32) (map[int32][]*V, error)) *batchLoader[int32, []*V] {
+	loader, ok := loaders[size]
+	if !ok {
+		loader = newBatchLoader(fetch)
+		loader.prime(l.campaignIDs...)
+		loaders[size] = loader
+	}
+
+	return loader
+}
+
+// lineItemResolvers wraps line items for the resolvers and primes the loader of their campaigns.
+func (l *graphQLLoaders) lineItemResolvers(items []*models.CampaignLineItem) []*lineItemResolver {
+	resolvers := make([]*lineItemResolver, len(items))
+	ids := make([]int32, len(items))
+
+	for i, item := range items {
+		resolvers[i] = &lineItemResolver{item: item}
+		ids[i] = int32(item.CampaignID)
+	}
+
+	l.campaigns.prime(ids...)
+
+	return resolvers
+}
+
+// invoiceResolvers wraps invoices for the resolvers and primes the loader of their campaigns.
+func (l *graphQLLoaders) invoiceResolvers(invoices []*models.Invoice) []*invoiceResolver {
+	resolvers := make([]*invoiceResolver, len(invoices))
+	ids := make([]int32, len(invoices))
+
+	for i, invoice := range invoices {
+		resolvers[i] = &invoiceResolver{invoice: invoice}
+		ids[i] = int32(invoice.CampaignID)
+	}
+
+	l.campaigns.prime(ids...)
+
+	return resolvers
+}
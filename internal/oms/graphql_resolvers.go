@@ -0,0 +1,549 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+)
+
+var (
+	errInvalidGraphQLID    = newAPIError(http.StatusBadRequest, models.ProblemInvalidRequest, "the ID is not valid")
+	errConnectionDirection = newAPIError(http.StatusBadRequest, models.ProblemInvalidRequest,
+		"page forward with first and after or backward with last and before, not both")
+	errLastWithoutBefore = newAPIError(http.StatusBadRequest, models.ProblemInvalidRequest,
+		"last pages backward and needs a before cursor")
+	errConnectionSize = newAPIError(http.StatusBadRequest, models.ProblemInvalidRequest,
+		fmt.Sprintf("first and last must be between 1 and %d", maxListLimit))
+)
+
+// graphQLResolver resolves the queries and mutations of the GraphQL schema.
+type graphQLResolver struct {
+	dbQueries *db.Queries
+	logger    *slog.Logger
+	tokens    *tokenCodec
+}
+
+func newGraphQLResolver(logger *slog.Logger, dbQueries *db.Queries, tokens *tokenCodec) *graphQLResolver {
+	return &graphQLResolver{dbQueries: dbQueries, logger: logger, tokens: tokens}
+}
+
+type getArgs struct {
+	ID             graphql.ID
+	IncludeDeleted bool
+}
+
+// connectionArgs page through a list like the REST API does, the cursors are its
+// page tokens.
+type connectionArgs struct {
+	First          *int32
+	After          *string
+	Last           *int32
+	Before         *string
+	Filter         *string
+	OrderBy        *string
+	IncludeDeleted bool
+}
+
+// firstPage reports whether the arguments ask for the first page of the live records
+// in the default order, which is loaded for every campaign of a request at once.
+func (a *connectionArgs) firstPage() bool {
+	return a.After == nil && a.Before == nil && a.Last == nil && a.Filter == nil && a.OrderBy == nil &&
+		!a.IncludeDeleted
+}
+
+type lineItemsArgs struct {
+	CampaignID *graphql.ID
+	connectionArgs
+}
+
+type campaignInput struct {
+	Name      string
+	StartedAt *graphql.Time
+	EndedAt   *graphql.Time
+}
+
+type lineItemInput struct {
+	CampaignID     *graphql.ID
+	Name           string
+	Booked         *float64
+	Actual         *float64
+	Adjustments    *float64
+	StartedAt      *graphql.Time
+	EndedAt        *graphql.Time
+	PricingModel   *string
+	UnitRate       *float64
+	BookedUnits    *int32
+	DeliveredUnits *int32
+}
+
+type invoiceInput struct {
+	CampaignID        graphql.ID
+	TotalBookedAmount *float64
+	TotalActualAmount *float64
+	TotalAdjustments  *float64
+	StartedAt         *graphql.Time
+	EndedAt           *graphql.Time
+	IssuedAt          *graphql.Time
+}
+
+func (r *graphQLResolver) Campaign(ctx context.Context, args getArgs) (*campaignResolver, error) {
+	id, err := idFromGraphQL(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := getCampaign(ctx, r.dbQueries, id, args.IncludeDeleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).campaignResolvers([]*models.Campaign{campaign})[0], nil
+}
+
+func (r *graphQLResolver) Campaigns(ctx context.Context,
+	args connectionArgs) (*connection[*campaignResolver], error) {
+	req, err := r.newConnectionRequest("graphql:campaigns", campaignListSchema, &args)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := listCampaigns(ctx, r.dbQueries, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnection(req, page, func(c *models.Campaign) int { return c.ID },
+		graphQLLoadersFrom(ctx).campaignResolvers(page.Items),
+		func(ctx context.Context) (*int64, error) { return countCampaigns(ctx, r.dbQueries, req) }), nil
+}
+
+func (r *graphQLResolver) LineItem(ctx context.Context, args getArgs) (*lineItemResolver, error) {
+	id, err := idFromGraphQL(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := getCampaignLine(ctx, r.dbQueries, id, args.IncludeDeleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).lineItemResolvers([]*models.CampaignLineItem{item})[0], nil
+}
+
+func (r *graphQLResolver) LineItems(ctx context.Context, args lineItemsArgs) (*connection[*lineItemResolver], error) {
+	var campaignID int32
+
+	if args.CampaignID != nil {
+		var err error
+
+		campaignID, err = idFromGraphQL(*args.CampaignID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.lineItemConnection(ctx, campaignID, &args.connectionArgs, listCampaignLines)
+}
+
+// lineItemConnection pages through the line items of a campaign, or of every
+// campaign when campaignID is 0, with the list function of the caller.
+func (r *graphQLResolver) lineItemConnection(ctx context.Context, campaignID int32, args *connectionArgs,
+	list func(context.Context, *db.Queries, *listRequest, int32) (*models.List[models.CampaignLineItem], error)) (
+	*connection[*lineItemResolver], error) {
+	req, err := r.newConnectionRequest(fmt.Sprintf("graphql:lineItems:%d", campaignID), campaignLineItemListSchema,
+		args)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := list(ctx, r.dbQueries, req, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnection(req, page, func(l *models.CampaignLineItem) int { return l.ID },
+		graphQLLoadersFrom(ctx).lineItemResolvers(page.Items),
+		func(ctx context.Context) (*int64, error) {
+			return countCampaignLines(ctx, r.dbQueries, req, campaignID)
+		}), nil
+}
+
+// campaignLineItemConnection pages through the line items of a campaign the caller
+// already found. The first page is loaded together with those of the other campaigns.
+func (r *graphQLResolver) campaignLineItemConnection(ctx context.Context, campaignID int32, args *connectionArgs) (
+	*connection[*lineItemResolver], error) {
+	if !args.firstPage() {
+		return r.lineItemConnection(ctx, campaignID, args, pageCampaignLines)
+	}
+
+	req, err := r.newConnectionRequest(fmt.Sprintf("graphql:lineItems:%d", campaignID), campaignLineItemListSchema,
+		args)
+	if err != nil {
+		return nil, err
+	}
+
+	loaders := graphQLLoadersFrom(ctx)
+
+	items, err := loaders.lineItemPage(ctx, campaignID, req.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	id := func(l *models.CampaignLineItem) int { return l.ID }
+	page := newFirstPage(req, items, id)
+
+	return newConnection(req, page, id, loaders.lineItemResolvers(page.Items),
+		func(ctx context.Context) (*int64, error) {
+			return countCampaignLines(ctx, r.dbQueries, req, campaignID)
+		}), nil
+}
+
+func (r *graphQLResolver) Invoice(ctx context.Context, args getArgs) (*invoiceResolver, error) {
+	id, err := idFromGraphQL(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := getInvoice(ctx, r.dbQueries, id, args.IncludeDeleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).invoiceResolvers([]*models.Invoice{invoice})[0], nil
+}
+
+func (r *graphQLResolver) Invoices(ctx context.Context, args connectionArgs) (*connection[*invoiceResolver], error) {
+	return r.invoiceConnection(ctx, 0, &args)
+}
+
+// invoiceConnection pages through the invoices of a campaign, or of every campaign
+// when campaignID is 0.
+func (r *graphQLResolver) invoiceConnection(ctx context.Context, campaignID int32, args *connectionArgs) (
+	*connection[*invoiceResolver], error) {
+	req, err := r.newConnectionRequest(fmt.Sprintf("graphql:invoices:%d", campaignID), invoiceListSchema, args)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := listInvoices(ctx, r.dbQueries, req, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnection(req, page, func(i *models.Invoice) int { return i.ID },
+		graphQLLoadersFrom(ctx).invoiceResolvers(page.Items),
+		func(ctx context.Context) (*int64, error) { return countInvoices(ctx, r.dbQueries, req, campaignID) }), nil
+}
+
+// campaignInvoiceConnection pages through the invoices of a campaign the caller
+// already found. The first page is loaded together with those of the other campaigns.
+func (r *graphQLResolver) campaignInvoiceConnection(ctx context.Context, campaignID int32, args *connectionArgs) (
+	*connection[*invoiceResolver], error) {
+	if !args.firstPage() {
+		return r.invoiceConnection(ctx, campaignID, args)
+	}
+
+	req, err := r.newConnectionRequest(fmt.Sprintf("graphql:invoices:%d", campaignID), invoiceListSchema, args)
+	if err != nil {
+		return nil, err
+	}
+
+	loaders := graphQLLoadersFrom(ctx)
+
+	invoices, err := loaders.invoicePage(ctx, campaignID, req.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	id := func(i *models.Invoice) int { return i.ID }
+	page := newFirstPage(req, invoices, id)
+
+	return newConnection(req, page, id, loaders.invoiceResolvers(page.Items),
+		func(ctx context.Context) (*int64, error) { return countInvoices(ctx, r.dbQueries, req, campaignID) }), nil
+}
+
+func (r *graphQLResolver) CreateCampaign(ctx context.Context, args struct{ Input campaignInput }) (
+	*campaignResolver, error) {
+	campaign, err := createCampaign(ctx, r.logger, r.dbQueries, args.Input.toModel())
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).campaignResolvers([]*models.Campaign{campaign})[0], nil
+}
+
+func (r *graphQLResolver) UpdateCampaign(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version int32
+	Input   campaignInput
+}) (*campaignResolver, error) {
+	id, err := idFromGraphQL(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := versionMatch(args.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := replaceCampaign(ctx, r.dbQueries, id, args.Input.toModel(), match)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).campaignResolvers([]*models.Campaign{campaign})[0], nil
+}
+
+func (r *graphQLResolver) CreateLineItem(ctx context.Context, args struct{ Input lineItemInput }) (
+	*lineItemResolver, error) {
+	item, err := args.Input.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	var id int32
+
+	err = r.dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		id, err = createCampaignLine(ctx, q, item)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := getCampaignLine(ctx, r.dbQueries, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).lineItemResolvers([]*models.CampaignLineItem{created})[0], nil
+}
+
+func (r *graphQLResolver) UpdateLineItem(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version int32
+	Input   lineItemInput
+}) (*lineItemResolver, error) {
+	id, err := idFromGraphQL(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := versionMatch(args.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := args.Input.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := replaceCampaignLine(ctx, r.dbQueries, id, item, match)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).lineItemResolvers([]*models.CampaignLineItem{updated})[0], nil
+}
+
+func (r *graphQLResolver) CreateInvoice(ctx context.Context, args struct{ Input invoiceInput }) (
+	*invoiceResolver, error) {
+	invoice, err := args.Input.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := createInvoice(ctx, r.dbQueries, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.invoice(ctx, id)
+}
+
+func (r *graphQLResolver) AdjustInvoice(ctx context.Context, args struct {
+	ID               graphql.ID
+	TotalAdjustments float64
+}) (*invoiceResolver, error) {
+	id, err := idFromGraphQL(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := adjustInvoice(ctx, r.dbQueries, id, args.TotalAdjustments); err != nil {
+		return nil, err
+	}
+
+	return r.invoice(ctx, id)
+}
+
+func (r *graphQLResolver) invoice(ctx context.Context, id int32) (*invoiceResolver, error) {
+	invoice, err := getInvoice(ctx, r.dbQueries, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphQLLoadersFrom(ctx).invoiceResolvers([]*models.Invoice{invoice})[0], nil
+}
+
+// newConnectionRequest builds the page request of a connection. first and after
+// page forward, last and before page backward, the cursors are page tokens the
+// list issued and carry its filter and orderBy.
+func (r *graphQLResolver) newConnectionRequest(path string, schema listSchema,
+	args *connectionArgs) (*listRequest, error) {
+	forward := args.First != nil || args.After != nil
+	backward := args.Last != nil || args.Before != nil
+
+	switch {
+	case forward && backward:
+		return nil, errConnectionDirection
+	case args.Last != nil && args.Before == nil:
+		return nil, errLastWithoutBefore
+	}
+
+	size := args.First
+	if args.Last != nil {
+		size = args.Last
+	}
+
+	if size != nil && (*size < 1 || *size > maxListLimit) {
+		return nil, errConnectionSize
+	}
+
+	opts := &listOptions{path: path, includeDeleted: args.IncludeDeleted}
+
+	if args.Filter != nil {
+		opts.filter, opts.hasFilter = *args.Filter, true
+	}
+
+	if args.OrderBy != nil {
+		opts.orderBy, opts.hasOrderBy = *args.OrderBy, true
+	}
+
+	cursor := args.After
+	if args.Before != nil {
+		cursor = args.Before
+	}
+
+	if cursor != nil {
+		token, err := r.tokens.decode(*cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.token = &token
+	}
+
+	req, err := newListRequest(r.tokens, schema[apiUnversioned], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if size != nil {
+		req.limit = *size
+	}
+
+	if args.Before != nil {
+		req.after.Backward = true
+	}
+
+	return req, nil
+}
+
+func (i *campaignInput) toModel() *models.Campaign {
+	return &models.Campaign{
+		Name:      i.Name,
+		StartedAt: timeFromGraphQL(i.StartedAt),
+		EndedAt:   timeFromGraphQL(i.EndedAt),
+	}
+}
+
+func (i *lineItemInput) toModel() (*models.CampaignLineItem, error) {
+	item := &models.CampaignLineItem{
+		Name:           i.Name,
+		Booked:         valueOrZero(i.Booked),
+		Actual:         valueOrZero(i.Actual),
+		Adjustments:    valueOrZero(i.Adjustments),
+		StartedAt:      timeFromGraphQL(i.StartedAt),
+		EndedAt:        timeFromGraphQL(i.EndedAt),
+		PricingModel:   valueOrZero(i.PricingModel),
+		UnitRate:       valueOrZero(i.UnitRate),
+		BookedUnits:    int64(valueOrZero(i.BookedUnits)),
+		DeliveredUnits: int64(valueOrZero(i.DeliveredUnits)),
+	}
+
+	if i.CampaignID != nil {
+		campaignID, err := idFromGraphQL(*i.CampaignID)
+		if err != nil {
+			return nil, err
+		}
+
+		item.CampaignID = int(campaignID)
+	}
+
+	return item, nil
+}
+
+func (i *invoiceInput) toModel() (*models.Invoice, error) {
+	campaignID, err := idFromGraphQL(i.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &models.Invoice{
+		CampaignID:        int(campaignID),
+		TotalBookedAmount: valueOrZero(i.TotalBookedAmount),
+		TotalActualAmount: valueOrZero(i.TotalActualAmount),
+		TotalAdjustments:  valueOrZero(i.TotalAdjustments),
+		StartedAt:         timeFromGraphQL(i.StartedAt),
+		EndedAt:           timeFromGraphQL(i.EndedAt),
+	}
+
+	if i.IssuedAt != nil {
+		invoice.IssuedAt = i.IssuedAt.Time
+	}
+
+	return invoice, nil
+}
+
+func idFromGraphQL(id graphql.ID) (int32, error) {
+	value, err := strconv.ParseInt(string(id), 10, 32)
+	if err != nil {
+		return 0, errInvalidGraphQLID
+	}
+
+	return int32(value), nil
+}
+
+func valueOrZero[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+
+	return *value
+}
@@ -0,0 +1,195 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type campaignResolver struct {
+	campaign *models.Campaign
+	resolver *graphQLResolver
+}
+
+func (r *campaignResolver) ID() graphql.ID           { return graphQLID(r.campaign.ID) }
+func (r *campaignResolver) Name() string             { return r.campaign.Name }
+func (r *campaignResolver) StartedAt() *graphql.Time { return graphQLTime(r.campaign.StartedAt) }
+func (r *campaignResolver) EndedAt() *graphql.Time   { return graphQLTime(r.campaign.EndedAt) }
+func (r *campaignResolver) Archiving() bool          { return r.campaign.Archiving }
+func (r *campaignResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.campaign.CreatedAt} }
+func (r *campaignResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.campaign.UpdatedAt} }
+func (r *campaignResolver) DeletedAt() *graphql.Time { return graphQLTime(r.campaign.DeletedAt) }
+func (r *campaignResolver) Version() int32           { return int32(r.campaign.Version) }
+
+// LineItems pages through the line items of the campaign like the lineItems query
+// does, a page of campaigns cannot load every line item of every campaign.
+func (r *campaignResolver) LineItems(ctx context.Context, args connectionArgs) (
+	*connection[*lineItemResolver], error) {
+	return r.resolver.campaignLineItemConnection(ctx, int32(r.campaign.ID), &args)
+}
+
+// Invoices pages through the invoices of the campaign.
+func (r *campaignResolver) Invoices(ctx context.Context, args connectionArgs) (*connection[*invoiceResolver], error) {
+	return r.resolver.campaignInvoiceConnection(ctx, int32(r.campaign.ID), &args)
+}
+
+type lineItemResolver struct {
+	item *models.CampaignLineItem
+}
+
+func (r *lineItemResolver) ID() graphql.ID           { return graphQLID(r.item.ID) }
+func (r *lineItemResolver) CampaignID() graphql.ID   { return graphQLID(r.item.CampaignID) }
+func (r *lineItemResolver) Name() string             { return r.item.Name }
+func (r *lineItemResolver) Booked() float64          { return r.item.Booked }
+func (r *lineItemResolver) Actual() float64          { return r.item.Actual }
+func (r *lineItemResolver) Adjustments() float64     { return r.item.Adjustments }
+func (r *lineItemResolver) StartedAt() *graphql.Time { return graphQLTime(r.item.StartedAt) }
+func (r *lineItemResolver) EndedAt() *graphql.Time   { return graphQLTime(r.item.EndedAt) }
+func (r *lineItemResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.item.CreatedAt} }
+func (r *lineItemResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.item.UpdatedAt} }
+func (r *lineItemResolver) DeletedAt() *graphql.Time { return graphQLTime(r.item.DeletedAt) }
+func (r *lineItemResolver) PricingModel() string     { return r.item.PricingModel }
+func (r *lineItemResolver) UnitRate() float64        { return r.item.UnitRate }
+func (r *lineItemResolver) BookedUnits() float64     { return float64(r.item.BookedUnits) }
+func (r *lineItemResolver) DeliveredUnits() float64  { return float64(r.item.DeliveredUnits) }
+func (r *lineItemResolver) LockedAt() *graphql.Time  { return graphQLTime(r.item.LockedAt) }
+func (r *lineItemResolver) Version() int32           { return int32(r.item.Version) }
+
+func (r *lineItemResolver) Campaign(ctx context.Context) (*campaignResolver, error) {
+	return loadCampaign(ctx, r.item.CampaignID)
+}
+
+type invoiceResolver struct {
+	invoice *models.Invoice
+}
+
+func (r *invoiceResolver) ID() graphql.ID             { return graphQLID(r.invoice.ID) }
+func (r *invoiceResolver) CampaignID() graphql.ID     { return graphQLID(r.invoice.CampaignID) }
+func (r *invoiceResolver) TotalBookedAmount() float64 { return r.invoice.TotalBookedAmount }
+func (r *invoiceResolver) TotalActualAmount() float64 { return r.invoice.TotalActualAmount }
+func (r *invoiceResolver) TotalAdjustments() float64  { return r.invoice.TotalAdjustments }
+func (r *invoiceResolver) StartedAt() *graphql.Time   { return graphQLTime(r.invoice.StartedAt) }
+func (r *invoiceResolver) EndedAt() *graphql.Time     { return graphQLTime(r.invoice.EndedAt) }
+func (r *invoiceResolver) CollectedAt() *graphql.Time { return graphQLTime(r.invoice.CollectedAt) }
+func (r *invoiceResolver) CreatedAt() graphql.Time    { return graphql.Time{Time: r.invoice.CreatedAt} }
+func (r *invoiceResolver) UpdatedAt() graphql.Time    { return graphql.Time{Time: r.invoice.UpdatedAt} }
+func (r *invoiceResolver) DeletedAt() *graphql.Time   { return graphQLTime(r.invoice.DeletedAt) }
+func (r *invoiceResolver) Version() int32             { return int32(r.invoice.Version) }
+
+// IssuedAt is null for invoices that were not issued.
+func (r *invoiceResolver) IssuedAt() *graphql.Time {
+	if r.invoice.IssuedAt.IsZero() {
+		return nil
+	}
+
+	return &graphql.Time{Time: r.invoice.IssuedAt}
+}
+
+func (r *invoiceResolver) Campaign(ctx context.Context) (*campaignResolver, error) {
+	return loadCampaign(ctx, r.invoice.CampaignID)
+}
+
+// loadCampaign loads the campaign a line item or invoice belongs to, deleted or not.
+func loadCampaign(ctx context.Context, id int) (*campaignResolver, error) {
+	loaders := graphQLLoadersFrom(ctx)
+
+	campaign, err := loaders.campaigns.load(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return loaders.campaignResolvers([]*models.Campaign{campaign})[0], nil
+}
+
+// connection is a relay connection over a page of a list.
+type connection[R any] struct {
+	edges    []*edge[R]
+	pageInfo *pageInfo
+	count    func(context.Context) (*int64, error)
+}
+
+type edge[R any] struct {
+	cursor string
+	node   R
+}
+
+type pageInfo struct {
+	hasNextPage     bool
+	hasPreviousPage bool
+	startCursor     *string
+	endCursor       *string
+}
+
+// newConnection builds the connection of a page. The cursor of an edge is the
+// token of the page after it, the page before it is read with the same token.
+func newConnection[T any, R any](req *listRequest, page *models.List[T], id func(*T) int, nodes []R,
+	count func(context.Context) (*int64, error)) *connection[R] {
+	conn := &connection[R]{
+		edges: make([]*edge[R], len(nodes)),
+		pageInfo: &pageInfo{
+			hasNextPage:     page.NextPageToken != "",
+			hasPreviousPage: page.PreviousPageToken != "",
+		},
+		count: count,
+	}
+
+	for i, item := range page.Items {
+		conn.edges[i] = &edge[R]{cursor: req.pageToken(req.list.CursorAt(id(item), item)), node: nodes[i]}
+	}
+
+	if len(conn.edges) > 0 {
+		conn.pageInfo.startCursor = &conn.edges[0].cursor
+		conn.pageInfo.endCursor = &conn.edges[len(conn.edges)-1].cursor
+	}
+
+	return conn
+}
+
+func (c *connection[R]) Edges() []*edge[R]   { return c.edges }
+func (c *connection[R]) PageInfo() *pageInfo { return c.pageInfo }
+
+// TotalCount counts the matching records only when the query asks for it.
+func (c *connection[R]) TotalCount(ctx context.Context) (int32, error) {
+	total, err := c.count(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(*total), nil
+}
+
+func (e *edge[R]) Cursor() string { return e.cursor }
+func (e *edge[R]) Node() R        { return e.node }
+
+func (p *pageInfo) HasNextPage() bool     { return p.hasNextPage }
+func (p *pageInfo) HasPreviousPage() bool { return p.hasPreviousPage }
+func (p *pageInfo) StartCursor() *string  { return p.startCursor }
+func (p *pageInfo) EndCursor() *string    { return p.endCursor }
+
+func graphQLID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+func graphQLTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &graphql.Time{Time: *t}
+}
+
+func timeFromGraphQL(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &t.Time
+}
@@ -0,0 +1,183 @@
+package oms
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/omspb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type campaignsService struct {
+	omspb.UnimplementedCampaignServiceServer
+
+	dbQueries *db.Queries
+	logger    *slog.Logger
+	tokens    *tokenCodec
+}
+
+func newCampaignsService(logger *slog.Logger, dbQueries *db.Queries, tokens *tokenCodec) *campaignsService {
+	return &campaignsService{dbQueries: dbQueries, logger: logger, tokens: tokens}
+}
+
+func (s *campaignsService) CreateCampaign(ctx context.Context,
+	req *omspb.CreateCampaignRequest) (*omspb.Campaign, error) {
+	campaign, err := createCampaign(ctx, s.logger, s.dbQueries, campaignFromProto(req.GetCampaign()))
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignToProto(campaign), nil
+}
+
+func (s *campaignsService) GetCampaign(ctx context.Context, req *omspb.GetCampaignRequest) (*omspb.Campaign, error) {
+	campaign, err := getCampaign(ctx, s.dbQueries, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignToProto(campaign), nil
+}
+
+func (s *campaignsService) ListCampaigns(req *omspb.ListCampaignsRequest,
+	stream grpc.ServerStreamingServer[omspb.Campaign]) error {
+	listReq, err := newStreamListRequest(s.tokens, campaignListSchema, req.GetQuery())
+	if err != nil {
+		return err
+	}
+
+	return streamList(listReq,
+		func(r *listRequest) (*models.List[models.Campaign], error) {
+			return listCampaigns(stream.Context(), s.dbQueries, r)
+		},
+		func(c *models.Campaign) int { return c.ID },
+		func(c *models.Campaign) error { return stream.Send(campaignToProto(c)) })
+}
+
+func (s *campaignsService) UpdateCampaign(ctx context.Context,
+	req *omspb.UpdateCampaignRequest) (*omspb.Campaign, error) {
+	match, err := versionMatch(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := replaceCampaign(ctx, s.dbQueries, req.GetId(), campaignFromProto(req.GetCampaign()), match)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignToProto(campaign), nil
+}
+
+func (s *campaignsService) DeleteCampaign(ctx context.Context,
+	req *omspb.DeleteCampaignRequest) (*emptypb.Empty, error) {
+	mode := req.GetMode()
+	if mode == "" {
+		mode = deleteModeRestrict
+	}
+
+	if mode != deleteModeRestrict && mode != deleteModeCascade {
+		return nil, errInvalidDeleteMode
+	}
+
+	match, err := versionMatch(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deleteCampaign(ctx, s.dbQueries, req.GetId(), mode, match); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *campaignsService) RestoreCampaign(ctx context.Context,
+	req *omspb.RestoreCampaignRequest) (*omspb.Campaign, error) {
+	if err := restoreCampaign(ctx, s.dbQueries, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return s.GetCampaign(ctx, &omspb.GetCampaignRequest{Id: req.GetId()})
+}
+
+func (s *campaignsService) GetCampaignSummary(ctx context.Context,
+	req *omspb.GetCampaignSummaryRequest) (*omspb.CampaignSummary, error) {
+	summary, err := getCampaignSummary(ctx, s.dbQueries, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &omspb.CampaignSummary{
+		CampaignId:       int32(summary.CampaignID),
+		LineItemCount:    int32(summary.LineItemCount),
+		TotalBooked:      summary.TotalBooked,
+		TotalActual:      summary.TotalActual,
+		TotalAdjustments: summary.TotalAdjustments,
+		Variance:         summary.Variance,
+		PercentDelivered: summary.PercentDelivered,
+		InvoicedToDate:   summary.InvoicedToDate,
+		Uninvoiced:       summary.Uninvoiced,
+	}, nil
+}
+
+func (s *campaignsService) GenerateInvoice(ctx context.Context,
+	req *omspb.GenerateInvoiceRequest) (*omspb.Invoice, error) {
+	s.logger.Info("Generating Campaign Invoice")
+
+	invoiceID, err := generateCampaignInvoice(ctx, s.dbQueries, req.GetCampaignId())
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := getInvoice(ctx, s.dbQueries, invoiceID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoiceToProto(invoice), nil
+}
+
+func (s *campaignsService) CloneCampaign(ctx context.Context,
+	req *omspb.CloneCampaignRequest) (*omspb.Campaign, error) {
+	s.logger.Info("Cloning campaign", slog.Attr{Key: "campaign_id", Value: slog.IntValue(int(req.GetId()))})
+
+	campaign, err := cloneCampaign(ctx, s.dbQueries, req.GetId(), &models.CloneCampaignRequest{
+		Name:         req.GetName(),
+		OffsetDays:   int(req.GetOffsetDays()),
+		ResetActuals: req.GetResetActuals(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignToProto(models.NewCampaignFromDB(&campaign)), nil
+}
+
+func campaignToProto(c *models.Campaign) *omspb.Campaign {
+	return &omspb.Campaign{
+		Id:        int32(c.ID),
+		Name:      c.Name,
+		StartedAt: timestampToProto(c.StartedAt),
+		EndedAt:   timestampToProto(c.EndedAt),
+		Archiving: c.Archiving,
+		CreatedAt: timestampToProto(&c.CreatedAt),
+		UpdatedAt: timestampToProto(&c.UpdatedAt),
+		DeletedAt: timestampToProto(c.DeletedAt),
+		Version:   int32(c.Version),
+	}
+}
+
+// campaignFromProto reads the fields of a campaign a request may set.
+func campaignFromProto(c *omspb.Campaign) *models.Campaign {
+	return &models.Campaign{
+		ID:        int(c.GetId()),
+		Name:      c.GetName(),
+		StartedAt: timestampFromProto(c.GetStartedAt()),
+		EndedAt:   timestampFromProto(c.GetEndedAt()),
+		Archiving: c.GetArchiving(),
+	}
+}
@@ -0,0 +1,136 @@
+package oms
+
+import (
+	"context"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/omspb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type invoicesService struct {
+	omspb.UnimplementedInvoiceServiceServer
+
+	dbQueries *db.Queries
+	tokens    *tokenCodec
+}
+
+func newInvoicesService(dbQueries *db.Queries, tokens *tokenCodec) *invoicesService {
+	return &invoicesService{dbQueries: dbQueries, tokens: tokens}
+}
+
+func (s *invoicesService) CreateInvoice(ctx context.Context, req *omspb.CreateInvoiceRequest) (*omspb.Invoice, error) {
+	id, err := createInvoice(ctx, s.dbQueries, invoiceFromProto(req.GetInvoice()))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetInvoice(ctx, &omspb.GetInvoiceRequest{Id: id})
+}
+
+func (s *invoicesService) GetInvoice(ctx context.Context, req *omspb.GetInvoiceRequest) (*omspb.Invoice, error) {
+	invoice, err := getInvoice(ctx, s.dbQueries, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	return invoiceToProto(invoice), nil
+}
+
+func (s *invoicesService) ListInvoices(req *omspb.ListInvoicesRequest,
+	stream grpc.ServerStreamingServer[omspb.Invoice]) error {
+	listReq, err := newStreamListRequest(s.tokens, invoiceListSchema, req.GetQuery())
+	if err != nil {
+		return err
+	}
+
+	return streamList(listReq,
+		func(r *listRequest) (*models.List[models.Invoice], error) {
+			return listInvoices(stream.Context(), s.dbQueries, r, 0)
+		},
+		func(i *models.Invoice) int { return i.ID },
+		func(i *models.Invoice) error { return stream.Send(invoiceToProto(i)) })
+}
+
+func (s *invoicesService) AdjustInvoice(ctx context.Context, req *omspb.AdjustInvoiceRequest) (*omspb.Invoice, error) {
+	if err := adjustInvoice(ctx, s.dbQueries, req.GetId(), req.GetTotalAdjustments()); err != nil {
+		return nil, err
+	}
+
+	return s.GetInvoice(ctx, &omspb.GetInvoiceRequest{Id: req.GetId()})
+}
+
+func (s *invoicesService) CollectInvoice(ctx context.Context,
+	req *omspb.CollectInvoiceRequest) (*omspb.Invoice, error) {
+	if err := collectInvoice(ctx, s.dbQueries, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return s.GetInvoice(ctx, &omspb.GetInvoiceRequest{Id: req.GetId()})
+}
+
+func (s *invoicesService) DeleteInvoice(ctx context.Context, req *omspb.DeleteInvoiceRequest) (*emptypb.Empty, error) {
+	match, err := versionMatch(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deleteInvoice(ctx, s.dbQueries, req.GetId(), match); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *invoicesService) RestoreInvoice(ctx context.Context,
+	req *omspb.RestoreInvoiceRequest) (*omspb.Invoice, error) {
+	if err := restoreInvoice(ctx, s.dbQueries, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return s.GetInvoice(ctx, &omspb.GetInvoiceRequest{Id: req.GetId()})
+}
+
+// invoiceToProto leaves the issue date out of invoices that were not issued.
+func invoiceToProto(i *models.Invoice) *omspb.Invoice {
+	invoice := &omspb.Invoice{
+		Id:                int32(i.ID),
+		CampaignId:        int32(i.CampaignID),
+		TotalBookedAmount: i.TotalBookedAmount,
+		TotalActualAmount: i.TotalActualAmount,
+		TotalAdjustments:  i.TotalAdjustments,
+		StartedAt:         timestampToProto(i.StartedAt),
+		EndedAt:           timestampToProto(i.EndedAt),
+		CollectedAt:       timestampToProto(i.CollectedAt),
+		CreatedAt:         timestampToProto(&i.CreatedAt),
+		UpdatedAt:         timestampToProto(&i.UpdatedAt),
+		DeletedAt:         timestampToProto(i.DeletedAt),
+		Version:           int32(i.Version),
+	}
+
+	if !i.IssuedAt.IsZero() {
+		invoice.IssuedAt = timestampToProto(&i.IssuedAt)
+	}
+
+	return invoice
+}
+
+// invoiceFromProto reads the fields of an invoice a request may set.
+func invoiceFromProto(i *omspb.Invoice) *models.Invoice {
+	invoice := &models.Invoice{
+		CampaignID:        int(i.GetCampaignId()),
+		TotalBookedAmount: i.GetTotalBookedAmount(),
+		TotalActualAmount: i.GetTotalActualAmount(),
+		TotalAdjustments:  i.GetTotalAdjustments(),
+		StartedAt:         timestampFromProto(i.GetStartedAt()),
+		EndedAt:           timestampFromProto(i.GetEndedAt()),
+	}
+
+	if issuedAt := timestampFromProto(i.GetIssuedAt()); issuedAt != nil {
+		invoice.IssuedAt = *issuedAt
+	}
+
+	return invoice
+}
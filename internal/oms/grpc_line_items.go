@@ -0,0 +1,144 @@
+package oms
+
+import (
+	"context"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/omspb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type lineItemsService struct {
+	omspb.UnimplementedLineItemServiceServer
+
+	dbQueries *db.Queries
+	tokens    *tokenCodec
+}
+
+func newLineItemsService(dbQueries *db.Queries, tokens *tokenCodec) *lineItemsService {
+	return &lineItemsService{dbQueries: dbQueries, tokens: tokens}
+}
+
+func (s *lineItemsService) CreateLineItem(ctx context.Context,
+	req *omspb.CreateLineItemRequest) (*omspb.LineItem, error) {
+	var id int32
+
+	err := s.dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		id, err = createCampaignLine(ctx, q, lineItemFromProto(req.GetLineItem()))
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLineItem(ctx, &omspb.GetLineItemRequest{Id: id})
+}
+
+func (s *lineItemsService) GetLineItem(ctx context.Context, req *omspb.GetLineItemRequest) (*omspb.LineItem, error) {
+	item, err := getCampaignLine(ctx, s.dbQueries, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	return lineItemToProto(item), nil
+}
+
+func (s *lineItemsService) ListLineItems(req *omspb.ListLineItemsRequest,
+	stream grpc.ServerStreamingServer[omspb.LineItem]) error {
+	listReq, err := newStreamListRequest(s.tokens, campaignLineItemListSchema, req.GetQuery())
+	if err != nil {
+		return err
+	}
+
+	return streamList(listReq,
+		func(r *listRequest) (*models.List[models.CampaignLineItem], error) {
+			return listCampaignLines(stream.Context(), s.dbQueries, r, req.GetCampaignId())
+		},
+		func(l *models.CampaignLineItem) int { return l.ID },
+		func(l *models.CampaignLineItem) error { return stream.Send(lineItemToProto(l)) })
+}
+
+func (s *lineItemsService) UpdateLineItem(ctx context.Context,
+	req *omspb.UpdateLineItemRequest) (*omspb.LineItem, error) {
+	match, err := versionMatch(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := replaceCampaignLine(ctx, s.dbQueries, req.GetId(), lineItemFromProto(req.GetLineItem()), match)
+	if err != nil {
+		return nil, err
+	}
+
+	return lineItemToProto(item), nil
+}
+
+func (s *lineItemsService) DeleteLineItem(ctx context.Context,
+	req *omspb.DeleteLineItemRequest) (*emptypb.Empty, error) {
+	match, err := versionMatch(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		return deleteCampaignLine(ctx, q, req.GetId(), match)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *lineItemsService) RestoreLineItem(ctx context.Context,
+	req *omspb.RestoreLineItemRequest) (*omspb.LineItem, error) {
+	if err := restoreCampaignLine(ctx, s.dbQueries, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return s.GetLineItem(ctx, &omspb.GetLineItemRequest{Id: req.GetId()})
+}
+
+func lineItemToProto(l *models.CampaignLineItem) *omspb.LineItem {
+	return &omspb.LineItem{
+		Id:             int32(l.ID),
+		CampaignId:     int32(l.CampaignID),
+		Name:           l.Name,
+		Booked:         l.Booked,
+		Actual:         l.Actual,
+		Adjustments:    l.Adjustments,
+		StartedAt:      timestampToProto(l.StartedAt),
+		EndedAt:        timestampToProto(l.EndedAt),
+		CreatedAt:      timestampToProto(&l.CreatedAt),
+		UpdatedAt:      timestampToProto(&l.UpdatedAt),
+		DeletedAt:      timestampToProto(l.DeletedAt),
+		PricingModel:   l.PricingModel,
+		UnitRate:       l.UnitRate,
+		BookedUnits:    l.BookedUnits,
+		DeliveredUnits: l.DeliveredUnits,
+		LockedAt:       timestampToProto(l.LockedAt),
+		Version:        int32(l.Version),
+	}
+}
+
+// lineItemFromProto reads the fields of a line item a request may set.
+func lineItemFromProto(l *omspb.LineItem) *models.CampaignLineItem {
+	return &models.CampaignLineItem{
+		ID:             int(l.GetId()),
+		CampaignID:     int(l.GetCampaignId()),
+		Name:           l.GetName(),
+		Booked:         l.GetBooked(),
+		Actual:         l.GetActual(),
+		Adjustments:    l.GetAdjustments(),
+		StartedAt:      timestampFromProto(l.GetStartedAt()),
+		EndedAt:        timestampFromProto(l.GetEndedAt()),
+		PricingModel:   l.GetPricingModel(),
+		UnitRate:       l.GetUnitRate(),
+		BookedUnits:    l.GetBookedUnits(),
+		DeliveredUnits: l.GetDeliveredUnits(),
+	}
+}
@@ -0,0 +1,171 @@
+package oms
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/omspb"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultGRPCAddr = ":9090"
+	grpcErrorDomain = "oms"
+)
+
+// grpcCodes maps the status of a problem to the code of the gRPC status it is
+// answered with, statuses that are not listed are internal.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusConflict:             codes.FailedPrecondition,
+	http.StatusPreconditionFailed:   codes.Aborted,
+	http.StatusPreconditionRequired: codes.FailedPrecondition,
+}
+
+// newGRPCServer serves the campaigns, line items and invoices over gRPC. The
+// services call the same domain functions as the controllers and answer their
+// errors with the codes of the problems the REST API answers them with.
+func newGRPCServer(logger *slog.Logger, dbQueries *db.Queries, tokens *tokenCodec) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryGRPCErrors(logger)),
+		grpc.ChainStreamInterceptor(streamGRPCErrors(logger)),
+	)
+
+	omspb.RegisterCampaignServiceServer(server, newCampaignsService(logger, dbQueries, tokens))
+	omspb.RegisterLineItemServiceServer(server, newLineItemsService(dbQueries, tokens))
+	omspb.RegisterInvoiceServiceServer(server, newInvoicesService(dbQueries, tokens))
+
+	return server
+}
+
+func unaryGRPCErrors(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, grpcError(logger, info.FullMethod, err)
+		}
+
+		return resp, nil
+	}
+}
+
+func streamGRPCErrors(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := handler(srv, stream); err != nil {
+			return grpcError(logger, info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+// grpcError turns an error of a service into a gRPC status. The code of the
+// problem is the reason of its ErrorInfo detail, and the field errors of a failed
+// validation are the violations of a BadRequest detail. Internal errors are logged.
+func grpcError(logger *slog.Logger, method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if ctxErr := status.FromContextError(err); ctxErr.Code() != codes.Unknown {
+		return ctxErr.Err()
+	}
+
+	problem := problemFor(err)
+
+	code, ok := grpcCodes[problem.Status]
+	if !ok {
+		code = codes.Internal
+
+		logger.Error("rpc failed", slog.String("error", err.Error()), slog.String("method", method))
+	}
+
+	st := status.New(code, problem.Detail)
+
+	details := []*errdetails.BadRequest_FieldViolation{}
+	for _, fieldErr := range problem.Errors {
+		details = append(details, &errdetails.BadRequest_FieldViolation{
+			Field:       v1.FieldName(fieldErr.Field),
+			Description: fieldErr.Message,
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{Reason: problem.Code, Domain: grpcErrorDomain})
+	if detailsErr == nil && len(details) > 0 {
+		withDetails, detailsErr = withDetails.WithDetails(&errdetails.BadRequest{FieldViolations: details})
+	}
+
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// newStreamListRequest builds the request of a streamed list. It names the fields
+// like /v1 does and reads the pages itself, so there are no page tokens to hand out.
+func newStreamListRequest(tokens *tokenCodec, schema listSchema, listQuery *omspb.ListQuery) (*listRequest, error) {
+	return newListRequest(tokens, schema[apiV1], &listOptions{
+		filter:         listQuery.GetFilter(),
+		orderBy:        listQuery.GetOrderBy(),
+		includeDeleted: listQuery.GetIncludeDeleted(),
+	})
+}
+
+// streamList sends every item of a list, one page at a time. Every page starts
+// after the last item of the page before, the page size only bounds how many rows
+// are read at once.
+func streamList[T any](req *listRequest, page func(*listRequest) (*models.List[T], error), id func(*T) int,
+	send func(*T) error) error {
+	for {
+		items, err := page(req)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items.Items {
+			if err := send(item); err != nil {
+				return errors.Wrap(err, "cannot send the item")
+			}
+		}
+
+		if len(items.Items) < int(req.limit) {
+			return nil
+		}
+
+		last := items.Items[len(items.Items)-1]
+		cursor := req.list.CursorAt(id(last), last)
+		req.after = &cursor
+	}
+}
+
+func timestampToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func timestampFromProto(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	v := t.AsTime()
+
+	return &v
+}
@@ -0,0 +1,277 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/omspb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTestGRPCConn serves newGRPCServer in memory. The requests of the tests fail
+// before they reach the database, so there is none.
+func newTestGRPCConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := newGRPCServer(newTestLogger(), nil, newTestTokens())
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// requireStatus checks the code of err and the reason of its ErrorInfo detail, and
+// returns the violations of its BadRequest detail.
+func requireStatus(t *testing.T, err error, code codes.Code, reason string) []*errdetails.BadRequest_FieldViolation {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a gRPC status: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+
+	var (
+		info       *errdetails.ErrorInfo
+		violations []*errdetails.BadRequest_FieldViolation
+	)
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = detail
+		case *errdetails.BadRequest:
+			violations = detail.GetFieldViolations()
+		}
+	}
+
+	require.NotNil(t, info)
+	require.Equal(t, reason, info.GetReason())
+	require.Equal(t, grpcErrorDomain, info.GetDomain())
+
+	return violations
+}
+
+func TestGRPCServerErrors(t *testing.T) {
+	conn := newTestGRPCConn(t)
+	campaigns := omspb.NewCampaignServiceClient(conn)
+	lineItems := omspb.NewLineItemServiceClient(conn)
+	ctx := context.Background()
+
+	t.Run("validation failure names the fields", func(t *testing.T) {
+		_, err := campaigns.CreateCampaign(ctx, &omspb.CreateCampaignRequest{Campaign: &omspb.Campaign{
+			Name:      "backwards",
+			StartedAt: timestamppb.New(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			EndedAt:   timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		}})
+
+		violations := requireStatus(t, err, codes.InvalidArgument, models.ProblemValidationFailed)
+		require.Len(t, violations, 1)
+		require.Equal(t, "ended_at", violations[0].GetField())
+		require.Equal(t, "cannot be before the start 2024-02-01 00:00:00", violations[0].GetDescription())
+	})
+
+	t.Run("update without a version", func(t *testing.T) {
+		_, err := campaigns.UpdateCampaign(ctx, &omspb.UpdateCampaignRequest{Id: 1, Campaign: &omspb.Campaign{}})
+
+		violations := requireStatus(t, err, codes.FailedPrecondition, models.ProblemPreconditionRequired)
+		require.Empty(t, violations)
+	})
+
+	t.Run("delete of a line item without a version", func(t *testing.T) {
+		_, err := lineItems.DeleteLineItem(ctx, &omspb.DeleteLineItemRequest{Id: 1})
+
+		requireStatus(t, err, codes.FailedPrecondition, models.ProblemPreconditionRequired)
+	})
+
+	t.Run("unknown delete mode", func(t *testing.T) {
+		_, err := campaigns.DeleteCampaign(ctx, &omspb.DeleteCampaignRequest{Id: 1, Version: 1, Mode: "bogus"})
+
+		requireStatus(t, err, codes.InvalidArgument, models.ProblemInvalidRequest)
+	})
+
+	t.Run("stream with an invalid filter", func(t *testing.T) {
+		stream, err := campaigns.ListCampaigns(ctx, &omspb.ListCampaignsRequest{
+			Query: &omspb.ListQuery{Filter: "secret eq 'x'"},
+		})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		requireStatus(t, err, codes.InvalidArgument, models.ProblemInvalidQuery)
+	})
+}
+
+func TestGRPCError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{name: "missing record", err: sql.ErrNoRows, wantCode: codes.NotFound, wantReason: models.ProblemNotFound},
+		{
+			name:       "stale version",
+			err:        &versionMismatchError{current: 3},
+			wantCode:   codes.Aborted,
+			wantReason: models.ProblemVersionMismatch,
+		},
+		{
+			name:       "conflict",
+			err:        errLineItemNotLocked,
+			wantCode:   codes.FailedPrecondition,
+			wantReason: models.ProblemInvalidState,
+		},
+		{
+			name:       "wrapped sentinel",
+			err:        errors.Join(errors.New("listing"), errTokenExpired),
+			wantCode:   codes.InvalidArgument,
+			wantReason: models.ProblemTokenExpired,
+		},
+		{
+			name:       "unexpected error",
+			err:        errors.New("connection refused"),
+			wantCode:   codes.Internal,
+			wantReason: models.ProblemInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := grpcError(newTestLogger(), "/oms.CampaignService/GetCampaign", tt.err)
+
+			violations := requireStatus(t, err, tt.wantCode, tt.wantReason)
+			require.Empty(t, violations)
+		})
+	}
+
+	t.Run("validation failure", func(t *testing.T) {
+		err := grpcError(newTestLogger(), "", newValidationError([]models.FieldError{
+			{Field: "CampaignID", Message: "is required"},
+			{Field: "Owners[0].SplitPercent", Message: "must be positive"},
+		}))
+
+		violations := requireStatus(t, err, codes.InvalidArgument, models.ProblemValidationFailed)
+		require.Len(t, violations, 2)
+		require.Equal(t, "campaign_id", violations[0].GetField())
+		require.Equal(t, "is required", violations[0].GetDescription())
+		require.Equal(t, "owners[0].split_percent", violations[1].GetField())
+	})
+
+	t.Run("status passes through", func(t *testing.T) {
+		in := status.Error(codes.Unauthenticated, "who are you")
+		require.Equal(t, in, grpcError(newTestLogger(), "", in))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		err := grpcError(newTestLogger(), "", context.Canceled)
+		require.Equal(t, codes.Canceled, status.Code(err))
+	})
+}
+
+func TestVersionMatch(t *testing.T) {
+	_, err := versionMatch(0)
+	require.ErrorIs(t, err, errVersionRequired)
+	require.Equal(t, http.StatusPreconditionRequired, problemFor(err).Status)
+
+	match, err := versionMatch(4)
+	require.NoError(t, err)
+	require.Equal(t, []int{4}, match.versions)
+}
+
+func TestStreamList(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     [][]int
+		wantSent  []int
+		wantPages int
+	}{
+		{name: "short first page", pages: [][]int{{1}}, wantSent: []int{1}, wantPages: 1},
+		{name: "short last page", pages: [][]int{{1, 2}, {3, 4}, {5}}, wantSent: []int{1, 2, 3, 4, 5}, wantPages: 3},
+		{name: "full last page", pages: [][]int{{1, 2}, {}}, wantSent: []int{1, 2}, wantPages: 2},
+		{name: "no items", pages: [][]int{{}}, wantSent: nil, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newStreamListRequest(newTestTokens(), campaignListSchema, &omspb.ListQuery{OrderBy: "name"})
+			require.NoError(t, err)
+
+			req.limit = 2
+
+			var (
+				sent   []int
+				afters []*int
+			)
+
+			page := func(r *listRequest) (*models.List[models.Campaign], error) {
+				require.Less(t, len(afters), len(tt.pages), "read past the last page")
+
+				var after *int
+				if r.after != nil {
+					after = &r.after.ID
+					require.Equal(t, []interface{}{"campaign " + strconv.Itoa(r.after.ID)}, r.after.Values)
+				}
+
+				afters = append(afters, after)
+
+				list := &models.List[models.Campaign]{}
+				for _, id := range tt.pages[len(afters)-1] {
+					list.Items = append(list.Items, &models.Campaign{ID: id, Name: "campaign " + strconv.Itoa(id)})
+				}
+
+				return list, nil
+			}
+
+			err = streamList(req, page,
+				func(c *models.Campaign) int { return c.ID },
+				func(c *models.Campaign) error {
+					sent = append(sent, c.ID)
+					return nil
+				})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSent, sent)
+			require.Len(t, afters, tt.wantPages)
+			require.Nil(t, afters[0])
+		})
+	}
+
+	t.Run("send error stops the stream", func(t *testing.T) {
+		req, err := newStreamListRequest(newTestTokens(), campaignListSchema, &omspb.ListQuery{})
+		require.NoError(t, err)
+
+		err = streamList(req,
+			func(*listRequest) (*models.List[models.Campaign], error) {
+				return &models.List[models.Campaign]{Items: []*models.Campaign{{ID: 1}, {ID: 2}}}, nil
+			},
+			func(c *models.Campaign) int { return c.ID },
+			func(*models.Campaign) error { return io.ErrClosedPipe })
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+}
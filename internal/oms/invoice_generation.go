@@ -0,0 +1,59 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+// generateCampaignInvoice issues an invoice for the current totals of a campaign
+// and records and locks the line items it bills. The live line items are locked
+// first and the invoice is built, linked and locked from that set, so a write
+// to one of them waits for the invoice and sees it locked.
+func generateCampaignInvoice(ctx context.Context, dbQueries *db.Queries, campaignID int32) (int32, error) {
+	var invoiceID int32
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		lineItemIDs, err := q.LockCampaignLinesForInvoice(ctx, campaignID)
+		if err != nil {
+			return errors.Wrap(err, "cannot lock the line items of the campaign")
+		}
+
+		invoice, err := q.BuildCampaignInvoice(ctx, db.BuildCampaignInvoiceParams{
+			CampaignID:  campaignID,
+			LineItemIds: lineItemIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		invoice.IssuedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+
+		invoiceID, err = q.CreateInvoice(ctx, invoice)
+		if err != nil {
+			return errors.Wrap(err, "cannot create invoice")
+		}
+
+		err = q.LinkInvoiceLineItems(ctx, db.LinkInvoiceLineItemsParams{InvoiceID: invoiceID, LineItemIds: lineItemIDs})
+		if err != nil {
+			return errors.Wrap(err, "cannot link the line items to the invoice")
+		}
+
+		err = q.LockInvoiceLineItems(ctx, db.LockInvoiceLineItemsParams{InvoiceID: invoiceID, LockedAt: invoice.IssuedAt})
+		if err != nil {
+			return errors.Wrap(err, "cannot lock the line items of the invoice")
+		}
+
+		if err := q.RecordInvoiceLineItemsLocked(ctx, invoiceID); err != nil {
+			return errors.Wrap(err, "cannot record the line item locks")
+		}
+
+		return emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceGenerated, invoiceID)
+	})
+
+	return invoiceID, err
+}
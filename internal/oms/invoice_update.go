@@ -0,0 +1,98 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+// invoicePatchFields are the invoice fields a merge patch may change, the totals
+// come from the campaign and the dates from issuing and collecting.
+var invoicePatchFields = patchableFields{
+	"TotalAdjustments": false,
+	"StartedAt":        true,
+	"EndedAt":          true,
+}
+
+var errInvoiceIssued = newAPIError(http.StatusConflict, models.ProblemInvalidState,
+	"issued invoices are billed documents and cannot be patched")
+
+// patchInvoice applies a merge patch to a draft invoice and returns the patched invoice.
+func patchInvoice(ctx context.Context, dbQueries *db.Queries, id int32, patch map[string]interface{},
+	match *ifMatch) (*models.Invoice, error) {
+	var invoice db.OmsInvoice
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetInvoiceForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(existing.Version); err != nil {
+			return err
+		}
+
+		if existing.IssuedAt.Valid {
+			return errInvoiceIssued
+		}
+
+		current, err := models.NewInvoiceFromDB(existing)
+		if err != nil {
+			return err
+		}
+
+		var patched models.Invoice
+		if err := applyMergePatch(current, patch, invoicePatchFields, &patched); err != nil {
+			return err
+		}
+
+		if err := newValidationError(patched.ValidateFlight()); err != nil {
+			return err
+		}
+
+		if err := q.UpdateInvoice(ctx, patched.ToUpdateInvoiceParams()); err != nil {
+			return err
+		}
+
+		if err := emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceUpdated, id); err != nil {
+			return err
+		}
+
+		invoice, err = q.GetInvoice(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewInvoiceFromDB(invoice)
+}
+
+// deleteInvoice moves an invoice that is still at a version the request named to the trash.
+func deleteInvoice(ctx context.Context, dbQueries *db.Queries, id int32, match *ifMatch) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		invoice, err := q.GetInvoiceForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(invoice.Version); err != nil {
+			return err
+		}
+
+		err = q.SoftDeleteInvoice(ctx, db.SoftDeleteInvoiceParams{
+			ID:        id,
+			DeletedAt: sql.NullTime{Valid: true, Time: time.Now().UTC()},
+		})
+		if err != nil {
+			return err
+		}
+
+		return emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceDeleted, id)
+	})
+}
@@ -0,0 +1,117 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+var errInvoiceNotIssued = newAPIError(http.StatusConflict, models.ProblemInvalidState,
+	"only issued invoices can be collected")
+
+// createInvoice stores an invoice as it is given and returns its id.
+func createInvoice(ctx context.Context, dbQueries *db.Queries, invoice *models.Invoice) (int32, error) {
+	var id int32
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		id, err = q.CreateInvoice(ctx, invoice.ToCreateInvoiceParams())
+		if err != nil {
+			return err
+		}
+
+		return emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceCreated, id)
+	})
+
+	return id, err
+}
+
+// getInvoice returns an invoice, a deleted invoice only when includeDeleted is set.
+func getInvoice(ctx context.Context, dbQueries *db.Queries, id int32, includeDeleted bool) (*models.Invoice, error) {
+	get := dbQueries.GetInvoice
+	if includeDeleted {
+		get = dbQueries.GetInvoiceWithDeleted
+	}
+
+	invoice, err := get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewInvoiceFromDB(invoice)
+}
+
+// listInvoices returns a page of the invoices of a campaign the caller already
+// found, or of every campaign when campaignID is 0.
+func listInvoices(ctx context.Context, dbQueries *db.Queries, req *listRequest,
+	campaignID int32) (*models.List[models.Invoice], error) {
+	toModel := func(i *db.OmsInvoice) (*models.Invoice, error) { return models.NewInvoiceFromDB(*i) }
+	base, baseArgs := invoiceListBase(req, campaignID)
+
+	return listPage(ctx, req, dbQueries.QueryInvoices, dbQueries.CountInvoices, toModel,
+		func(i *models.Invoice) int { return i.ID }, base, baseArgs...)
+}
+
+// countInvoices counts the invoices of the list across all pages.
+func countInvoices(ctx context.Context, dbQueries *db.Queries, req *listRequest, campaignID int32) (*int64, error) {
+	base, baseArgs := invoiceListBase(req, campaignID)
+
+	return req.countAll(ctx, dbQueries.CountInvoices, base, baseArgs...)
+}
+
+func invoiceListBase(req *listRequest, campaignID int32) (string, []interface{}) {
+	if campaignID == 0 {
+		return "($1::boolean OR deleted_at IS NULL)", []interface{}{req.includeDeleted}
+	}
+
+	return "campaign_id = $1 AND ($2::boolean OR deleted_at IS NULL)", []interface{}{campaignID, req.includeDeleted}
+}
+
+// adjustInvoice sets the total adjustments of an invoice.
+func adjustInvoice(ctx context.Context, dbQueries *db.Queries, id int32, adjustmentAmount float64) error {
+	adjustedAmountStr := strconv.FormatFloat(adjustmentAmount, 'f', 15, 64)
+	params := db.AdjustInvoiceParams{ID: id, TotalAdjustments: sql.NullString{Valid: true, String: adjustedAmountStr}}
+
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		if err := q.AdjustInvoice(ctx, params); err != nil {
+			return err
+		}
+
+		return emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceUpdated, id)
+	})
+}
+
+// collectInvoice records that the payment of an issued invoice was received,
+// collecting an invoice twice keeps the first collection date.
+func collectInvoice(ctx context.Context, dbQueries *db.Queries, id int32) error {
+	invoice, err := dbQueries.GetInvoice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !invoice.IssuedAt.Valid {
+		return errInvoiceNotIssued
+	}
+
+	if invoice.CollectedAt.Valid {
+		return nil
+	}
+
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		err := q.CollectInvoice(ctx, db.CollectInvoiceParams{
+			ID:          id,
+			CollectedAt: sql.NullTime{Valid: true, Time: time.Now().UTC()},
+		})
+		if err != nil {
+			return err
+		}
+
+		return emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceUpdated, id)
+	})
+}
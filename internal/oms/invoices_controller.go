@@ -5,161 +5,211 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
-	"strconv"
 
 	"github.com/chrisrob11/oms/internal/oms/db"
 	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
 	"github.com/gin-gonic/gin"
 )
 
 type invoicesController struct {
 	dbQueries *db.Queries
 	logger    *slog.Logger
+	tokens    *tokenCodec
 }
 
-func newInvoicesController(logger *slog.Logger, engine *gin.Engine, dbQueries *db.Queries) *invoicesController {
-	controller := &invoicesController{dbQueries: dbQueries, logger: logger}
-	engine.POST("/invoices", controller.create)
-	engine.GET("/invoices", controller.list)
-	engine.GET("/invoices/:id", controller.get)
-	engine.DELETE("/invoices/:id", controller.delete)
-	engine.POST("/invoices/:id/adjust", controller.adjust)
+func newInvoicesController(logger *slog.Logger, dbQueries *db.Queries, tokens *tokenCodec) *invoicesController {
+	return &invoicesController{dbQueries: dbQueries, logger: logger, tokens: tokens}
+}
 
-	return controller
+// register adds the invoice routes.
+func (s *invoicesController) register(router gin.IRoutes) {
+	router.POST("/invoices", s.create)
+	router.GET("/invoices", s.list)
+	router.GET("/invoices/:id", s.get)
+	router.PATCH("/invoices/:id", s.patch)
+	router.DELETE("/invoices/:id", s.delete)
+	router.POST("/invoices/:id/adjust", s.adjust)
+	router.POST("/invoices/:id/restore", s.restore)
+	router.POST("/invoices/:id/collect", s.collect)
 }
 
 func (s *invoicesController) create(c *gin.Context) {
 	var req models.Invoice
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
 		return
 	}
 
-	invoiceID, err := s.dbQueries.CreateInvoice(c.Request.Context(), req.ToCreateInvoiceParams())
+	invoiceID, err := createInvoice(c.Request.Context(), s.dbQueries, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, invoiceID)
+	respond(c, http.StatusOK, invoiceID)
 }
 
 func (s *invoicesController) get(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	includeDeleted, hasError := extractIncludeDeleted(c)
+	if hasError {
 		return
 	}
 
-	invoice, err := s.dbQueries.GetInvoice(c.Request.Context(), id)
+	invoiceModel, err := getInvoice(c.Request.Context(), s.dbQueries, id, includeDeleted)
 	if errors.Is(err, sql.ErrNoRows) {
-		c.JSON(http.StatusNotFound, gin.H{"error": err})
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "invoice not found")
 		return
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	invoiceModel, err := models.NewInvoiceFromDB(invoice)
+	setETag(c, invoiceModel.Version)
+	respond(c, http.StatusOK, invoiceModel)
+}
+
+func (s *invoicesController) list(c *gin.Context) {
+	req, hasError := extractListRequest(c, s.tokens, invoiceListSchema)
+	if hasError {
+		return
+	}
+
+	invoicesResp, err := listInvoices(c.Request.Context(), s.dbQueries, req, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, invoiceModel)
+	respond(c, http.StatusOK, invoicesResp)
 }
 
-func (s *invoicesController) list(c *gin.Context) {
-	params := db.ListInvoicesParams{
-		Limit: 100,
+func (s *invoicesController) adjust(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
 	}
 
-	limitInt, hasError := extractLimit(c)
-	if hasError {
+	var adjustmentAmount float64
+	if err := bindJSON(c, &adjustmentAmount); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
 		return
 	}
 
-	if limitInt != nil {
-		params.Limit = *limitInt
+	if err := adjustInvoice(c.Request.Context(), s.dbQueries, id, adjustmentAmount); err != nil {
+		respondError(c, err)
+		return
 	}
 
-	pageInfo, hasError := extractTokenFromQuery(c)
+	respond(c, http.StatusOK, int(id))
+}
+
+// patch changes only the fields present in a JSON merge patch, null clears a field.
+func (s *invoicesController) patch(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
 	if hasError {
 		return
 	}
 
-	if pageInfo != nil {
-		s.logger.Info("Token info", slog.Attr{Key: "starting_id", Value: slog.IntValue(pageInfo.StartID)})
-		params.ID = int32(pageInfo.StartID)
-		params.Limit = int32(pageInfo.Size)
+	patch, hasError := readMergePatch(c, v1.InvoiceNames)
+	if hasError {
+		return
+	}
+
+	invoice, err := patchInvoice(c.Request.Context(), s.dbQueries, id, patch, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "invoice not found")
+		return
 	}
 
-	invoices, err := s.dbQueries.ListInvoices(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	numItems := len(invoices)
-	invoicesResp := &models.List[models.Invoice]{}
-	invoicesResp.Items = make([]*models.Invoice, numItems)
+	setETag(c, invoice.Version)
+	respond(c, http.StatusOK, invoice)
+}
 
-	for i := 0; i < numItems; i++ {
-		v, err := models.NewInvoiceFromDB(invoices[i])
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+func (s *invoicesController) delete(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
 
-		invoicesResp.Items[i] = v
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
 	}
 
-	if numItems >= int(params.Limit) {
-		token := EncodeToken(PaginationToken{StartID: int(invoices[numItems-1].ID), Size: int(params.Limit)})
-		invoicesResp.NextPageToken = token
+	err = deleteInvoice(c.Request.Context(), s.dbQueries, id, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "invoice not found")
+		return
 	}
 
-	c.JSON(http.StatusOK, invoicesResp)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
 }
 
-func (s *invoicesController) adjust(c *gin.Context) {
+func (s *invoicesController) restore(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
 		return
 	}
 
-	var adjustmentAmount float64
-	if err := c.BindJSON(&adjustmentAmount); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	err = restoreInvoice(c.Request.Context(), s.dbQueries, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "invoice not found")
 		return
 	}
 
-	adjustedAmountStr := strconv.FormatFloat(adjustmentAmount, 'f', 15, 64)
-	params := db.AdjustInvoiceParams{ID: id, TotalAdjustments: sql.NullString{Valid: true, String: adjustedAmountStr}}
-
-	if err := s.dbQueries.AdjustInvoice(c.Request.Context(), params); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, int(id))
+	respond(c, http.StatusOK, int(id))
 }
 
-func (s *invoicesController) delete(c *gin.Context) {
+func (s *invoicesController) collect(c *gin.Context) {
 	id, err := toInt32(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	err = collectInvoice(c.Request.Context(), s.dbQueries, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "invoice not found")
 		return
 	}
 
-	err = s.dbQueries.DeleteInvoice(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	respond(c, http.StatusOK, int(id))
 }
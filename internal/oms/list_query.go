@@ -0,0 +1,335 @@
+package oms
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/query"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	filterQueryParamName  = "$filter"
+	orderByQueryParamName = "$orderby"
+	countQueryParamName   = "$count"
+	defaultListLimit      = 100
+)
+
+var errTokenQueryMismatch = errors.New("$filter and $orderby cannot change while paging, " +
+	"leave them out or repeat the values of the first page")
+
+// listSchema is the schema of a list endpoint for every version of the API, /v1
+// names the fields like its response bodies do.
+type listSchema map[apiVersion]*query.Schema
+
+// newListSchema panics when a field is missing from the /v1 names of the resource,
+// the schemas are built at start up.
+func newListSchema(names *v1.WireNames, fields ...query.Field) listSchema {
+	schema := query.NewSchema(fields...)
+
+	return listSchema{apiUnversioned: schema, apiV1: schema.Renamed(func(name string) string {
+		key, ok := names.Key(name)
+		if !ok {
+			panic("the /v1 resource has no field " + name + " to filter and sort on")
+		}
+
+		return key
+	})}
+}
+
+// The fields each list endpoint can filter and sort on. Numeric columns the models
+// read as zero when null are compared the same way.
+var (
+	campaignListSchema = newListSchema(v1.CampaignNames,
+		query.Field{Name: "ID", Column: "id", Type: query.Integer},
+		query.Field{Name: "Name", Column: "name", Type: query.String},
+		query.Field{Name: "StartedAt", Column: "started_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "EndedAt", Column: "ended_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "CreatedAt", Column: "created_at", Type: query.Time},
+		query.Field{Name: "UpdatedAt", Column: "updated_at", Type: query.Time},
+	)
+
+	campaignLineItemListSchema = newListSchema(v1.CampaignLineItemNames,
+		query.Field{Name: "ID", Column: "id", Type: query.Integer},
+		query.Field{Name: "CampaignID", Column: "campaign_id", Type: query.Integer},
+		query.Field{Name: "Name", Column: "name", Type: query.String},
+		query.Field{Name: "Booked", Column: "booked", Type: query.Number},
+		query.Field{Name: "Actual", Column: "COALESCE(actual, 0)", Type: query.Number},
+		query.Field{Name: "Adjustments", Column: "COALESCE(adjustments, 0)", Type: query.Number},
+		query.Field{Name: "PricingModel", Column: "pricing_model", Type: query.String},
+		query.Field{Name: "UnitRate", Column: "unit_rate", Type: query.Number},
+		query.Field{Name: "BookedUnits", Column: "booked_units", Type: query.Integer},
+		query.Field{Name: "DeliveredUnits", Column: "delivered_units", Type: query.Integer},
+		query.Field{Name: "StartedAt", Column: "started_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "EndedAt", Column: "ended_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "LockedAt", Column: "locked_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "CreatedAt", Column: "created_at", Type: query.Time},
+		query.Field{Name: "UpdatedAt", Column: "updated_at", Type: query.Time},
+	)
+
+	invoiceListSchema = newListSchema(v1.InvoiceNames,
+		query.Field{Name: "ID", Column: "id", Type: query.Integer},
+		query.Field{Name: "CampaignID", Column: "campaign_id", Type: query.Integer},
+		query.Field{Name: "TotalBookedAmount", Column: "COALESCE(total_booked_amount, 0)", Type: query.Number},
+		query.Field{Name: "TotalActualAmount", Column: "COALESCE(total_actual_amount, 0)", Type: query.Number},
+		query.Field{Name: "TotalAdjustments", Column: "COALESCE(total_adjustments, 0)", Type: query.Number},
+		query.Field{Name: "StartedAt", Column: "started_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "EndedAt", Column: "ended_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "IssuedAt", Column: "issued_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "CollectedAt", Column: "collected_at", Type: query.Time, Nullable: true},
+		query.Field{Name: "CreatedAt", Column: "created_at", Type: query.Time},
+		query.Field{Name: "UpdatedAt", Column: "updated_at", Type: query.Time},
+	)
+)
+
+// listRequest is a page request of a list endpoint. The first page takes $filter
+// and $orderby from the query, later pages take them from the token so every page
+// walks the same ordering.
+type listRequest struct {
+	list           *query.List
+	after          *query.Cursor
+	limit          int32
+	includeDeleted bool
+	// count asks for the number of matching rows across all pages.
+	count bool
+	// fingerprint ties the tokens of the page to the list they were issued for.
+	fingerprint string
+	tokens      *tokenCodec
+}
+
+// listOptions are the options of a list request, whichever API it was sent to.
+type listOptions struct {
+	// path names the list in the fingerprint that ties its tokens to it.
+	path           string
+	limit          *int32
+	filter         string
+	hasFilter      bool
+	orderBy        string
+	hasOrderBy     bool
+	includeDeleted bool
+	count          bool
+	token          *PaginationToken
+}
+
+func extractListRequest(c *gin.Context, tokens *tokenCodec, schema listSchema) (*listRequest, bool) {
+	opts := &listOptions{path: c.Request.URL.Path}
+
+	var hasError bool
+
+	opts.limit, hasError = extractLimit(c)
+	if hasError {
+		return nil, true
+	}
+
+	opts.filter, opts.hasFilter = c.GetQuery(filterQueryParamName)
+	opts.orderBy, opts.hasOrderBy = c.GetQuery(orderByQueryParamName)
+
+	opts.includeDeleted, hasError = extractIncludeDeleted(c)
+	if hasError {
+		return nil, true
+	}
+
+	opts.token, hasError = extractTokenFromQuery(c, tokens)
+	if hasError {
+		return nil, true
+	}
+
+	if count, ok := c.GetQuery(countQueryParamName); ok {
+		var err error
+
+		opts.count, err = strconv.ParseBool(count)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "$count must be true or false")
+			return nil, true
+		}
+	}
+
+	req, err := newListRequest(tokens, schema[requestAPIVersion(c)], opts)
+	if err != nil {
+		respondError(c, err)
+		return nil, true
+	}
+
+	return req, false
+}
+
+// newListRequest builds a page request from the options. A token must come from
+// the same list, its filter and order win over the options, which may only repeat them.
+func newListRequest(tokens *tokenCodec, schema *query.Schema, opts *listOptions) (*listRequest, error) {
+	req := &listRequest{limit: defaultListLimit, includeDeleted: opts.includeDeleted, count: opts.count, tokens: tokens}
+
+	if opts.limit != nil {
+		req.limit = *opts.limit
+	}
+
+	filter, orderBy := opts.filter, opts.orderBy
+
+	if pageInfo := opts.token; pageInfo != nil {
+		if (opts.hasFilter && filter != pageInfo.Filter) || (opts.hasOrderBy && orderBy != pageInfo.OrderBy) {
+			return nil, errTokenQueryMismatch
+		}
+
+		filter, orderBy = pageInfo.Filter, pageInfo.OrderBy
+
+		if pageInfo.Query != queryFingerprint(opts.path, req.includeDeleted, filter, orderBy) {
+			return nil, errTokenOtherQuery
+		}
+
+		req.after = &query.Cursor{ID: pageInfo.StartID, Values: pageInfo.After, Backward: pageInfo.Backward}
+		req.limit = int32(pageInfo.Size)
+	}
+
+	list, err := query.Parse(schema, filter, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	req.list = list
+	req.fingerprint = queryFingerprint(opts.path, req.includeDeleted, filter, orderBy)
+
+	return req, nil
+}
+
+// listQuery builds the page query on top of the base condition of the endpoint,
+// whose placeholders are numbered from $1.
+func (r *listRequest) listQuery(base string, baseArgs ...interface{}) (db.ListQuery, error) {
+	statement, err := r.list.Build(base, baseArgs, "id", r.after)
+	if err != nil {
+		return db.ListQuery{}, err
+	}
+
+	return db.ListQuery{
+		Where:   statement.Where,
+		OrderBy: statement.OrderBy,
+		Args:    statement.Args,
+		Limit:   r.limit,
+	}, nil
+}
+
+// backward reports whether the page was asked for with a previous page token, its
+// rows come back from the database last row first.
+func (r *listRequest) backward() bool {
+	return r.after != nil && r.after.Backward
+}
+
+// totalCount counts the rows matching the base condition and the filter when the
+// request asked for it with $count=true, and returns nil otherwise.
+func (r *listRequest) totalCount(ctx context.Context, count func(context.Context, db.ListQuery) (int64, error),
+	base string, baseArgs ...interface{}) (*int64, error) {
+	if !r.count {
+		return nil, nil
+	}
+
+	return r.countAll(ctx, count, base, baseArgs...)
+}
+
+// countAll counts the rows matching the base condition and the filter across all pages.
+func (r *listRequest) countAll(ctx context.Context, count func(context.Context, db.ListQuery) (int64, error),
+	base string, baseArgs ...interface{}) (*int64, error) {
+	statement, err := r.list.Build(base, baseArgs, "id", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := count(ctx, db.ListQuery{Where: statement.Where, Args: statement.Args})
+	if err != nil {
+		return nil, err
+	}
+
+	return &total, nil
+}
+
+// setPageTokens sets the tokens of the pages around a page in display order. A
+// page reached going forward has a previous page unless it is the first, and has a
+// next page when it is full, going backward it is the other way around.
+func setPageTokens[T any](r *listRequest, page *models.List[T], id func(*T) int) {
+	numItems := len(page.Items)
+	if numItems == 0 {
+		return
+	}
+
+	full := numItems >= int(r.limit)
+
+	if r.backward() || full {
+		last := page.Items[numItems-1]
+		page.NextPageToken = r.pageToken(r.list.CursorAt(id(last), last))
+	}
+
+	if (r.backward() && full) || (!r.backward() && r.after != nil) {
+		first := page.Items[0]
+		cursor := r.list.CursorAt(id(first), first)
+		cursor.Backward = true
+		page.PreviousPageToken = r.pageToken(cursor)
+	}
+}
+
+// newFirstPage builds the first page of a list from its first rows and one more
+// when there are more, which only tells that there is a next page.
+func newFirstPage[T any](r *listRequest, rows []*T, id func(*T) int) *models.List[T] {
+	if len(rows) <= int(r.limit) {
+		return &models.List[T]{Items: rows}
+	}
+
+	page := &models.List[T]{Items: rows[:r.limit]}
+	last := page.Items[len(page.Items)-1]
+	page.NextPageToken = r.pageToken(r.list.CursorAt(id(last), last))
+
+	return page
+}
+
+func (r *listRequest) pageToken(cursor query.Cursor) string {
+	return r.tokens.encode(PaginationToken{
+		StartID:  cursor.ID,
+		Size:     int(r.limit),
+		Filter:   r.list.Filter,
+		OrderBy:  r.list.OrderBy,
+		After:    cursor.Values,
+		Backward: cursor.Backward,
+		Query:    r.fingerprint,
+	})
+}
+
+// listPage runs the page query of the request on top of the base condition and
+// returns the page with its tokens, and its total when the request asked for it.
+func listPage[R any, T any](ctx context.Context, req *listRequest,
+	queryRows func(context.Context, db.ListQuery) ([]R, error),
+	count func(context.Context, db.ListQuery) (int64, error),
+	toModel func(*R) (*T, error), id func(*T) int, base string, baseArgs ...interface{}) (*models.List[T], error) {
+	listQuery, err := req.listQuery(base, baseArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queryRows(ctx, listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.backward() {
+		slices.Reverse(rows)
+	}
+
+	page := &models.List[T]{Items: make([]*T, len(rows))}
+
+	for i := range rows {
+		page.Items[i], err = toModel(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	setPageTokens(req, page, id)
+
+	page.TotalCount, err = req.totalCount(ctx, count, base, baseArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,50 @@
+package oms
+
+import (
+	"testing"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFirstPage(t *testing.T) {
+	limit := int32(2)
+
+	req, err := newListRequest(newTestTokens(), campaignLineItemListSchema[apiUnversioned],
+		&listOptions{path: "graphql:lineItems:1", limit: &limit})
+	require.NoError(t, err)
+
+	id := func(l *models.CampaignLineItem) int { return l.ID }
+
+	tests := []struct {
+		name     string
+		ids      []int
+		wantIDs  []int
+		wantNext bool
+	}{
+		{name: "no rows", wantIDs: []int{}},
+		{name: "fewer rows than the limit", ids: []int{4}, wantIDs: []int{4}},
+		{name: "as many rows as the limit", ids: []int{4, 7}, wantIDs: []int{4, 7}},
+		{name: "one more row than the limit", ids: []int{4, 7, 9}, wantIDs: []int{4, 7}, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([]*models.CampaignLineItem, len(tt.ids))
+			for i, rowID := range tt.ids {
+				rows[i] = &models.CampaignLineItem{ID: rowID}
+			}
+
+			page := newFirstPage(req, rows, id)
+
+			gotIDs := make([]int, len(page.Items))
+			for i, item := range page.Items {
+				gotIDs[i] = item.ID
+			}
+
+			require.Equal(t, tt.wantIDs, gotIDs)
+			require.Equal(t, tt.wantNext, page.NextPageToken != "")
+			require.Empty(t, page.PreviousPageToken)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package oms
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var errPatchNotObject = errors.New("a merge patch must be a JSON object")
+
+// patchableFields maps the fields a merge patch may change to whether null may clear them.
+type patchableFields map[string]bool
+
+// readMergePatch reads an RFC 7396 merge patch from the body, plain JSON is
+// accepted as well. The patch names the fields like the models do, whatever the
+// version of the request, /v1 keys are renamed with the names of the resource. It
+// writes the error response and reports when it fails.
+func readMergePatch(c *gin.Context, names *v1.WireNames) (map[string]interface{}, bool) {
+	contentType := c.ContentType()
+	if contentType != mergePatchContentType && contentType != gin.MIMEJSON {
+		respondProblem(c, http.StatusUnsupportedMediaType, models.ProblemUnsupportedMediaType,
+			"content type must be "+mergePatchContentType)
+		return nil, true
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return nil, true
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		respondError(c, errPatchNotObject)
+		return nil, true
+	}
+
+	if requestAPIVersion(c) == apiV1 {
+		patch = names.PatchFromWire(patch)
+	}
+
+	return patch, false
+}
+
+// applyMergePatch merges the patch into the JSON form of current and decodes the
+// result into out, which should be a zero value so removed fields stay cleared.
+func applyMergePatch(current interface{}, patch map[string]interface{}, fields patchableFields,
+	out interface{}) error {
+	if err := validateMergePatch(patch, fields); err != nil {
+		return err
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode the current value")
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(currentJSON, &document); err != nil {
+		return errors.Wrap(err, "cannot decode the current value")
+	}
+
+	mergedJSON, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return errors.Wrap(err, "cannot encode the patched value")
+	}
+
+	if err := json.Unmarshal(mergedJSON, out); err != nil {
+		return newValidationError([]models.FieldError{{Field: "Patch", Message: err.Error()}})
+	}
+
+	return nil
+}
+
+func validateMergePatch(patch map[string]interface{}, fields patchableFields) error {
+	var fieldErrors []models.FieldError
+
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		nullable, ok := fields[key]
+
+		switch {
+		case !ok:
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   key,
+				Message: "cannot be patched, patchable fields are " + fields.names(),
+			})
+		case patch[key] == nil && !nullable:
+			fieldErrors = append(fieldErrors, models.FieldError{Field: key, Message: "cannot be cleared with null"})
+		}
+	}
+
+	return newValidationError(fieldErrors)
+}
+
+// mergePatch implements the MergePatch function of RFC 7396 section 2.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+func (f patchableFields) names() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
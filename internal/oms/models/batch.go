@@ -0,0 +1,33 @@
+package models
+
+const (
+	BatchOperationCreate = "create"
+	BatchOperationUpdate = "update"
+	BatchOperationDelete = "delete"
+)
+
+// CampaignLineItemOperation is a single change of a batch. ID selects the line
+// item to update or delete and Version is the version of it the change is based
+// on, LineItem holds the fields to create or update.
+type CampaignLineItemOperation struct {
+	Op       string
+	ID       int
+	Version  int
+	LineItem *CampaignLineItem
+}
+
+// CampaignLineItemOperationResult is the outcome of an operation of a batch,
+// Status is the HTTP status the operation would have on its own.
+type CampaignLineItemOperationResult struct {
+	Index  int
+	Op     string
+	ID     int
+	Status int
+	Error  string       `json:",omitempty"`
+	Fields []FieldError `json:",omitempty"`
+}
+
+// CampaignLineItemBatchResult lists the results of the operations of a batch in order.
+type CampaignLineItemBatchResult struct {
+	Results []*CampaignLineItemOperationResult
+}
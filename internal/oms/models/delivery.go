@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/pkg/errors"
+)
+
+// LineItemDelivery is what a line item delivered on a single day.
+type LineItemDelivery struct {
+	LineItemID int
+	// Date is the UTC day of the delivery, the time of day is ignored.
+	Date        time.Time
+	Impressions int64
+	Clicks      int64
+	Conversions int64
+	Spend       float64
+}
+
+func NewLineItemDeliveryFromDB(d *db.OmsLineItemDelivery) (*LineItemDelivery, error) {
+	spend, err := strconv.ParseFloat(d.Spend, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string spend not convertable to float: %s", d.Spend)
+	}
+
+	return &LineItemDelivery{
+		LineItemID:  int(d.LineItemID),
+		Date:        d.DeliveryDate,
+		Impressions: d.Impressions,
+		Clicks:      d.Clicks,
+		Conversions: d.Conversions,
+		Spend:       spend,
+	}, nil
+}
+
+func (d *LineItemDelivery) ToUpsertLineItemDelivery() db.UpsertLineItemDeliveryParams {
+	return db.UpsertLineItemDeliveryParams{
+		LineItemID:   int32(d.LineItemID),
+		DeliveryDate: d.Date.UTC().Truncate(24 * time.Hour),
+		Impressions:  d.Impressions,
+		Clicks:       d.Clicks,
+		Conversions:  d.Conversions,
+		Spend:        strconv.FormatFloat(d.Spend, 'f', -1, 64),
+	}
+}
+
+// Validate checks that the record has a day and no negative numbers.
+func (d *LineItemDelivery) Validate() []FieldError {
+	var fieldErrors []FieldError
+
+	if d.Date.IsZero() {
+		fieldErrors = append(fieldErrors, FieldError{Field: "Date", Message: "is required"})
+	}
+
+	if d.Impressions < 0 || d.Clicks < 0 || d.Conversions < 0 || d.Spend < 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "Delivery", Message: "cannot be negative"})
+	}
+
+	return fieldErrors
+}
+
+// DeliveryTotals sums all the delivery of a line item.
+type DeliveryTotals struct {
+	Impressions int64
+	Clicks      int64
+	Conversions int64
+	Spend       float64
+}
+
+func NewDeliveryTotalsFromDB(t *db.GetLineItemDeliveryTotalsRow) (*DeliveryTotals, error) {
+	spend, err := strconv.ParseFloat(t.Spend, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string spend not convertable to float: %s", t.Spend)
+	}
+
+	return &DeliveryTotals{
+		Impressions: t.Impressions,
+		Clicks:      t.Clicks,
+		Conversions: t.Conversions,
+		Spend:       spend,
+	}, nil
+}
+
+// DeliveryIngestResult reports what a bulk delivery ingestion changed.
+type DeliveryIngestResult struct {
+	Records   int
+	LineItems int
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+)
+
+const (
+	// LineItemEventMoved records a line item moving to another campaign.
+	LineItemEventMoved = "moved"
+	// LineItemEventLocked records an issued invoice billing the line item.
+	LineItemEventLocked = "locked"
+	// LineItemEventUnlocked records a line item being unlocked so it can be changed again.
+	LineItemEventUnlocked = "unlocked"
+)
+
+// LineItemHistoryEntry is a change made to a line item that is kept for auditing.
+type LineItemHistoryEntry struct {
+	ID             int
+	LineItemID     int
+	Event          string
+	FromCampaignID *int
+	ToCampaignID   *int
+	Reason         string
+	CreatedAt      time.Time
+}
+
+func NewLineItemHistoryEntryFromDB(h *db.OmsLineItemHistory) *LineItemHistoryEntry {
+	return &LineItemHistoryEntry{
+		ID:             int(h.ID),
+		LineItemID:     int(h.LineItemID),
+		Event:          h.Event,
+		FromCampaignID: toInt(h.FromCampaignID),
+		ToCampaignID:   toInt(h.ToCampaignID),
+		Reason:         h.Reason.String,
+		CreatedAt:      h.CreatedAt.Time,
+	}
+}
+
+// MoveCampaignLineItemRequest moves a line item to the campaign with CampaignID.
+type MoveCampaignLineItemRequest struct {
+	CampaignID int
+	Reason     string
+}
+
+// UnlockCampaignLineItemRequest explains why a locked line item has to change.
+type UnlockCampaignLineItemRequest struct {
+	Reason string
+}
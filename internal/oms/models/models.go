@@ -1,4 +1,6 @@
-// Package models has the main models and how to convert them to the db models back and forth
+// Package models has the main models and how to convert them to the db models back and forth.
+// What encoding/json makes of them is the wire format of the unversioned routes, so their
+// field names and tags must not change, the /v1 API has its own types in package v1.
 package models
 
 import (
@@ -19,6 +21,17 @@ type Campaign struct {
 	Archiving bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
+	// Version changes with every update, it is served as the ETag of the campaign.
+	Version int
+}
+
+// ExpandedCampaign is a campaign with the related resources asked for with $expand,
+// a relation that was not expanded is left out of the response and stays nil.
+type ExpandedCampaign struct {
+	Campaign
+	LineItems []*CampaignLineItem
+	Invoices  []*Invoice
 }
 
 func NewCampaignFromDB(c *db.OmsCampaign) *Campaign {
@@ -30,6 +43,8 @@ func NewCampaignFromDB(c *db.OmsCampaign) *Campaign {
 		Archiving: c.Archiving.Bool,
 		CreatedAt: c.CreatedAt.Time,
 		UpdatedAt: c.UpdatedAt.Time,
+		DeletedAt: toTime(c.DeletedAt),
+		Version:   int(c.Version),
 	}
 }
 
@@ -52,6 +67,89 @@ func (c *Campaign) ToCreateCampaign() *db.CreateCampaignParams {
 	}
 }
 
+// CloneCampaignRequest describes how a campaign should be copied.
+type CloneCampaignRequest struct {
+	// Name of the new campaign, defaults to the source name when empty.
+	Name string
+	// OffsetDays shifts the flight dates of the campaign and its line items.
+	OffsetDays int
+	// ResetActuals zeroes the actual and adjustment amounts on the copied line items.
+	ResetActuals bool
+}
+
+// CampaignSummary holds the aggregated financials of a campaign.
+type CampaignSummary struct {
+	CampaignID       int
+	LineItemCount    int
+	TotalBooked      float64
+	TotalActual      float64
+	TotalAdjustments float64
+	// Variance is the actual amount minus the booked amount.
+	Variance float64
+	// PercentDelivered is the actual amount as a percentage of the booked amount.
+	PercentDelivered float64
+	// InvoicedToDate is the billed amount (actual plus adjustments) of the latest issued invoice, which
+	// bills the cumulative totals of the campaign.
+	InvoicedToDate float64
+	// Uninvoiced is the billable amount of the line items not yet covered by issued invoices.
+	Uninvoiced float64
+}
+
+func NewCampaignSummaryFromDB(campaignID int32, totals *db.GetCampaignLineTotalsRow,
+	invoicedToDate string) (*CampaignSummary, error) {
+	booked, err := strconv.ParseFloat(totals.TotalBooked, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string total booked not convertable to float: %s", totals.TotalBooked)
+	}
+
+	actual, err := strconv.ParseFloat(totals.TotalActual, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string total actual not convertable to float: %s", totals.TotalActual)
+	}
+
+	adjustments, err := strconv.ParseFloat(totals.TotalAdjustments, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string total adjustments not convertable to float: %s", totals.TotalAdjustments)
+	}
+
+	invoiced, err := strconv.ParseFloat(invoicedToDate, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string invoiced to date not convertable to float: %s", invoicedToDate)
+	}
+
+	var percentDelivered float64
+	if booked != 0 {
+		percentDelivered = actual / booked * 100
+	}
+
+	return &CampaignSummary{
+		CampaignID:       int(campaignID),
+		LineItemCount:    int(totals.LineItemCount),
+		TotalBooked:      booked,
+		TotalActual:      actual,
+		TotalAdjustments: adjustments,
+		Variance:         actual - booked,
+		PercentDelivered: percentDelivered,
+		InvoicedToDate:   invoiced,
+		Uninvoiced:       actual + adjustments - invoiced,
+	}, nil
+}
+
+// CampaignDependents counts the records that reference a campaign.
+type CampaignDependents struct {
+	LineItems      int
+	DraftInvoices  int
+	IssuedInvoices int
+}
+
+func NewCampaignDependentsFromDB(c *db.CountCampaignDependentsRow) *CampaignDependents {
+	return &CampaignDependents{
+		LineItems:      int(c.LineItemCount),
+		DraftInvoices:  int(c.DraftInvoiceCount),
+		IssuedInvoices: int(c.IssuedInvoiceCount),
+	}
+}
+
 // CampaignLineItem represents the data structure for a campaign order line.
 type CampaignLineItem struct {
 	ID          int
@@ -64,6 +162,15 @@ type CampaignLineItem struct {
 	EndedAt     *time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+	// PricingModel is one of cpm, cpc, cpa or flat, see ApplyPricing.
+	PricingModel   string
+	UnitRate       float64
+	BookedUnits    int64
+	DeliveredUnits int64
+	// LockedAt is set once an issued invoice bills the line item, it cannot change until unlocked.
+	LockedAt *time.Time
+	Version  int
 }
 
 func NewCampaignLineItemFromDB(c *db.OmsCampaignLineItem) (*CampaignLineItem, error) {
@@ -89,42 +196,62 @@ func NewCampaignLineItemFromDB(c *db.OmsCampaignLineItem) (*CampaignLineItem, er
 		}
 	}
 
+	unitRate, err := strconv.ParseFloat(c.UnitRate, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string unit rate not convertable to float: %s", c.UnitRate)
+	}
+
 	return &CampaignLineItem{
-		ID:          int(c.ID),
-		CampaignID:  int(c.CampaignID),
-		Name:        c.Name,
-		Booked:      booked,
-		Actual:      actual,
-		Adjustments: adjustments,
-		StartedAt:   toTime(c.StartedAt),
-		EndedAt:     toTime(c.EndedAt),
-		CreatedAt:   c.CreatedAt.Time,
-		UpdatedAt:   c.UpdatedAt.Time,
+		ID:             int(c.ID),
+		CampaignID:     int(c.CampaignID),
+		Name:           c.Name,
+		Booked:         booked,
+		Actual:         actual,
+		Adjustments:    adjustments,
+		StartedAt:      toTime(c.StartedAt),
+		EndedAt:        toTime(c.EndedAt),
+		CreatedAt:      c.CreatedAt.Time,
+		UpdatedAt:      c.UpdatedAt.Time,
+		DeletedAt:      toTime(c.DeletedAt),
+		PricingModel:   c.PricingModel,
+		UnitRate:       unitRate,
+		BookedUnits:    c.BookedUnits,
+		DeliveredUnits: c.DeliveredUnits,
+		LockedAt:       toTime(c.LockedAt),
+		Version:        int(c.Version),
 	}, nil
 }
 
 func (c *CampaignLineItem) ToCreateCampaignLineItemWithID() db.CreateCampaignLineWithIDParams {
 	return db.CreateCampaignLineWithIDParams{
-		ID:          int32(c.ID),
-		CampaignID:  int32(c.CampaignID),
-		Name:        c.Name,
-		Booked:      strconv.FormatFloat(c.Booked, 'f', 16, 64),
-		Actual:      sql.NullString{String: strconv.FormatFloat(c.Actual, 'f', 16, 64), Valid: true},
-		Adjustments: sql.NullString{String: strconv.FormatFloat(c.Adjustments, 'f', 16, 64), Valid: true},
-		StartedAt:   toSQLTime(c.StartedAt),
-		EndedAt:     toSQLTime(c.EndedAt),
+		ID:             int32(c.ID),
+		CampaignID:     int32(c.CampaignID),
+		Name:           c.Name,
+		Booked:         strconv.FormatFloat(c.Booked, 'f', 16, 64),
+		Actual:         sql.NullString{String: strconv.FormatFloat(c.Actual, 'f', 16, 64), Valid: true},
+		Adjustments:    sql.NullString{String: strconv.FormatFloat(c.Adjustments, 'f', 16, 64), Valid: true},
+		StartedAt:      toSQLTime(c.StartedAt),
+		EndedAt:        toSQLTime(c.EndedAt),
+		PricingModel:   c.PricingModel,
+		UnitRate:       formatUnitRate(c.UnitRate),
+		BookedUnits:    c.BookedUnits,
+		DeliveredUnits: c.DeliveredUnits,
 	}
 }
 
 func (c *CampaignLineItem) ToCreateCampaignLineItem() db.CreateCampaignLineParams {
 	return db.CreateCampaignLineParams{
-		CampaignID:  int32(c.CampaignID),
-		Name:        c.Name,
-		Booked:      strconv.FormatFloat(c.Booked, 'f', 16, 64),
-		Actual:      sql.NullString{String: strconv.FormatFloat(c.Actual, 'f', 16, 64), Valid: true},
-		Adjustments: sql.NullString{String: strconv.FormatFloat(c.Adjustments, 'f', 16, 64), Valid: true},
-		StartedAt:   toSQLTime(c.StartedAt),
-		EndedAt:     toSQLTime(c.EndedAt),
+		CampaignID:     int32(c.CampaignID),
+		Name:           c.Name,
+		Booked:         strconv.FormatFloat(c.Booked, 'f', 16, 64),
+		Actual:         sql.NullString{String: strconv.FormatFloat(c.Actual, 'f', 16, 64), Valid: true},
+		Adjustments:    sql.NullString{String: strconv.FormatFloat(c.Adjustments, 'f', 16, 64), Valid: true},
+		StartedAt:      toSQLTime(c.StartedAt),
+		EndedAt:        toSQLTime(c.EndedAt),
+		PricingModel:   c.PricingModel,
+		UnitRate:       formatUnitRate(c.UnitRate),
+		BookedUnits:    c.BookedUnits,
+		DeliveredUnits: c.DeliveredUnits,
 	}
 }
 
@@ -137,8 +264,11 @@ type Invoice struct {
 	StartedAt         *time.Time
 	EndedAt           *time.Time
 	IssuedAt          time.Time
+	CollectedAt       *time.Time
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
+	DeletedAt         *time.Time
+	Version           int
 }
 
 func (i *Invoice) ToCreateInvoiceParams() db.CreateInvoiceParams {
@@ -153,6 +283,15 @@ func (i *Invoice) ToCreateInvoiceParams() db.CreateInvoiceParams {
 	}
 }
 
+func (i *Invoice) ToUpdateInvoiceParams() db.UpdateInvoiceParams {
+	return db.UpdateInvoiceParams{
+		ID:               int32(i.ID),
+		TotalAdjustments: toSQLStringFromFloat64(i.TotalAdjustments),
+		StartedAt:        toSQLTime(i.StartedAt),
+		EndedAt:          toSQLTime(i.EndedAt),
+	}
+}
+
 func NewInvoiceFromDB(i db.OmsInvoice) (*Invoice, error) {
 	var err error
 
@@ -192,8 +331,11 @@ func NewInvoiceFromDB(i db.OmsInvoice) (*Invoice, error) {
 		StartedAt:         toTime(i.StartedAt),
 		EndedAt:           toTime(i.EndedAt),
 		CreatedAt:         i.CreatedAt.Time,
-		UpdatedAt:         i.EndedAt.Time,
+		UpdatedAt:         i.UpdatedAt.Time,
 		IssuedAt:          i.IssuedAt.Time,
+		CollectedAt:       toTime(i.CollectedAt),
+		DeletedAt:         toTime(i.DeletedAt),
+		Version:           int(i.Version),
 	}, nil
 }
 
@@ -226,4 +368,9 @@ type Paging struct {
 type List[T any] struct {
 	Items         []*T
 	NextPageToken string `json:"omitempty"`
+	// PreviousPageToken returns the page before this one, it is empty on the first page.
+	PreviousPageToken string `json:",omitempty"`
+	// TotalCount is the number of rows matching the filter across all pages, it is
+	// only counted when the request asks for it with $count=true.
+	TotalCount *int64 `json:",omitempty"`
 }
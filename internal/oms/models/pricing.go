@@ -0,0 +1,129 @@
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	// PricingModelCPM prices a line item per thousand impressions.
+	PricingModelCPM = "cpm"
+	// PricingModelCPC prices a line item per click.
+	PricingModelCPC = "cpc"
+	// PricingModelCPA prices a line item per action, such as a sign up or a sale.
+	PricingModelCPA = "cpa"
+	// PricingModelFlat is a fixed fee, booked and actual are given as amounts.
+	PricingModelFlat = "flat"
+)
+
+// amountTolerance absorbs the rounding of amounts stored as numerics, a booked or
+// actual amount sent with a priced line item may differ from its price by less.
+const amountTolerance = 0.005
+
+// unitsPerPrice is the number of units the unit rate of a pricing model is quoted for.
+var unitsPerPrice = map[string]float64{
+	PricingModelCPM: 1000,
+	PricingModelCPC: 1,
+	PricingModelCPA: 1,
+}
+
+// ApplyPricing validates the pricing fields of the line item and, unless it is
+// a flat fee, computes Booked from BookedUnits and Actual from DeliveredUnits.
+// A Booked or Actual that is sent with a priced line item must match the computed
+// amount, leave it at zero to have it computed. A line item without a pricing
+// model is a flat fee.
+func (c *CampaignLineItem) ApplyPricing() []FieldError {
+	if c.PricingModel == "" {
+		c.PricingModel = PricingModelFlat
+	}
+
+	var fieldErrors []FieldError
+
+	if c.UnitRate < 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "UnitRate", Message: "cannot be negative"})
+	}
+
+	if c.BookedUnits < 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "BookedUnits", Message: "cannot be negative"})
+	}
+
+	if c.DeliveredUnits < 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "DeliveredUnits", Message: "cannot be negative"})
+	}
+
+	if c.PricingModel == PricingModelFlat {
+		return fieldErrors
+	}
+
+	units, ok := unitsPerPrice[c.PricingModel]
+	if !ok {
+		return append(fieldErrors, FieldError{
+			Field:   "PricingModel",
+			Message: "must be one of cpm, cpc, cpa or flat",
+		})
+	}
+
+	if c.UnitRate == 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "UnitRate", Message: "is required for " + c.PricingModel})
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	booked := float64(c.BookedUnits) / units * c.UnitRate
+	actual := float64(c.DeliveredUnits) / units * c.UnitRate
+
+	if c.Booked != 0 && math.Abs(c.Booked-booked) >= amountTolerance {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "Booked",
+			Message: "does not match the " + c.PricingModel + " price of the booked units, " + formatAmount(booked),
+		})
+	}
+
+	if c.Actual != 0 && math.Abs(c.Actual-actual) >= amountTolerance {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "Actual",
+			Message: "does not match the " + c.PricingModel + " price of the delivered units, " + formatAmount(actual),
+		})
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	c.Booked = booked
+	c.Actual = actual
+
+	return nil
+}
+
+// ApplyDelivery sets the delivered units and the actual amount from the delivery
+// of the line item: impressions for cpm, clicks for cpc and conversions for cpa.
+// Flat fee line items count impressions and take the spend as their actual.
+func (c *CampaignLineItem) ApplyDelivery(totals *DeliveryTotals) {
+	switch c.PricingModel {
+	case PricingModelCPC:
+		c.DeliveredUnits = totals.Clicks
+	case PricingModelCPA:
+		c.DeliveredUnits = totals.Conversions
+	default:
+		c.DeliveredUnits = totals.Impressions
+	}
+
+	units, ok := unitsPerPrice[c.PricingModel]
+	if !ok {
+		c.Actual = totals.Spend
+		return
+	}
+
+	c.Actual = float64(c.DeliveredUnits) / units * c.UnitRate
+}
+
+func formatUnitRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
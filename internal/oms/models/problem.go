@@ -0,0 +1,49 @@
+package models
+
+// ProblemContentType is the content type of every error response of the service.
+const ProblemContentType = "application/problem+json"
+
+// Codes of the problems the service answers with. They are stable, unlike the
+// detail which explains the occurrence and may change.
+const (
+	ProblemInvalidRequest       = "invalid_request"
+	ProblemValidationFailed     = "validation_failed"
+	ProblemInvalidQuery         = "invalid_query"
+	ProblemInvalidToken         = "invalid_token"
+	ProblemTokenExpired         = "token_expired"
+	ProblemNotFound             = "not_found"
+	ProblemConflict             = "conflict"
+	ProblemHasDependents        = "has_dependents"
+	ProblemLineItemLocked       = "line_item_locked"
+	ProblemLineItemInvoiced     = "line_item_invoiced"
+	ProblemParentDeleted        = "parent_deleted"
+	ProblemInvalidState         = "invalid_state"
+	ProblemBatchFailed          = "batch_failed"
+	ProblemPreconditionRequired = "precondition_required"
+	ProblemVersionMismatch      = "version_mismatch"
+	ProblemUnsupportedMediaType = "unsupported_media_type"
+	ProblemInternal             = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details body. Code and RequestID are extension
+// members, the members after them are only set by the problems they belong to.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+	// Errors lists the rejected fields of a validation_failed problem.
+	Errors []FieldError `json:"errors,omitempty"`
+	// Dependents counts the records that block the deletion of a campaign.
+	Dependents *CampaignDependents `json:"dependents,omitempty"`
+	// Results are the outcomes of the operations of a failed batch.
+	Results []*CampaignLineItemOperationResult `json:"results,omitempty"`
+}
+
+// ProblemType is the type URI of the problems with the code.
+func ProblemType(code string) string {
+	return "urn:oms:problem:" + code
+}
@@ -0,0 +1,261 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CommissionBasisBilled pays commission on invoices once they are issued.
+	CommissionBasisBilled = "billed"
+	// CommissionBasisCollected pays commission on invoices once their payment is collected.
+	CommissionBasisCollected = "collected"
+)
+
+// CommissionPlan is the rate a sales rep earns on the revenue of the campaigns they own.
+type CommissionPlan struct {
+	ID          int
+	Name        string
+	Basis       string
+	RatePercent float64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewCommissionPlanFromDB(p *db.OmsCommissionPlan) (*CommissionPlan, error) {
+	rate, err := strconv.ParseFloat(p.RatePercent, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string rate percent not convertable to float: %s", p.RatePercent)
+	}
+
+	return &CommissionPlan{
+		ID:          int(p.ID),
+		Name:        p.Name,
+		Basis:       p.Basis,
+		RatePercent: rate,
+		CreatedAt:   p.CreatedAt.Time,
+		UpdatedAt:   p.UpdatedAt.Time,
+	}, nil
+}
+
+func (p *CommissionPlan) ToCreateCommissionPlan() db.CreateCommissionPlanParams {
+	return db.CreateCommissionPlanParams{
+		Name:        p.Name,
+		Basis:       p.Basis,
+		RatePercent: strconv.FormatFloat(p.RatePercent, 'f', -1, 64),
+	}
+}
+
+// Validate checks the name, basis and rate of the plan.
+func (p *CommissionPlan) Validate() []FieldError {
+	var fieldErrors []FieldError
+
+	if p.Name == "" {
+		fieldErrors = append(fieldErrors, FieldError{Field: "Name", Message: "is required"})
+	}
+
+	if p.Basis != CommissionBasisBilled && p.Basis != CommissionBasisCollected {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "Basis",
+			Message: "must be " + CommissionBasisBilled + " or " + CommissionBasisCollected,
+		})
+	}
+
+	if p.RatePercent < 0 || p.RatePercent > 100 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "RatePercent", Message: "must be between 0 and 100"})
+	}
+
+	return fieldErrors
+}
+
+// SalesRep is a sales person that can own campaigns.
+type SalesRep struct {
+	ID               int
+	Name             string
+	Email            string
+	CommissionPlanID *int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Version          int
+}
+
+func NewSalesRepFromDB(r *db.OmsSalesRep) *SalesRep {
+	return &SalesRep{
+		ID:               int(r.ID),
+		Name:             r.Name,
+		Email:            r.Email.String,
+		CommissionPlanID: toInt(r.CommissionPlanID),
+		CreatedAt:        r.CreatedAt.Time,
+		UpdatedAt:        r.UpdatedAt.Time,
+		Version:          int(r.Version),
+	}
+}
+
+func (r *SalesRep) ToCreateSalesRep() db.CreateSalesRepParams {
+	return db.CreateSalesRepParams{
+		Name:             r.Name,
+		Email:            sql.NullString{Valid: r.Email != "", String: r.Email},
+		CommissionPlanID: toSQLInt32(r.CommissionPlanID),
+	}
+}
+
+func (r *SalesRep) ToUpdateSalesRep(id int32) db.UpdateSalesRepParams {
+	return db.UpdateSalesRepParams{
+		ID:               id,
+		Name:             r.Name,
+		Email:            sql.NullString{Valid: r.Email != "", String: r.Email},
+		CommissionPlanID: toSQLInt32(r.CommissionPlanID),
+	}
+}
+
+// CampaignOwner is a sales rep owning a share of the revenue of a campaign.
+type CampaignOwner struct {
+	SalesRepID   int
+	SalesRepName string
+	SplitPercent float64
+}
+
+func NewCampaignOwnerFromDB(o *db.ListCampaignOwnersRow) (*CampaignOwner, error) {
+	split, err := strconv.ParseFloat(o.SplitPercent, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string split percent not convertable to float: %s", o.SplitPercent)
+	}
+
+	return &CampaignOwner{
+		SalesRepID:   int(o.SalesRepID),
+		SalesRepName: o.SalesRepName,
+		SplitPercent: split,
+	}, nil
+}
+
+func (o *CampaignOwner) ToAddCampaignOwner(campaignID int32) db.AddCampaignOwnerParams {
+	return db.AddCampaignOwnerParams{
+		CampaignID:   campaignID,
+		SalesRepID:   int32(o.SalesRepID),
+		SplitPercent: strconv.FormatFloat(o.SplitPercent, 'f', -1, 64),
+	}
+}
+
+// ValidateCampaignOwners checks that every rep owns the campaign once and that
+// the splits add up to 100 percent, an empty list removes all owners.
+func ValidateCampaignOwners(owners []*CampaignOwner) []FieldError {
+	var fieldErrors []FieldError
+
+	seen := map[int]bool{}
+	total := 0.0
+
+	for i, owner := range owners {
+		field := fmt.Sprintf("Owners[%d]", i)
+
+		if seen[owner.SalesRepID] {
+			fieldErrors = append(fieldErrors, FieldError{Field: field + ".SalesRepID", Message: "is listed more than once"})
+		}
+
+		if owner.SplitPercent <= 0 || owner.SplitPercent > 100 {
+			fieldErrors = append(fieldErrors, FieldError{Field: field + ".SplitPercent", Message: "must be above 0 and at most 100"})
+		}
+
+		seen[owner.SalesRepID] = true
+		total += owner.SplitPercent
+	}
+
+	if len(owners) > 0 && math.Abs(total-100) > 0.0001 {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "Owners",
+			Message: "splits must add up to 100, got " + strconv.FormatFloat(total, 'f', -1, 64),
+		})
+	}
+
+	return fieldErrors
+}
+
+// CommissionReport holds the commission earned by each sales rep over a period.
+type CommissionReport struct {
+	Period string
+	// PeriodStart is inclusive and PeriodEnd exclusive.
+	PeriodStart     time.Time
+	PeriodEnd       time.Time
+	Lines           []*CommissionReportLine
+	TotalRevenue    float64
+	TotalCommission float64
+}
+
+// CommissionReportLine is the commission of a single sales rep, Revenue is the
+// share of the rep of the invoices billed or collected in the period. Invoices
+// are cumulative, so each one counts only what it adds to the previous issued
+// invoice of its campaign.
+type CommissionReportLine struct {
+	SalesRepID         int
+	SalesRepName       string
+	CommissionPlanID   int
+	CommissionPlanName string
+	Basis              string
+	RatePercent        float64
+	InvoiceCount       int
+	Revenue            float64
+	Commission         float64
+}
+
+func NewCommissionReportFromDB(period string, start, end time.Time,
+	rows []db.ListCommissionRevenueRow) (*CommissionReport, error) {
+	report := &CommissionReport{
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]*CommissionReportLine, len(rows)),
+	}
+
+	for i, row := range rows {
+		rate, err := strconv.ParseFloat(row.RatePercent, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "string rate percent not convertable to float: %s", row.RatePercent)
+		}
+
+		revenue, err := strconv.ParseFloat(row.Revenue, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "string revenue not convertable to float: %s", row.Revenue)
+		}
+
+		commission := revenue * rate / 100
+
+		report.Lines[i] = &CommissionReportLine{
+			SalesRepID:         int(row.SalesRepID),
+			SalesRepName:       row.SalesRepName,
+			CommissionPlanID:   int(row.CommissionPlanID),
+			CommissionPlanName: row.CommissionPlanName,
+			Basis:              row.Basis,
+			RatePercent:        rate,
+			InvoiceCount:       int(row.InvoiceCount),
+			Revenue:            revenue,
+			Commission:         commission,
+		}
+		report.TotalRevenue += revenue
+		report.TotalCommission += commission
+	}
+
+	return report, nil
+}
+
+func toInt(i sql.NullInt32) *int {
+	if !i.Valid {
+		return nil
+	}
+
+	value := int(i.Int32)
+
+	return &value
+}
+
+func toSQLInt32(i *int) sql.NullInt32 {
+	if i == nil {
+		return sql.NullInt32{Valid: false}
+	}
+
+	return sql.NullInt32{Valid: true, Int32: int32(*i)}
+}
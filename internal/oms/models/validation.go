@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+// FieldError describes why the value of a single field was rejected.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidateFlight checks that the campaign does not end before it starts.
+func (c *Campaign) ValidateFlight() []FieldError {
+	return validateFlightOrder(c.StartedAt, c.EndedAt)
+}
+
+// ValidateLineItemBounds checks that the earliest and latest flight dates of the
+// line items of the campaign still fall within the flight of the campaign.
+func (c *Campaign) ValidateLineItemBounds(earliest, latest *time.Time) []FieldError {
+	var fieldErrors []FieldError
+
+	if c.StartedAt != nil && earliest != nil && earliest.Before(*c.StartedAt) {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "StartedAt",
+			Message: "campaign cannot start after its line items, earliest line item date is " + formatDate(earliest),
+		})
+	}
+
+	if c.EndedAt != nil && latest != nil && latest.After(*c.EndedAt) {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "EndedAt",
+			Message: "campaign cannot end before its line items, latest line item date is " + formatDate(latest),
+		})
+	}
+
+	return fieldErrors
+}
+
+// ValidateFlight checks that the line item does not end before it starts and,
+// when the campaign is given, that its flight falls within the flight of the campaign.
+func (c *CampaignLineItem) ValidateFlight(campaign *Campaign) []FieldError {
+	fieldErrors := validateFlightOrder(c.StartedAt, c.EndedAt)
+
+	if campaign == nil {
+		return fieldErrors
+	}
+
+	fieldErrors = append(fieldErrors, validateWithinFlight("StartedAt", c.StartedAt, campaign)...)
+	fieldErrors = append(fieldErrors, validateWithinFlight("EndedAt", c.EndedAt, campaign)...)
+
+	return fieldErrors
+}
+
+// ValidateFlight checks that the invoice period does not end before it starts.
+func (i *Invoice) ValidateFlight() []FieldError {
+	return validateFlightOrder(i.StartedAt, i.EndedAt)
+}
+
+func validateFlightOrder(startedAt, endedAt *time.Time) []FieldError {
+	if startedAt != nil && endedAt != nil && endedAt.Before(*startedAt) {
+		return []FieldError{{Field: "EndedAt", Message: "cannot be before the start " + formatDate(startedAt)}}
+	}
+
+	return nil
+}
+
+func validateWithinFlight(field string, value *time.Time, campaign *Campaign) []FieldError {
+	if value == nil {
+		return nil
+	}
+
+	if campaign.StartedAt != nil && value.Before(*campaign.StartedAt) {
+		return []FieldError{{Field: field, Message: "cannot be before the campaign starts " + formatDate(campaign.StartedAt)}}
+	}
+
+	if campaign.EndedAt != nil && value.After(*campaign.EndedAt) {
+		return []FieldError{{Field: field, Message: "cannot be after the campaign ends " + formatDate(campaign.EndedAt)}}
+	}
+
+	return nil
+}
+
+func formatDate(t *time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/pkg/errors"
+)
+
+const (
+	VarianceGroupByLineItem = "lineItem"
+	VarianceGroupByCampaign = "campaign"
+
+	VarianceSortAbsolute = "absolute"
+	VarianceSortPercent  = "percent"
+
+	VarianceUnder = "under"
+	VarianceOver  = "over"
+)
+
+// VarianceReport lists the line items, or campaigns, whose actual differs from
+// booked by more than the threshold.
+type VarianceReport struct {
+	// Threshold is a percentage of booked when ThresholdPercent is set and an amount otherwise.
+	Threshold        float64
+	ThresholdPercent bool
+	GroupBy          string
+	Sort             string
+	Items            []*VarianceReportItem
+}
+
+// VarianceReportItem is a line item, or a campaign when grouped by campaign, that
+// under or over delivered. LineItemID is zero for campaigns.
+type VarianceReportItem struct {
+	CampaignID    int
+	CampaignName  string
+	LineItemID    int    `json:",omitempty"`
+	LineItemName  string `json:",omitempty"`
+	LineItemCount int    `json:",omitempty"`
+	Booked        float64
+	Actual        float64
+	// Variance is actual minus booked, negative when under delivered.
+	Variance float64
+	// VariancePercent is the variance as a percentage of booked, nil when nothing was booked.
+	VariancePercent *float64
+	Status          string
+}
+
+func NewLineItemVarianceFromDB(row *db.ListLineItemVarianceRow) (*VarianceReportItem, error) {
+	item, err := newVarianceReportItem(row.Booked, row.Actual)
+	if err != nil {
+		return nil, err
+	}
+
+	item.CampaignID = int(row.CampaignID)
+	item.CampaignName = row.CampaignName
+	item.LineItemID = int(row.LineItemID)
+	item.LineItemName = row.LineItemName
+
+	return item, nil
+}
+
+func NewCampaignVarianceFromDB(row *db.ListCampaignVarianceRow) (*VarianceReportItem, error) {
+	item, err := newVarianceReportItem(row.Booked, row.Actual)
+	if err != nil {
+		return nil, err
+	}
+
+	item.CampaignID = int(row.CampaignID)
+	item.CampaignName = row.CampaignName
+	item.LineItemCount = int(row.LineItemCount)
+
+	return item, nil
+}
+
+func newVarianceReportItem(bookedStr, actualStr string) (*VarianceReportItem, error) {
+	booked, err := strconv.ParseFloat(bookedStr, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string booked not convertable to float: %s", bookedStr)
+	}
+
+	actual, err := strconv.ParseFloat(actualStr, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "string actual not convertable to float: %s", actualStr)
+	}
+
+	item := &VarianceReportItem{Booked: booked, Actual: actual, Variance: actual - booked, Status: VarianceOver}
+	if item.Variance < 0 {
+		item.Status = VarianceUnder
+	}
+
+	if booked != 0 {
+		percent := item.Variance / booked * 100
+		item.VariancePercent = &percent
+	}
+
+	return item, nil
+}
+
+// SortItems orders the items by the size of their variance, largest first. Items
+// without a percentage, nothing booked, come first when sorting by percentage.
+func (r *VarianceReport) SortItems() {
+	magnitude := func(item *VarianceReportItem) float64 {
+		if r.Sort != VarianceSortPercent {
+			return math.Abs(item.Variance)
+		}
+
+		if item.VariancePercent == nil {
+			return math.Inf(1)
+		}
+
+		return math.Abs(*item.VariancePercent)
+	}
+
+	sort.SliceStable(r.Items, func(i, j int) bool {
+		return magnitude(r.Items[i]) > magnitude(r.Items[j])
+	})
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag formats the version of a resource as the strong entity tag served in the ETag header.
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ParseETag returns the version in a strong entity tag, weak tags never name a version.
+func ParseETag(tag string) (int, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/pkg/errors"
+)
+
+// The events a webhook subscription can receive. Generating an invoice is sent
+// as invoice.generated, not as invoice.created. Deleting moves a record to the
+// trash, restoring takes it out and purging removes it from the trash for good.
+const (
+	WebhookEventCampaignCreated  = "campaign.created"
+	WebhookEventCampaignUpdated  = "campaign.updated"
+	WebhookEventCampaignDeleted  = "campaign.deleted"
+	WebhookEventCampaignRestored = "campaign.restored"
+	WebhookEventCampaignPurged   = "campaign.purged"
+	WebhookEventLineItemCreated  = "line_item.created"
+	WebhookEventLineItemUpdated  = "line_item.updated"
+	WebhookEventLineItemDeleted  = "line_item.deleted"
+	WebhookEventLineItemRestored = "line_item.restored"
+	WebhookEventLineItemPurged   = "line_item.purged"
+	WebhookEventInvoiceCreated   = "invoice.created"
+	WebhookEventInvoiceUpdated   = "invoice.updated"
+	WebhookEventInvoiceDeleted   = "invoice.deleted"
+	WebhookEventInvoiceRestored  = "invoice.restored"
+	WebhookEventInvoicePurged    = "invoice.purged"
+	WebhookEventInvoiceGenerated = "invoice.generated"
+)
+
+// WebhookEventTypes lists every event type in the order they are documented.
+var WebhookEventTypes = []string{
+	WebhookEventCampaignCreated, WebhookEventCampaignUpdated, WebhookEventCampaignDeleted,
+	WebhookEventCampaignRestored, WebhookEventCampaignPurged,
+	WebhookEventLineItemCreated, WebhookEventLineItemUpdated, WebhookEventLineItemDeleted,
+	WebhookEventLineItemRestored, WebhookEventLineItemPurged,
+	WebhookEventInvoiceCreated, WebhookEventInvoiceUpdated, WebhookEventInvoiceDeleted,
+	WebhookEventInvoiceRestored, WebhookEventInvoicePurged,
+	WebhookEventInvoiceGenerated,
+}
+
+// The states of a webhook delivery.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// Headers of a webhook delivery. The signature is "t=<unix time>,v1=<hex HMAC-SHA256>"
+// of "<unix time>.<body>" keyed with the secret of the subscription.
+const (
+	WebhookEventHeader     = "X-Oms-Event"
+	WebhookDeliveryHeader  = "X-Oms-Delivery"
+	WebhookSignatureHeader = "X-Oms-Signature"
+)
+
+const minWebhookSecretLength = 16
+
+var (
+	ErrWebhookSignatureInvalid = errors.New("the webhook signature is invalid")
+	ErrWebhookSignatureExpired = errors.New("the webhook signature is too old")
+)
+
+// WebhookSubscription sends the events of its event types to its URL.
+type WebhookSubscription struct {
+	ID         int
+	URL        string
+	EventTypes []string
+	// Secret signs the deliveries. It is only returned when the subscription is created.
+	Secret    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+// NewWebhookSubscriptionFromDB leaves the secret out.
+func NewWebhookSubscriptionFromDB(s *db.OmsWebhookSubscription) *WebhookSubscription {
+	return &WebhookSubscription{
+		ID:         int(s.ID),
+		URL:        s.Url,
+		EventTypes: s.EventTypes,
+		CreatedAt:  s.CreatedAt.Time,
+		UpdatedAt:  s.UpdatedAt.Time,
+		Version:    int(s.Version),
+	}
+}
+
+func (s *WebhookSubscription) ToCreateWebhookSubscription() db.CreateWebhookSubscriptionParams {
+	return db.CreateWebhookSubscriptionParams{Url: s.URL, EventTypes: s.EventTypes, Secret: s.Secret}
+}
+
+func (s *WebhookSubscription) ToUpdateWebhookSubscription(id int32) db.UpdateWebhookSubscriptionParams {
+	return db.UpdateWebhookSubscriptionParams{ID: id, Url: s.URL, EventTypes: s.EventTypes}
+}
+
+// Validate checks that the URL is an absolute http or https URL, that the event
+// types are known and that a secret, when one is given, is long enough.
+func (s *WebhookSubscription) Validate() []FieldError {
+	var fieldErrors []FieldError
+
+	if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fieldErrors = append(fieldErrors, FieldError{Field: "URL", Message: "must be an absolute http or https URL"})
+	}
+
+	if len(s.EventTypes) == 0 {
+		fieldErrors = append(fieldErrors, FieldError{Field: "EventTypes", Message: "is required"})
+	}
+
+	for _, eventType := range s.EventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   "EventTypes",
+				Message: eventType + " is not one of " + strings.Join(WebhookEventTypes, ", "),
+			})
+		}
+	}
+
+	if s.Secret != "" && len(s.Secret) < minWebhookSecretLength {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "Secret",
+			Message: "must be at least " + strconv.Itoa(minWebhookSecretLength) + " characters",
+		})
+	}
+
+	return fieldErrors
+}
+
+// WebhookEvent is the body of a webhook delivery, Data is the wire form of the
+// record the event is about as the /v1 API returns it.
+type WebhookEvent struct {
+	ID        int
+	Type      string
+	CreatedAt time.Time
+	Data      json.RawMessage
+}
+
+// WebhookDelivery is an entry of the delivery log, the outcome of sending an event
+// to a subscription.
+type WebhookDelivery struct {
+	ID             int
+	SubscriptionID int
+	EventID        int
+	EventType      string
+	Status         string
+	Attempts       int
+	// NextAttemptAt is only set while the delivery is pending.
+	NextAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewWebhookDeliveryFromDB(d *db.ListWebhookDeliveriesRow) *WebhookDelivery {
+	delivery := &WebhookDelivery{
+		ID:             int(d.ID),
+		SubscriptionID: int(d.SubscriptionID),
+		EventID:        int(d.EventID),
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       int(d.Attempts),
+		ResponseStatus: toInt(d.ResponseStatus),
+		LastError:      d.LastError.String,
+		DeliveredAt:    toTime(d.DeliveredAt),
+		CreatedAt:      d.CreatedAt.Time,
+		UpdatedAt:      d.UpdatedAt.Time,
+	}
+
+	if d.Status == WebhookDeliveryPending {
+		delivery.NextAttemptAt = &d.NextAttemptAt
+	}
+
+	return delivery
+}
+
+// SignWebhook returns the signature header of a delivery body sent at the time.
+func SignWebhook(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(webhookMAC(secret, timestamp, body))
+}
+
+// VerifyWebhookSignature checks the signature header of a delivery body against
+// the secret, and that it was signed no longer than tolerance before now so a
+// captured delivery cannot be replayed later.
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp, signature string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	mac, err := hex.DecodeString(signature)
+	if err != nil || timestamp == "" || !hmac.Equal(mac, webhookMAC(secret, timestamp, body)) {
+		return ErrWebhookSignatureInvalid
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookSignatureInvalid
+	}
+
+	if now.Sub(time.Unix(signedAt, 0)) > tolerance {
+		return ErrWebhookSignatureExpired
+	}
+
+	return nil
+}
+
+func webhookMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
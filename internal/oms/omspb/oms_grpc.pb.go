@@ -0,0 +1,1085 @@
+// The gRPC API of the order management system. It serves the same campaigns, line
+// items and invoices as the /v1 REST API, and names the fields the same way.
+//
+// Regenerate the Go code with `make proto` after changing this file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: oms.proto
+
+package omspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CampaignService_CreateCampaign_FullMethodName     = "/oms.v1.CampaignService/CreateCampaign"
+	CampaignService_GetCampaign_FullMethodName        = "/oms.v1.CampaignService/GetCampaign"
+	CampaignService_ListCampaigns_FullMethodName      = "/oms.v1.CampaignService/ListCampaigns"
+	CampaignService_UpdateCampaign_FullMethodName     = "/oms.v1.CampaignService/UpdateCampaign"
+	CampaignService_DeleteCampaign_FullMethodName     = "/oms.v1.CampaignService/DeleteCampaign"
+	CampaignService_RestoreCampaign_FullMethodName    = "/oms.v1.CampaignService/RestoreCampaign"
+	CampaignService_GetCampaignSummary_FullMethodName = "/oms.v1.CampaignService/GetCampaignSummary"
+	CampaignService_GenerateInvoice_FullMethodName    = "/oms.v1.CampaignService/GenerateInvoice"
+	CampaignService_CloneCampaign_FullMethodName      = "/oms.v1.CampaignService/CloneCampaign"
+)
+
+// CampaignServiceClient is the client API for CampaignService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CampaignService manages campaigns.
+type CampaignServiceClient interface {
+	CreateCampaign(ctx context.Context, in *CreateCampaignRequest, opts ...grpc.CallOption) (*Campaign, error)
+	GetCampaign(ctx context.Context, in *GetCampaignRequest, opts ...grpc.CallOption) (*Campaign, error)
+	// ListCampaigns streams every campaign matching the request, the campaigns that
+	// are archiving are left out.
+	ListCampaigns(ctx context.Context, in *ListCampaignsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Campaign], error)
+	UpdateCampaign(ctx context.Context, in *UpdateCampaignRequest, opts ...grpc.CallOption) (*Campaign, error)
+	DeleteCampaign(ctx context.Context, in *DeleteCampaignRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RestoreCampaign(ctx context.Context, in *RestoreCampaignRequest, opts ...grpc.CallOption) (*Campaign, error)
+	GetCampaignSummary(ctx context.Context, in *GetCampaignSummaryRequest, opts ...grpc.CallOption) (*CampaignSummary, error)
+	GenerateInvoice(ctx context.Context, in *GenerateInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	CloneCampaign(ctx context.Context, in *CloneCampaignRequest, opts ...grpc.CallOption) (*Campaign, error)
+}
+
+type campaignServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCampaignServiceClient(cc grpc.ClientConnInterface) CampaignServiceClient {
+	return &campaignServiceClient{cc}
+}
+
+func (c *campaignServiceClient) CreateCampaign(ctx context.Context, in *CreateCampaignRequest, opts ...grpc.CallOption) (*Campaign, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Campaign)
+	err := c.cc.Invoke(ctx, CampaignService_CreateCampaign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) GetCampaign(ctx context.Context, in *GetCampaignRequest, opts ...grpc.CallOption) (*Campaign, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Campaign)
+	err := c.cc.Invoke(ctx, CampaignService_GetCampaign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) ListCampaigns(ctx context.Context, in *ListCampaignsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Campaign], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CampaignService_ServiceDesc.Streams[0], CampaignService_ListCampaigns_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListCampaignsRequest, Campaign]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CampaignService_ListCampaignsClient = grpc.ServerStreamingClient[Campaign]
+
+func (c *campaignServiceClient) UpdateCampaign(ctx context.Context, in *UpdateCampaignRequest, opts ...grpc.CallOption) (*Campaign, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Campaign)
+	err := c.cc.Invoke(ctx, CampaignService_UpdateCampaign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) DeleteCampaign(ctx context.Context, in *DeleteCampaignRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CampaignService_DeleteCampaign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) RestoreCampaign(ctx context.Context, in *RestoreCampaignRequest, opts ...grpc.CallOption) (*Campaign, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Campaign)
+	err := c.cc.Invoke(ctx, CampaignService_RestoreCampaign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) GetCampaignSummary(ctx context.Context, in *GetCampaignSummaryRequest, opts ...grpc.CallOption) (*CampaignSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CampaignSummary)
+	err := c.cc.Invoke(ctx, CampaignService_GetCampaignSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) GenerateInvoice(ctx context.Context, in *GenerateInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, CampaignService_GenerateInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *campaignServiceClient) CloneCampaign(ctx context.Context, in *CloneCampaignRequest, opts ...grpc.CallOption) (*Campaign, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Campaign)
+	err := c.cc.Invoke(ctx, CampaignService_CloneCampaign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CampaignServiceServer is the server API for CampaignService service.
+// All implementations must embed UnimplementedCampaignServiceServer
+// for forward compatibility.
+//
+// CampaignService manages campaigns.
+type CampaignServiceServer interface {
+	CreateCampaign(context.Context, *CreateCampaignRequest) (*Campaign, error)
+	GetCampaign(context.Context, *GetCampaignRequest) (*Campaign, error)
+	// ListCampaigns streams every campaign matching the request, the campaigns that
+	// are archiving are left out.
+	ListCampaigns(*ListCampaignsRequest, grpc.ServerStreamingServer[Campaign]) error
+	UpdateCampaign(context.Context, *UpdateCampaignRequest) (*Campaign, error)
+	DeleteCampaign(context.Context, *DeleteCampaignRequest) (*emptypb.Empty, error)
+	RestoreCampaign(context.Context, *RestoreCampaignRequest) (*Campaign, error)
+	GetCampaignSummary(context.Context, *GetCampaignSummaryRequest) (*CampaignSummary, error)
+	GenerateInvoice(context.Context, *GenerateInvoiceRequest) (*Invoice, error)
+	CloneCampaign(context.Context, *CloneCampaignRequest) (*Campaign, error)
+	mustEmbedUnimplementedCampaignServiceServer()
+}
+
+// UnimplementedCampaignServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCampaignServiceServer struct{}
+
+func (UnimplementedCampaignServiceServer) CreateCampaign(context.Context, *CreateCampaignRequest) (*Campaign, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCampaign not implemented")
+}
+func (UnimplementedCampaignServiceServer) GetCampaign(context.Context, *GetCampaignRequest) (*Campaign, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCampaign not implemented")
+}
+func (UnimplementedCampaignServiceServer) ListCampaigns(*ListCampaignsRequest, grpc.ServerStreamingServer[Campaign]) error {
+	return status.Errorf(codes.Unimplemented, "method ListCampaigns not implemented")
+}
+func (UnimplementedCampaignServiceServer) UpdateCampaign(context.Context, *UpdateCampaignRequest) (*Campaign, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCampaign not implemented")
+}
+func (UnimplementedCampaignServiceServer) DeleteCampaign(context.Context, *DeleteCampaignRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCampaign not implemented")
+}
+func (UnimplementedCampaignServiceServer) RestoreCampaign(context.Context, *RestoreCampaignRequest) (*Campaign, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCampaign not implemented")
+}
+func (UnimplementedCampaignServiceServer) GetCampaignSummary(context.Context, *GetCampaignSummaryRequest) (*CampaignSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCampaignSummary not implemented")
+}
+func (UnimplementedCampaignServiceServer) GenerateInvoice(context.Context, *GenerateInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateInvoice not implemented")
+}
+func (UnimplementedCampaignServiceServer) CloneCampaign(context.Context, *CloneCampaignRequest) (*Campaign, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneCampaign not implemented")
+}
+func (UnimplementedCampaignServiceServer) mustEmbedUnimplementedCampaignServiceServer() {}
+func (UnimplementedCampaignServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeCampaignServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CampaignServiceServer will
+// result in compilation errors.
+type UnsafeCampaignServiceServer interface {
+	mustEmbedUnimplementedCampaignServiceServer()
+}
+
+func RegisterCampaignServiceServer(s grpc.ServiceRegistrar, srv CampaignServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCampaignServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CampaignService_ServiceDesc, srv)
+}
+
+func _CampaignService_CreateCampaign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCampaignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).CreateCampaign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_CreateCampaign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).CreateCampaign(ctx, req.(*CreateCampaignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_GetCampaign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCampaignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).GetCampaign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_GetCampaign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).GetCampaign(ctx, req.(*GetCampaignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_ListCampaigns_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCampaignsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CampaignServiceServer).ListCampaigns(m, &grpc.GenericServerStream[ListCampaignsRequest, Campaign]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CampaignService_ListCampaignsServer = grpc.ServerStreamingServer[Campaign]
+
+func _CampaignService_UpdateCampaign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCampaignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).UpdateCampaign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_UpdateCampaign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).UpdateCampaign(ctx, req.(*UpdateCampaignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_DeleteCampaign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCampaignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).DeleteCampaign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_DeleteCampaign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).DeleteCampaign(ctx, req.(*DeleteCampaignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_RestoreCampaign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCampaignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).RestoreCampaign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_RestoreCampaign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).RestoreCampaign(ctx, req.(*RestoreCampaignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_GetCampaignSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCampaignSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).GetCampaignSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_GetCampaignSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).GetCampaignSummary(ctx, req.(*GetCampaignSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_GenerateInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).GenerateInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_GenerateInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).GenerateInvoice(ctx, req.(*GenerateInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CampaignService_CloneCampaign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneCampaignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CampaignServiceServer).CloneCampaign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CampaignService_CloneCampaign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CampaignServiceServer).CloneCampaign(ctx, req.(*CloneCampaignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CampaignService_ServiceDesc is the grpc.ServiceDesc for CampaignService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CampaignService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "oms.v1.CampaignService",
+	HandlerType: (*CampaignServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCampaign",
+			Handler:    _CampaignService_CreateCampaign_Handler,
+		},
+		{
+			MethodName: "GetCampaign",
+			Handler:    _CampaignService_GetCampaign_Handler,
+		},
+		{
+			MethodName: "UpdateCampaign",
+			Handler:    _CampaignService_UpdateCampaign_Handler,
+		},
+		{
+			MethodName: "DeleteCampaign",
+			Handler:    _CampaignService_DeleteCampaign_Handler,
+		},
+		{
+			MethodName: "RestoreCampaign",
+			Handler:    _CampaignService_RestoreCampaign_Handler,
+		},
+		{
+			MethodName: "GetCampaignSummary",
+			Handler:    _CampaignService_GetCampaignSummary_Handler,
+		},
+		{
+			MethodName: "GenerateInvoice",
+			Handler:    _CampaignService_GenerateInvoice_Handler,
+		},
+		{
+			MethodName: "CloneCampaign",
+			Handler:    _CampaignService_CloneCampaign_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListCampaigns",
+			Handler:       _CampaignService_ListCampaigns_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "oms.proto",
+}
+
+const (
+	LineItemService_CreateLineItem_FullMethodName  = "/oms.v1.LineItemService/CreateLineItem"
+	LineItemService_GetLineItem_FullMethodName     = "/oms.v1.LineItemService/GetLineItem"
+	LineItemService_ListLineItems_FullMethodName   = "/oms.v1.LineItemService/ListLineItems"
+	LineItemService_UpdateLineItem_FullMethodName  = "/oms.v1.LineItemService/UpdateLineItem"
+	LineItemService_DeleteLineItem_FullMethodName  = "/oms.v1.LineItemService/DeleteLineItem"
+	LineItemService_RestoreLineItem_FullMethodName = "/oms.v1.LineItemService/RestoreLineItem"
+)
+
+// LineItemServiceClient is the client API for LineItemService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LineItemService manages the line items of campaigns.
+type LineItemServiceClient interface {
+	CreateLineItem(ctx context.Context, in *CreateLineItemRequest, opts ...grpc.CallOption) (*LineItem, error)
+	GetLineItem(ctx context.Context, in *GetLineItemRequest, opts ...grpc.CallOption) (*LineItem, error)
+	// ListLineItems streams every line item matching the request.
+	ListLineItems(ctx context.Context, in *ListLineItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LineItem], error)
+	UpdateLineItem(ctx context.Context, in *UpdateLineItemRequest, opts ...grpc.CallOption) (*LineItem, error)
+	DeleteLineItem(ctx context.Context, in *DeleteLineItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RestoreLineItem(ctx context.Context, in *RestoreLineItemRequest, opts ...grpc.CallOption) (*LineItem, error)
+}
+
+type lineItemServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLineItemServiceClient(cc grpc.ClientConnInterface) LineItemServiceClient {
+	return &lineItemServiceClient{cc}
+}
+
+func (c *lineItemServiceClient) CreateLineItem(ctx context.Context, in *CreateLineItemRequest, opts ...grpc.CallOption) (*LineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LineItem)
+	err := c.cc.Invoke(ctx, LineItemService_CreateLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineItemServiceClient) GetLineItem(ctx context.Context, in *GetLineItemRequest, opts ...grpc.CallOption) (*LineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LineItem)
+	err := c.cc.Invoke(ctx, LineItemService_GetLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineItemServiceClient) ListLineItems(ctx context.Context, in *ListLineItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LineItem], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LineItemService_ServiceDesc.Streams[0], LineItemService_ListLineItems_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListLineItemsRequest, LineItem]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LineItemService_ListLineItemsClient = grpc.ServerStreamingClient[LineItem]
+
+func (c *lineItemServiceClient) UpdateLineItem(ctx context.Context, in *UpdateLineItemRequest, opts ...grpc.CallOption) (*LineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LineItem)
+	err := c.cc.Invoke(ctx, LineItemService_UpdateLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineItemServiceClient) DeleteLineItem(ctx context.Context, in *DeleteLineItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LineItemService_DeleteLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineItemServiceClient) RestoreLineItem(ctx context.Context, in *RestoreLineItemRequest, opts ...grpc.CallOption) (*LineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LineItem)
+	err := c.cc.Invoke(ctx, LineItemService_RestoreLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LineItemServiceServer is the server API for LineItemService service.
+// All implementations must embed UnimplementedLineItemServiceServer
+// for forward compatibility.
+//
+// LineItemService manages the line items of campaigns.
+type LineItemServiceServer interface {
+	CreateLineItem(context.Context, *CreateLineItemRequest) (*LineItem, error)
+	GetLineItem(context.Context, *GetLineItemRequest) (*LineItem, error)
+	// ListLineItems streams every line item matching the request.
+	ListLineItems(*ListLineItemsRequest, grpc.ServerStreamingServer[LineItem]) error
+	UpdateLineItem(context.Context, *UpdateLineItemRequest) (*LineItem, error)
+	DeleteLineItem(context.Context, *DeleteLineItemRequest) (*emptypb.Empty, error)
+	RestoreLineItem(context.Context, *RestoreLineItemRequest) (*LineItem, error)
+	mustEmbedUnimplementedLineItemServiceServer()
+}
+
+// UnimplementedLineItemServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLineItemServiceServer struct{}
+
+func (UnimplementedLineItemServiceServer) CreateLineItem(context.Context, *CreateLineItemRequest) (*LineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLineItem not implemented")
+}
+func (UnimplementedLineItemServiceServer) GetLineItem(context.Context, *GetLineItemRequest) (*LineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLineItem not implemented")
+}
+func (UnimplementedLineItemServiceServer) ListLineItems(*ListLineItemsRequest, grpc.ServerStreamingServer[LineItem]) error {
+	return status.Errorf(codes.Unimplemented, "method ListLineItems not implemented")
+}
+func (UnimplementedLineItemServiceServer) UpdateLineItem(context.Context, *UpdateLineItemRequest) (*LineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLineItem not implemented")
+}
+func (UnimplementedLineItemServiceServer) DeleteLineItem(context.Context, *DeleteLineItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLineItem not implemented")
+}
+func (UnimplementedLineItemServiceServer) RestoreLineItem(context.Context, *RestoreLineItemRequest) (*LineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreLineItem not implemented")
+}
+func (UnimplementedLineItemServiceServer) mustEmbedUnimplementedLineItemServiceServer() {}
+func (UnimplementedLineItemServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeLineItemServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LineItemServiceServer will
+// result in compilation errors.
+type UnsafeLineItemServiceServer interface {
+	mustEmbedUnimplementedLineItemServiceServer()
+}
+
+func RegisterLineItemServiceServer(s grpc.ServiceRegistrar, srv LineItemServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLineItemServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LineItemService_ServiceDesc, srv)
+}
+
+func _LineItemService_CreateLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineItemServiceServer).CreateLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineItemService_CreateLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineItemServiceServer).CreateLineItem(ctx, req.(*CreateLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineItemService_GetLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineItemServiceServer).GetLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineItemService_GetLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineItemServiceServer).GetLineItem(ctx, req.(*GetLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineItemService_ListLineItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListLineItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LineItemServiceServer).ListLineItems(m, &grpc.GenericServerStream[ListLineItemsRequest, LineItem]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LineItemService_ListLineItemsServer = grpc.ServerStreamingServer[LineItem]
+
+func _LineItemService_UpdateLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineItemServiceServer).UpdateLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineItemService_UpdateLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineItemServiceServer).UpdateLineItem(ctx, req.(*UpdateLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineItemService_DeleteLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineItemServiceServer).DeleteLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineItemService_DeleteLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineItemServiceServer).DeleteLineItem(ctx, req.(*DeleteLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineItemService_RestoreLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineItemServiceServer).RestoreLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineItemService_RestoreLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineItemServiceServer).RestoreLineItem(ctx, req.(*RestoreLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LineItemService_ServiceDesc is the grpc.ServiceDesc for LineItemService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LineItemService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "oms.v1.LineItemService",
+	HandlerType: (*LineItemServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateLineItem",
+			Handler:    _LineItemService_CreateLineItem_Handler,
+		},
+		{
+			MethodName: "GetLineItem",
+			Handler:    _LineItemService_GetLineItem_Handler,
+		},
+		{
+			MethodName: "UpdateLineItem",
+			Handler:    _LineItemService_UpdateLineItem_Handler,
+		},
+		{
+			MethodName: "DeleteLineItem",
+			Handler:    _LineItemService_DeleteLineItem_Handler,
+		},
+		{
+			MethodName: "RestoreLineItem",
+			Handler:    _LineItemService_RestoreLineItem_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListLineItems",
+			Handler:       _LineItemService_ListLineItems_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "oms.proto",
+}
+
+const (
+	InvoiceService_CreateInvoice_FullMethodName  = "/oms.v1.InvoiceService/CreateInvoice"
+	InvoiceService_GetInvoice_FullMethodName     = "/oms.v1.InvoiceService/GetInvoice"
+	InvoiceService_ListInvoices_FullMethodName   = "/oms.v1.InvoiceService/ListInvoices"
+	InvoiceService_AdjustInvoice_FullMethodName  = "/oms.v1.InvoiceService/AdjustInvoice"
+	InvoiceService_CollectInvoice_FullMethodName = "/oms.v1.InvoiceService/CollectInvoice"
+	InvoiceService_DeleteInvoice_FullMethodName  = "/oms.v1.InvoiceService/DeleteInvoice"
+	InvoiceService_RestoreInvoice_FullMethodName = "/oms.v1.InvoiceService/RestoreInvoice"
+)
+
+// InvoiceServiceClient is the client API for InvoiceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// InvoiceService manages invoices.
+type InvoiceServiceClient interface {
+	CreateInvoice(ctx context.Context, in *CreateInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	// ListInvoices streams every invoice matching the request.
+	ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Invoice], error)
+	AdjustInvoice(ctx context.Context, in *AdjustInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	CollectInvoice(ctx context.Context, in *CollectInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	DeleteInvoice(ctx context.Context, in *DeleteInvoiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RestoreInvoice(ctx context.Context, in *RestoreInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+}
+
+type invoiceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInvoiceServiceClient(cc grpc.ClientConnInterface) InvoiceServiceClient {
+	return &invoiceServiceClient{cc}
+}
+
+func (c *invoiceServiceClient) CreateInvoice(ctx context.Context, in *CreateInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_CreateInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_GetInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Invoice], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[0], InvoiceService_ListInvoices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListInvoicesRequest, Invoice]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InvoiceService_ListInvoicesClient = grpc.ServerStreamingClient[Invoice]
+
+func (c *invoiceServiceClient) AdjustInvoice(ctx context.Context, in *AdjustInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_AdjustInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) CollectInvoice(ctx context.Context, in *CollectInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_CollectInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) DeleteInvoice(ctx context.Context, in *DeleteInvoiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, InvoiceService_DeleteInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) RestoreInvoice(ctx context.Context, in *RestoreInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_RestoreInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvoiceServiceServer is the server API for InvoiceService service.
+// All implementations must embed UnimplementedInvoiceServiceServer
+// for forward compatibility.
+//
+// InvoiceService manages invoices.
+type InvoiceServiceServer interface {
+	CreateInvoice(context.Context, *CreateInvoiceRequest) (*Invoice, error)
+	GetInvoice(context.Context, *GetInvoiceRequest) (*Invoice, error)
+	// ListInvoices streams every invoice matching the request.
+	ListInvoices(*ListInvoicesRequest, grpc.ServerStreamingServer[Invoice]) error
+	AdjustInvoice(context.Context, *AdjustInvoiceRequest) (*Invoice, error)
+	CollectInvoice(context.Context, *CollectInvoiceRequest) (*Invoice, error)
+	DeleteInvoice(context.Context, *DeleteInvoiceRequest) (*emptypb.Empty, error)
+	RestoreInvoice(context.Context, *RestoreInvoiceRequest) (*Invoice, error)
+	mustEmbedUnimplementedInvoiceServiceServer()
+}
+
+// UnimplementedInvoiceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInvoiceServiceServer struct{}
+
+func (UnimplementedInvoiceServiceServer) CreateInvoice(context.Context, *CreateInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetInvoice(context.Context, *GetInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListInvoices(*ListInvoicesRequest, grpc.ServerStreamingServer[Invoice]) error {
+	return status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+}
+func (UnimplementedInvoiceServiceServer) AdjustInvoice(context.Context, *AdjustInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) CollectInvoice(context.Context, *CollectInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) DeleteInvoice(context.Context, *DeleteInvoiceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) RestoreInvoice(context.Context, *RestoreInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) mustEmbedUnimplementedInvoiceServiceServer() {}
+func (UnimplementedInvoiceServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeInvoiceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InvoiceServiceServer will
+// result in compilation errors.
+type UnsafeInvoiceServiceServer interface {
+	mustEmbedUnimplementedInvoiceServiceServer()
+}
+
+func RegisterInvoiceServiceServer(s grpc.ServiceRegistrar, srv InvoiceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInvoiceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InvoiceService_ServiceDesc, srv)
+}
+
+func _InvoiceService_CreateInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).CreateInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_CreateInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).CreateInvoice(ctx, req.(*CreateInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetInvoice(ctx, req.(*GetInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListInvoicesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).ListInvoices(m, &grpc.GenericServerStream[ListInvoicesRequest, Invoice]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InvoiceService_ListInvoicesServer = grpc.ServerStreamingServer[Invoice]
+
+func _InvoiceService_AdjustInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).AdjustInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_AdjustInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).AdjustInvoice(ctx, req.(*AdjustInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_CollectInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).CollectInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_CollectInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).CollectInvoice(ctx, req.(*CollectInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_DeleteInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).DeleteInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_DeleteInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).DeleteInvoice(ctx, req.(*DeleteInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_RestoreInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).RestoreInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_RestoreInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).RestoreInvoice(ctx, req.(*RestoreInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InvoiceService_ServiceDesc is the grpc.ServiceDesc for InvoiceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InvoiceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "oms.v1.InvoiceService",
+	HandlerType: (*InvoiceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateInvoice",
+			Handler:    _InvoiceService_CreateInvoice_Handler,
+		},
+		{
+			MethodName: "GetInvoice",
+			Handler:    _InvoiceService_GetInvoice_Handler,
+		},
+		{
+			MethodName: "AdjustInvoice",
+			Handler:    _InvoiceService_AdjustInvoice_Handler,
+		},
+		{
+			MethodName: "CollectInvoice",
+			Handler:    _InvoiceService_CollectInvoice_Handler,
+		},
+		{
+			MethodName: "DeleteInvoice",
+			Handler:    _InvoiceService_DeleteInvoice_Handler,
+		},
+		{
+			MethodName: "RestoreInvoice",
+			Handler:    _InvoiceService_RestoreInvoice_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListInvoices",
+			Handler:       _InvoiceService_ListInvoices_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "oms.proto",
+}
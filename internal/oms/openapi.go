@@ -0,0 +1,210 @@
+package oms
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// openAPISpec documents the /v1 routes of the campaigns, line items and invoices
+// controllers. NewServer refuses to start when it and the routes drift apart.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// openAPIDocsPage renders the document with openAPIDocsScript, both are served from
+// here so the page does not depend on a CDN.
+//
+//go:embed openapi.html
+var openAPIDocsPage []byte
+
+//go:embed openapi.js
+var openAPIDocsScript []byte
+
+// loadOpenAPI parses and validates the embedded OpenAPI document.
+func loadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse the OpenAPI document")
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "the OpenAPI document is invalid")
+	}
+
+	// Merge patches are plain JSON to the validator.
+	openapi3filter.RegisterBodyDecoder(mergePatchContentType, openapi3filter.RegisteredBodyDecoder(gin.MIMEJSON))
+
+	return doc, nil
+}
+
+// registerOpenAPIRoutes serves the document and a page that renders it.
+func registerOpenAPIRoutes(engine *gin.Engine) {
+	engine.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, gin.MIMEJSON, openAPISpec)
+	})
+	engine.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, gin.MIMEHTML, openAPIDocsPage)
+	})
+	engine.GET("/docs/openapi.js", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/javascript; charset=utf-8", openAPIDocsScript)
+	})
+}
+
+// openAPIPath turns a gin route into an OpenAPI path, /campaigns/:id becomes
+// /campaigns/{id}.
+func openAPIPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// validateRequests checks the parameters and body of every request to a documented
+// route before it reaches the handler, the routes of other controllers pass through.
+// The documented paths are relative to basePath, the prefix of the route group it
+// is installed on before the routes are registered.
+func validateRequests(doc *openapi3.T, basePath string) gin.HandlerFunc {
+	routes := map[string]*routers.Route{}
+
+	for path, item := range doc.Paths.Map() {
+		for method, operation := range item.Operations() {
+			routes[method+" "+path] = &routers.Route{
+				Spec:      doc,
+				Path:      path,
+				PathItem:  item,
+				Method:    method,
+				Operation: operation,
+			}
+		}
+	}
+
+	options := &openapi3filter.Options{SkipSettingDefaults: true}
+
+	return func(c *gin.Context) {
+		route, ok := routes[c.Request.Method+" "+openAPIPath(strings.TrimPrefix(c.FullPath(), basePath))]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if body := route.Operation.RequestBody; body != nil && c.Request.ContentLength != 0 {
+			if body.Value.Content.Get(c.ContentType()) == nil {
+				respondProblem(c, http.StatusUnsupportedMediaType, models.ProblemUnsupportedMediaType,
+					"content type must be "+strings.Join(contentTypes(body.Value.Content), " or "))
+
+				return
+			}
+		}
+
+		pathParams := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			pathParams[param.Key] = param.Value
+		}
+
+		err := openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			writeProblem(c, openAPIProblem(err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func contentTypes(content openapi3.Content) []string {
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// openAPIProblem names the parameter or body field that failed and why, without
+// the schema dump of the validator. A body that breaks its schema fails validation
+// like the checks of the handlers do.
+func openAPIProblem(err error) *models.Problem {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return newProblem(http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return newProblem(http.StatusBadRequest, models.ProblemInvalidRequest, reqErr.Error())
+	}
+
+	if reqErr.Parameter != nil {
+		return newProblem(http.StatusBadRequest, models.ProblemInvalidRequest, "invalid "+reqErr.Parameter.In+
+			" parameter "+strconv.Quote(reqErr.Parameter.Name)+": "+schemaErr.Reason)
+	}
+
+	field := strings.Join(schemaErr.JSONPointer(), ".")
+	if field == "" {
+		field = "Body"
+	}
+
+	problem := newProblem(http.StatusBadRequest, models.ProblemValidationFailed, "validation failed")
+	problem.Errors = []models.FieldError{{Field: field, Message: schemaErr.Reason}}
+
+	return problem
+}
+
+// checkOpenAPIRoutes fails when the routes under basePath and the document drift
+// apart: every route must be documented and every documented operation must be
+// served.
+func checkOpenAPIRoutes(doc *openapi3.T, basePath string, routes gin.RoutesInfo) error {
+	served := map[string]bool{}
+
+	var drift []string
+
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, basePath+"/") {
+			continue
+		}
+
+		path := openAPIPath(strings.TrimPrefix(route.Path, basePath))
+		served[route.Method+" "+path] = true
+
+		if item := doc.Paths.Value(path); item == nil || item.GetOperation(route.Method) == nil {
+			drift = append(drift, route.Method+" "+path+" is not documented")
+		}
+	}
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !served[method+" "+path] {
+				drift = append(drift, method+" "+path+" is documented but not served")
+			}
+		}
+	}
+
+	if len(drift) > 0 {
+		sort.Strings(drift)
+		return errors.Errorf("the OpenAPI document does not match the routes: %s", strings.Join(drift, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+// Renders /openapi.json for the /docs page. It is served by the server itself so
+// the page works without reaching any other host.
+(function () {
+  "use strict";
+
+  function el(tag, className, text) {
+    var node = document.createElement(tag);
+    if (className) {
+      node.className = className;
+    }
+    if (text !== undefined && text !== null) {
+      node.textContent = text;
+    }
+    return node;
+  }
+
+  function refName(ref) {
+    return ref.substring(ref.lastIndexOf("/") + 1);
+  }
+
+  function resolve(doc, value) {
+    while (value && value.$ref) {
+      var parts = value.$ref.replace(/^#\//, "").split("/");
+      value = parts.reduce(function (node, part) {
+        return node && node[part];
+      }, doc);
+    }
+    return value || {};
+  }
+
+  // typeOf describes a schema in one line, named schemas link to their section.
+  function typeOf(schema) {
+    if (!schema) {
+      return el("span", "type", "any");
+    }
+    if (schema.$ref) {
+      var link = el("a", "type", refName(schema.$ref));
+      link.href = "#schema-" + refName(schema.$ref);
+      return link;
+    }
+    if (schema.type === "array") {
+      var span = el("span", "type");
+      span.appendChild(document.createTextNode("array of "));
+      span.appendChild(typeOf(schema.items));
+      return span;
+    }
+    var text = schema.type || "object";
+    if (schema.format) {
+      text += " (" + schema.format + ")";
+    }
+    if (schema.enum) {
+      text += ": " + schema.enum.join(", ");
+    }
+    if (schema.nullable) {
+      text += ", nullable";
+    }
+    return el("span", "type", text);
+  }
+
+  function table(headers, rows) {
+    var t = el("table");
+    var head = el("tr");
+    headers.forEach(function (header) {
+      head.appendChild(el("th", null, header));
+    });
+    t.appendChild(head);
+    rows.forEach(function (cells) {
+      var row = el("tr");
+      cells.forEach(function (cell) {
+        var td = el("td");
+        if (cell instanceof Node) {
+          td.appendChild(cell);
+        } else {
+          td.textContent = cell === undefined ? "" : cell;
+        }
+        row.appendChild(td);
+      });
+      t.appendChild(row);
+    });
+    return t;
+  }
+
+  function renderContent(content) {
+    var box = el("div");
+    Object.keys(content || {}).forEach(function (contentType) {
+      var line = el("p");
+      line.appendChild(el("code", null, contentType));
+      line.appendChild(document.createTextNode(" "));
+      line.appendChild(typeOf(content[contentType].schema));
+      box.appendChild(line);
+    });
+    return box;
+  }
+
+  function renderOperation(doc, path, method, operation) {
+    var section = el("section", "operation");
+    section.id = operation.operationId || method + path;
+
+    var title = el("h3");
+    title.appendChild(el("span", "method " + method, method.toUpperCase()));
+    title.appendChild(el("code", null, path));
+    section.appendChild(title);
+
+    if (operation.summary) {
+      section.appendChild(el("p", "summary", operation.summary));
+    }
+    if (operation.description) {
+      section.appendChild(el("p", null, operation.description));
+    }
+
+    var parameters = (operation.parameters || []).map(function (parameter) {
+      return resolve(doc, parameter);
+    });
+    if (parameters.length > 0) {
+      section.appendChild(el("h4", null, "Parameters"));
+      section.appendChild(table(["Name", "In", "Type", "Description"], parameters.map(function (p) {
+        return [p.name + (p.required ? " *" : ""), p.in, typeOf(p.schema), p.description];
+      })));
+    }
+
+    if (operation.requestBody) {
+      var body = resolve(doc, operation.requestBody);
+      section.appendChild(el("h4", null, "Request body" + (body.required ? " *" : "")));
+      section.appendChild(renderContent(body.content));
+    }
+
+    section.appendChild(el("h4", null, "Responses"));
+    section.appendChild(table(["Status", "Description", "Body"], Object.keys(operation.responses || {}).map(
+      function (status) {
+        var response = resolve(doc, operation.responses[status]);
+        return [status, response.description, renderContent(response.content)];
+      })));
+
+    return section;
+  }
+
+  function renderSchema(name, schema) {
+    var section = el("section", "schema");
+    section.id = "schema-" + name;
+    section.appendChild(el("h3", null, name));
+    if (schema.description) {
+      section.appendChild(el("p", null, schema.description));
+    }
+
+    var required = schema.required || [];
+    var properties = schema.properties || {};
+    var names = Object.keys(properties);
+    if (names.length > 0) {
+      section.appendChild(table(["Field", "Type", "Description"], names.map(function (field) {
+        var property = properties[field];
+        return [field + (required.indexOf(field) >= 0 ? " *" : ""), typeOf(property), property.description];
+      })));
+    } else {
+      section.appendChild(typeOf(schema));
+    }
+    return section;
+  }
+
+  function render(doc) {
+    var root = document.getElementById("docs");
+    var nav = el("nav");
+    var main = el("main");
+
+    main.appendChild(el("h1", null, doc.info.title + " " + doc.info.version));
+    if (doc.info.description) {
+      main.appendChild(el("p", null, doc.info.description));
+    }
+    (doc.servers || []).forEach(function (server) {
+      main.appendChild(el("p", null, "Base URL: " + server.url));
+    });
+
+    var byTag = {};
+    var tags = [];
+    Object.keys(doc.paths).forEach(function (path) {
+      Object.keys(doc.paths[path]).forEach(function (method) {
+        var operation = doc.paths[path][method];
+        if (typeof operation !== "object" || !operation.responses) {
+          return;
+        }
+        var tag = (operation.tags && operation.tags[0]) || "other";
+        if (!byTag[tag]) {
+          byTag[tag] = [];
+          tags.push(tag);
+        }
+        byTag[tag].push(renderOperation(doc, path, method, operation));
+      });
+    });
+
+    tags.forEach(function (tag) {
+      var heading = el("h2", null, tag);
+      heading.id = "tag-" + tag;
+      main.appendChild(heading);
+      byTag[tag].forEach(function (section) {
+        main.appendChild(section);
+      });
+
+      var link = el("a", null, tag);
+      link.href = "#tag-" + tag;
+      nav.appendChild(link);
+    });
+
+    var schemas = (doc.components && doc.components.schemas) || {};
+    var schemasHeading = el("h2", null, "Schemas");
+    schemasHeading.id = "schemas";
+    main.appendChild(schemasHeading);
+    Object.keys(schemas).forEach(function (name) {
+      main.appendChild(renderSchema(name, schemas[name]));
+    });
+
+    var schemasLink = el("a", null, "Schemas");
+    schemasLink.href = "#schemas";
+    nav.appendChild(schemasLink);
+
+    root.textContent = "";
+    root.appendChild(nav);
+    root.appendChild(main);
+  }
+
+  fetch("/openapi.json")
+    .then(function (response) {
+      if (!response.ok) {
+        throw new Error("the server answered " + response.status);
+      }
+      return response.json();
+    })
+    .then(render)
+    .catch(function (err) {
+      document.getElementById("docs").textContent = "Cannot load /openapi.json: " + err.message;
+    });
+})();
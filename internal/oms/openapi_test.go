@@ -0,0 +1,125 @@
+package oms
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// newOpenAPITestEngine registers every controller on /v1 like NewServer does.
+// The requests of the tests never get past the validation, so there is no database.
+func newOpenAPITestEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	doc, err := loadOpenAPI()
+	require.NoError(t, err)
+
+	logger := newTestLogger()
+	tokens := newTestTokens()
+
+	r := gin.New()
+	r.Use(requestContext(logger))
+
+	v1Routes := r.Group(v1.BasePath, validateRequests(doc, v1.BasePath))
+	newCampaignsController(logger, nil, tokens).register(v1Routes)
+	newCampaignLineItemsController(logger, nil, tokens).register(v1Routes)
+	newInvoicesController(logger, nil, tokens).register(v1Routes)
+	newSalesController(logger, nil).register(v1Routes)
+	newReportsController(logger, nil).register(v1Routes)
+	newDeliveryController(logger, nil).register(v1Routes)
+	newWebhooksController(logger, nil).register(v1Routes)
+
+	require.NoError(t, checkOpenAPIRoutes(doc, v1.BasePath, r.Routes()))
+
+	return r
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestTokens() *tokenCodec {
+	return &tokenCodec{keys: [][]byte{[]byte(strings.Repeat("k", minPaginationKeyLength))}, ttl: time.Hour,
+		now: time.Now}
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	newOpenAPITestEngine(t)
+}
+
+func TestCheckOpenAPIRoutesFindsDrift(t *testing.T) {
+	doc, err := loadOpenAPI()
+	require.NoError(t, err)
+
+	routes := gin.RoutesInfo{{Method: http.MethodGet, Path: v1.BasePath + "/undocumented"}}
+
+	err = checkOpenAPIRoutes(doc, v1.BasePath, routes)
+	require.ErrorContains(t, err, "GET /undocumented is not documented")
+	require.ErrorContains(t, err, "POST /campaigns is documented but not served")
+}
+
+func TestValidateRequests(t *testing.T) {
+	r := newOpenAPITestEngine(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+		wantErrors  []*v1.FieldError
+	}{
+		{
+			name:        "field of the wrong type",
+			contentType: gin.MIMEJSON,
+			body:        `{"name": 5}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    models.ProblemValidationFailed,
+			wantErrors:  []*v1.FieldError{{Field: "name", Message: `value must be a string`}},
+		},
+		{
+			name:        "body that is not an object",
+			contentType: gin.MIMEJSON,
+			body:        `[]`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    models.ProblemValidationFailed,
+			wantErrors:  []*v1.FieldError{{Field: "body", Message: `value must be an object`}},
+		},
+		{
+			name:        "content type that is not documented",
+			contentType: gin.MIMEXML,
+			body:        `<campaign/>`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantCode:    models.ProblemUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, v1.BasePath+"/campaigns", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Equal(t, models.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem v1.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.Equal(t, tt.wantCode, problem.Code)
+			require.Equal(t, tt.wantErrors, problem.Errors)
+		})
+	}
+}
@@ -1,45 +1,174 @@
 package oms
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
-const TokenQueryParamName = "$token"
+const (
+	TokenQueryParamName = "$token"
 
-// PaginationToken holds information for pagination.
+	maxListLimit = 500
+
+	defaultPaginationTokenTTL = 24 * time.Hour
+	minPaginationKeyLength    = 32
+)
+
+var (
+	errInvalidToken = errors.New("the pagination token is invalid or was tampered with, " +
+		"start again from the first page")
+	errTokenExpired    = errors.New("the pagination token has expired, start again from the first page")
+	errTokenOtherQuery = errors.New("the pagination token belongs to a different list, " +
+		"send it to the same endpoint with the same includeDeleted")
+)
+
+// PaginationToken holds information for pagination. The filter and order of the
+// first page travel with it, After holds the $orderby values of the row the next
+// page starts after, or before when Backward is set.
 type PaginationToken struct {
-	StartID int `json:"start_id"`
-	Size    int `json:"size"`
+	StartID  int           `json:"start_id"`
+	Size     int           `json:"size"`
+	Filter   string        `json:"filter,omitempty"`
+	OrderBy  string        `json:"orderby,omitempty"`
+	After    []interface{} `json:"after,omitempty"`
+	Backward bool          `json:"backward,omitempty"`
+	// Query is the fingerprint of the list the token was issued for.
+	Query string `json:"query"`
+	// ExpiresAt is a unix time in seconds.
+	ExpiresAt int64 `json:"exp"`
+}
+
+// tokenCodec signs pagination tokens with HMAC-SHA256 so callers cannot change
+// the position, size or query of a token. The first key signs, every key verifies,
+// so a new key can be put in front and the old one dropped once its tokens expired.
+type tokenCodec struct {
+	keys [][]byte
+	ttl  time.Duration
+	now  func() time.Time
 }
 
-// EncodeToken encodes the PaginationToken to base64.
-func EncodeToken(token PaginationToken) string {
+// newTokenCodecFromEnv reads the comma separated keys of OMS_PAGINATION_KEYS and the
+// lifetime of a token from OMS_PAGINATION_TOKEN_TTL. Without keys a random key is
+// used, tokens then stop working on restart and across instances.
+func newTokenCodecFromEnv(logger *slog.Logger) (*tokenCodec, error) {
+	ttl, err := durationFromEnv("OMS_PAGINATION_TOKEN_TTL", defaultPaginationTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys [][]byte
+
+	for _, key := range strings.Split(os.Getenv("OMS_PAGINATION_KEYS"), ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		if len(key) < minPaginationKeyLength {
+			return nil, errors.Errorf("OMS_PAGINATION_KEYS must hold keys of at least %d characters",
+				minPaginationKeyLength)
+		}
+
+		keys = append(keys, []byte(key))
+	}
+
+	if len(keys) == 0 {
+		logger.Warn("OMS_PAGINATION_KEYS is not set, pagination tokens only work on this instance until it restarts")
+
+		key := make([]byte, minPaginationKeyLength)
+		if _, err := rand.Read(key); err != nil {
+			return nil, errors.Wrap(err, "cannot generate a pagination key")
+		}
+
+		keys = append(keys, key)
+	}
+
+	return &tokenCodec{keys: keys, ttl: ttl, now: time.Now}, nil
+}
+
+// encode signs the token with the current key and sets when it expires.
+func (t *tokenCodec) encode(token PaginationToken) string {
+	token.ExpiresAt = t.now().Add(t.ttl).Unix()
+
 	data, _ := json.Marshal(token)
-	return base64.StdEncoding.EncodeToString(data)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(t.keys[0], payload))
 }
 
-// DecodeToken decodes the base64-encoded token to PaginationToken.
-func DecodeToken(encodedToken string) (PaginationToken, error) {
-	data, err := base64.StdEncoding.DecodeString(encodedToken)
+// decode checks the signature against every key and the expiry of the token.
+func (t *tokenCodec) decode(encodedToken string) (PaginationToken, error) {
+	payload, signature, ok := strings.Cut(encodedToken, ".")
+	if !ok {
+		return PaginationToken{}, errInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return PaginationToken{}, errInvalidToken
+	}
+
+	if !t.verify(payload, mac) {
+		return PaginationToken{}, errInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
 	if err != nil {
-		return PaginationToken{}, err
+		return PaginationToken{}, errInvalidToken
 	}
 
 	var token PaginationToken
-	err = json.Unmarshal(data, &token)
+	if err := json.Unmarshal(data, &token); err != nil {
+		return PaginationToken{}, errInvalidToken
+	}
+
+	if t.now().Unix() > token.ExpiresAt {
+		return PaginationToken{}, errTokenExpired
+	}
+
+	if token.Size < 1 || token.Size > maxListLimit {
+		return PaginationToken{}, errInvalidToken
+	}
+
+	return token, nil
+}
+
+func (t *tokenCodec) verify(payload string, mac []byte) bool {
+	for _, key := range t.keys {
+		if hmac.Equal(mac, sign(key, payload)) {
+			return true
+		}
+	}
 
-	return token, err
+	return false
 }
 
-type QueryPagingInfo struct {
-	Limit      int
-	StartingID *int
+func sign(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+
+	return mac.Sum(nil)
+}
+
+// queryFingerprint identifies a list by its path and options, a token is only
+// accepted by the list it was issued for.
+func queryFingerprint(path string, includeDeleted bool, filter, orderBy string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{path, strconv.FormatBool(includeDeleted), filter, orderBy}, "\n")))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
 }
 
 func extractLimit(c *gin.Context) (*int32, bool) {
@@ -47,12 +176,13 @@ func extractLimit(c *gin.Context) (*int32, bool) {
 	if limitStr != "" {
 		limitInt64, err := strconv.ParseInt(limitStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
 			return nil, true
 		}
 
-		if limitInt64 > 500 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "limit cannot be higher than 500"})
+		if limitInt64 > maxListLimit {
+			respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest,
+				"limit cannot be higher than "+strconv.Itoa(maxListLimit))
 			return nil, true
 		}
 
@@ -64,23 +194,17 @@ func extractLimit(c *gin.Context) (*int32, bool) {
 	return nil, false
 }
 
-func extractTokenFromQuery(c *gin.Context) (paging *PaginationToken, hasError bool) {
+func extractTokenFromQuery(c *gin.Context, tokens *tokenCodec) (paging *PaginationToken, hasError bool) {
 	token := c.Query(TokenQueryParamName)
-	params := db.ListCampaignLineItemsParams{
-		Limit: 100,
-	}
 
 	if token != "" {
-		pagingToken, err := DecodeToken(token)
+		pagingToken, err := tokens.decode(token)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return nil, true
 		}
 
-		params.ID = int32(pagingToken.StartID)
-		params.Limit = int32(pagingToken.Size)
-
-		return &PaginationToken{StartID: pagingToken.StartID, Size: pagingToken.Size}, false
+		return &pagingToken, false
 	}
 
 	return nil, false
@@ -0,0 +1,109 @@
+package oms
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+var (
+	errInvalidIfMatch  = errors.New("the If-Match header must be * or a list of entity tags from the ETag header")
+	errVersionRequired = newAPIError(http.StatusPreconditionRequired, models.ProblemPreconditionRequired,
+		"version is required, send the version being changed")
+)
+
+// versionMismatchError is returned when a resource changed after the version the
+// request was based on.
+type versionMismatchError struct {
+	current int32
+}
+
+func (e *versionMismatchError) Error() string {
+	return fmt.Sprintf("the resource was changed by someone else, its current version is %d", e.current)
+}
+
+// ifMatch holds the versions named by an If-Match header, or by the version field
+// of a gRPC request or batch operation.
+type ifMatch struct {
+	any      bool
+	versions []int
+}
+
+func parseIfMatch(header string) (*ifMatch, error) {
+	if strings.TrimSpace(header) == "*" {
+		return &ifMatch{any: true}, nil
+	}
+
+	match := &ifMatch{}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		// Weak tags are valid in the header but never match, If-Match uses strong comparison.
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		version, ok := models.ParseETag(tag)
+		if !ok {
+			return nil, errInvalidIfMatch
+		}
+
+		match.versions = append(match.versions, version)
+	}
+
+	return match, nil
+}
+
+// check returns a versionMismatchError when the current version is not one the request named.
+func (m *ifMatch) check(version int32) error {
+	if m == nil || m.any {
+		return nil
+	}
+
+	for _, v := range m.versions {
+		if v == int(version) {
+			return nil
+		}
+	}
+
+	return &versionMismatchError{current: version}
+}
+
+// requireIfMatch reads the If-Match header that updates and deletes must send. It
+// writes the error response and reports when the header is missing or malformed.
+func requireIfMatch(c *gin.Context) (*ifMatch, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		respondProblem(c, http.StatusPreconditionRequired, models.ProblemPreconditionRequired,
+			"If-Match is required, send the ETag of the version being changed")
+
+		return nil, true
+	}
+
+	match, err := parseIfMatch(header)
+	if err != nil {
+		respondError(c, err)
+		return nil, true
+	}
+
+	return match, false
+}
+
+// versionMatch is the precondition of a gRPC call or batch operation, which names
+// the version it is based on in the request instead of an If-Match header.
+func versionMatch(version int32) (*ifMatch, error) {
+	if version == 0 {
+		return nil, errVersionRequired
+	}
+
+	return &ifMatch{versions: []int{int(version)}}, nil
+}
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", models.ETag(version))
+}
@@ -0,0 +1,246 @@
+package oms
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/chrisrob11/oms/internal/oms/query"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const (
+	requestIDHeader = "X-Request-Id"
+	requestIDKey    = "requestID"
+
+	maxRequestIDLength = 128
+)
+
+// problemTitles summarise every problem code, the title of a code never changes.
+var problemTitles = map[string]string{
+	models.ProblemInvalidRequest:       "The request is malformed",
+	models.ProblemValidationFailed:     "Validation failed",
+	models.ProblemInvalidQuery:         "The list query is invalid",
+	models.ProblemInvalidToken:         "The pagination token is invalid",
+	models.ProblemTokenExpired:         "The pagination token has expired",
+	models.ProblemNotFound:             "Not found",
+	models.ProblemConflict:             "The request conflicts with the stored records",
+	models.ProblemHasDependents:        "The campaign has dependent records",
+	models.ProblemLineItemLocked:       "The line item is locked",
+	models.ProblemLineItemInvoiced:     "The line item is invoiced",
+	models.ProblemParentDeleted:        "The parent record is deleted",
+	models.ProblemInvalidState:         "The record is in the wrong state",
+	models.ProblemBatchFailed:          "The batch failed",
+	models.ProblemPreconditionRequired: "If-Match is required",
+	models.ProblemVersionMismatch:      "The version does not match",
+	models.ProblemUnsupportedMediaType: "Unsupported content type",
+	models.ProblemInternal:             "Internal error",
+}
+
+// sentinelProblems maps the sentinel errors of the domain to how they are answered,
+// the first match wins.
+var sentinelProblems = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{sql.ErrNoRows, http.StatusNotFound, models.ProblemNotFound},
+	{query.ErrInvalidQuery, http.StatusBadRequest, models.ProblemInvalidQuery},
+	{errInvalidToken, http.StatusBadRequest, models.ProblemInvalidToken},
+	{errTokenOtherQuery, http.StatusBadRequest, models.ProblemInvalidToken},
+	{errTokenQueryMismatch, http.StatusBadRequest, models.ProblemInvalidToken},
+	{errTokenExpired, http.StatusBadRequest, models.ProblemTokenExpired},
+	{errInvalidIfMatch, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errInvalidDeleteMode, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errPatchNotObject, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errInvalidPeriod, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errInvalidThreshold, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errInvalidGroupBy, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errInvalidSort, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errInvalidStatus, http.StatusBadRequest, models.ProblemInvalidRequest},
+	{errBatchFailed, http.StatusBadRequest, models.ProblemBatchFailed},
+	{errLineItemLocked, http.StatusConflict, models.ProblemLineItemLocked},
+	{errLineItemInvoiced, http.StatusConflict, models.ProblemLineItemInvoiced},
+	{errParentDeleted, http.StatusConflict, models.ProblemParentDeleted},
+}
+
+// apiError is an error answered with its own status, code and detail.
+type apiError struct {
+	status int
+	code   string
+	detail string
+}
+
+func newAPIError(status int, code, detail string) *apiError {
+	return &apiError{status: status, code: code, detail: detail}
+}
+
+func (e *apiError) Error() string {
+	return e.detail
+}
+
+// problemFor maps an error to the problem it is answered with. Errors it does not
+// know are internal, their message stays in the log and out of the response.
+func problemFor(err error) *models.Problem {
+	var (
+		apiErr        *apiError
+		vErr          *validationError
+		mismatchErr   *versionMismatchError
+		dependentsErr *campaignDependentsError
+		pqErr         *pq.Error
+	)
+
+	switch {
+	case errors.As(err, &apiErr):
+		return newProblem(apiErr.status, apiErr.code, apiErr.detail)
+	case errors.As(err, &vErr):
+		problem := newProblem(http.StatusBadRequest, models.ProblemValidationFailed, "validation failed")
+		problem.Errors = vErr.fields
+
+		return problem
+	case errors.As(err, &mismatchErr):
+		return newProblem(http.StatusPreconditionFailed, models.ProblemVersionMismatch, mismatchErr.Error())
+	case errors.As(err, &dependentsErr):
+		problem := newProblem(http.StatusConflict, models.ProblemHasDependents, dependentsErr.Error())
+		problem.Dependents = dependentsErr.dependents
+
+		return problem
+	case errors.As(err, &pqErr):
+		return problemForPQ(pqErr)
+	}
+
+	for _, sentinel := range sentinelProblems {
+		if errors.Is(err, sentinel.err) {
+			detail := err.Error()
+			if sentinel.err == sql.ErrNoRows {
+				detail = "the record does not exist"
+			}
+
+			return newProblem(sentinel.status, sentinel.code, detail)
+		}
+	}
+
+	return newProblem(http.StatusInternalServerError, models.ProblemInternal, "an unexpected error occurred")
+}
+
+// problemForPQ answers the constraint violations of the database without the
+// message of the driver, anything else is internal.
+func problemForPQ(err *pq.Error) *models.Problem {
+	switch {
+	case err.Code == "23505":
+		return newProblem(http.StatusConflict, models.ProblemConflict, "a record with the same values already exists")
+	case err.Code == "23503":
+		return newProblem(http.StatusConflict, models.ProblemConflict,
+			"the record references a record that does not exist, or is still referenced")
+	case err.Code == "23514" || err.Code.Class() == "22":
+		return newProblem(http.StatusBadRequest, models.ProblemInvalidRequest,
+			"a value is out of range or not allowed")
+	}
+
+	return newProblem(http.StatusInternalServerError, models.ProblemInternal, "an unexpected error occurred")
+}
+
+func newProblem(status int, code, detail string) *models.Problem {
+	return &models.Problem{
+		Type:   models.ProblemType(code),
+		Title:  problemTitles[code],
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// respondError answers the request with the problem for err. Internal errors are
+// recorded on the context and logged with the request id.
+func respondError(c *gin.Context, err error) {
+	problem := problemFor(err)
+
+	var mismatchErr *versionMismatchError
+	if errors.As(err, &mismatchErr) {
+		setETag(c, int(mismatchErr.current))
+	}
+
+	if problem.Status >= http.StatusInternalServerError {
+		_ = c.Error(err)
+	}
+
+	writeProblem(c, problem)
+}
+
+// respondProblem answers the request with a problem of the status and code.
+func respondProblem(c *gin.Context, status int, code, detail string) {
+	writeProblem(c, newProblem(status, code, detail))
+}
+
+// writeProblem aborts the request with the problem in the wire format of its
+// version, so a failing middleware stops the handlers after it.
+func writeProblem(c *gin.Context, problem *models.Problem) {
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.GetString(requestIDKey)
+
+	c.Header("Content-Type", models.ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, encodeBody(c, problem))
+}
+
+// requestContext gives every request an id, the X-Request-Id of the caller when it
+// sent a usable one. The id is echoed in the response and in its problems, and
+// the errors of the request are logged with it.
+func requestContext(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+
+		c.Next()
+
+		for _, err := range c.Errors {
+			logger.Error("request failed",
+				slog.String("error", err.Error()),
+				slog.String("requestId", id),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path))
+		}
+	}
+}
+
+// recoverWithProblem answers a panic of a handler with an internal error.
+func recoverWithProblem(c *gin.Context, recovered interface{}) {
+	respondError(c, errors.Errorf("panic: %v", recovered))
+}
+
+// noRoute answers requests that match no route.
+func noRoute(c *gin.Context) {
+	respondProblem(c, http.StatusNotFound, models.ProblemNotFound,
+		"no route matches "+c.Request.Method+" "+c.Request.URL.Path)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlnum && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,422 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maxFilterLength = 2000
+	maxFilterDepth  = 32
+)
+
+// filterNode is a parsed $filter expression.
+type filterNode interface {
+	sql(a *args) string
+}
+
+type logicalNode struct {
+	op          string
+	left, right filterNode
+}
+
+func (n *logicalNode) sql(a *args) string {
+	return "(" + n.left.sql(a) + " " + n.op + " " + n.right.sql(a) + ")"
+}
+
+type comparisonNode struct {
+	field Field
+	op    string
+	value interface{}
+}
+
+var comparisonOperators = map[string]string{
+	"eq": "=",
+	"ne": "<>",
+	"gt": ">",
+	"ge": ">=",
+	"lt": "<",
+	"le": "<=",
+}
+
+func (n *comparisonNode) sql(a *args) string {
+	if n.value == nil {
+		if n.op == "eq" {
+			return n.field.Column + " IS NULL"
+		}
+
+		return n.field.Column + " IS NOT NULL"
+	}
+
+	return n.field.Column + " " + comparisonOperators[n.op] + " " + a.add(n.value)
+}
+
+type containsNode struct {
+	field Field
+	value string
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (n *containsNode) sql(a *args) string {
+	return n.field.Column + ` ILIKE ('%' || ` + a.add(likeEscaper.Replace(n.value)) + ` || '%') ESCAPE '\'`
+}
+
+// parseFilter parses an OData style filter such as
+// "contains(Name,'spring') and StartedAt gt 2024-01-01T00:00:00Z".
+func parseFilter(filter string, schema *Schema) (filterNode, error) {
+	if len(filter) > maxFilterLength {
+		return nil, errors.Wrapf(ErrInvalidQuery, "$filter is longer than %d characters", maxFilterLength)
+	}
+
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, schema: schema}
+
+	node, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	stringToken
+	openToken
+	closeToken
+	commaToken
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+}
+
+func tokenize(filter string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(filter)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: openToken, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: closeToken, text: ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: commaToken, text: ","})
+			i++
+		case r == '\'':
+			value, next, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: stringToken, text: string(runes[i:next]), value: value})
+			i = next
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),'", runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: wordToken, text: string(runes[start:i]), value: string(runes[start:i])})
+		}
+	}
+
+	return tokens, nil
+}
+
+// readString reads a quoted string starting at runes[start], a doubled quote is a literal quote.
+func readString(runes []rune, start int) (string, int, error) {
+	var value strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != '\'' {
+			value.WriteRune(runes[i])
+			continue
+		}
+
+		if i+1 < len(runes) && runes[i+1] == '\'' {
+			value.WriteRune('\'')
+			i++
+
+			continue
+		}
+
+		return value.String(), i + 1, nil
+	}
+
+	return "", 0, errors.Wrap(ErrInvalidQuery, "unterminated string in $filter")
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+	schema *Schema
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peekWord(word string) bool {
+	return !p.done() && p.tokens[p.pos].kind == wordToken && strings.EqualFold(p.tokens[p.pos].text, word)
+}
+
+func (p *filterParser) next() (token, error) {
+	if p.done() {
+		return token{}, errors.Wrap(ErrInvalidQuery, "$filter ended unexpectedly")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+
+	return t, nil
+}
+
+func (p *filterParser) expect(kind tokenKind, text string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	if t.kind != kind {
+		return errors.Wrapf(ErrInvalidQuery, "expected %q in $filter but found %q", text, t.text)
+	}
+
+	return nil
+}
+
+func (p *filterParser) unexpected() error {
+	return errors.Wrapf(ErrInvalidQuery, "unexpected %q in $filter", p.tokens[p.pos].text)
+}
+
+func (p *filterParser) parseOr(depth int) (filterNode, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekWord("or") {
+		p.pos++
+
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalNode{op: "OR", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd(depth int) (filterNode, error) {
+	left, err := p.parseTerm(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekWord("and") {
+		p.pos++
+
+		right, err := p.parseTerm(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalNode{op: "AND", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseTerm(depth int) (filterNode, error) {
+	if depth > maxFilterDepth {
+		return nil, errors.Wrap(ErrInvalidQuery, "$filter is nested too deeply")
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t.kind == openToken:
+		node, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return node, p.expect(closeToken, ")")
+	case t.kind == wordToken && strings.EqualFold(t.text, "contains"):
+		return p.parseContains()
+	case t.kind == wordToken:
+		return p.parseComparison(t.text)
+	default:
+		return nil, errors.Wrapf(ErrInvalidQuery, "expected a field in $filter but found %q", t.text)
+	}
+}
+
+func (p *filterParser) parseContains() (filterNode, error) {
+	if err := p.expect(openToken, "("); err != nil {
+		return nil, err
+	}
+
+	name, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := p.schema.field(name.text)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.Type != String {
+		return nil, errors.Wrapf(ErrInvalidQuery, "contains needs a string field, the type of %s is %s",
+			field.Name, field.Type)
+	}
+
+	if err := p.expect(commaToken, ","); err != nil {
+		return nil, err
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if value.kind != stringToken {
+		return nil, errors.Wrapf(ErrInvalidQuery, "contains needs a quoted string but found %q", value.text)
+	}
+
+	return &containsNode{field: field, value: value.value}, p.expect(closeToken, ")")
+}
+
+func (p *filterParser) parseComparison(name string) (filterNode, error) {
+	field, err := p.schema.field(name)
+	if err != nil {
+		return nil, err
+	}
+
+	opToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	op := strings.ToLower(opToken.text)
+	if _, ok := comparisonOperators[op]; !ok || opToken.kind != wordToken {
+		return nil, errors.Wrapf(ErrInvalidQuery, "unknown operator %q, expected eq, ne, gt, ge, lt or le", opToken.text)
+	}
+
+	valueToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if valueToken.kind == wordToken && valueToken.text == "null" {
+		if !field.Nullable {
+			return nil, errors.Wrapf(ErrInvalidQuery, "%s is never null", field.Name)
+		}
+
+		if op != "eq" && op != "ne" {
+			return nil, errors.Wrapf(ErrInvalidQuery, "null can only be compared with eq or ne")
+		}
+
+		return &comparisonNode{field: field, op: op}, nil
+	}
+
+	if field.Type == Bool && op != "eq" && op != "ne" {
+		return nil, errors.Wrapf(ErrInvalidQuery, "%s is a boolean and can only be compared with eq or ne", field.Name)
+	}
+
+	value, err := parseLiteral(field, valueToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comparisonNode{field: field, op: op, value: value}, nil
+}
+
+// parseLiteral checks a literal against the type of the field. Numbers stay strings
+// so the database compares them without losing precision.
+func parseLiteral(field Field, t token) (interface{}, error) {
+	invalid := errors.Wrapf(ErrInvalidQuery, "%q is not a valid %s for %s", t.text, field.Type, field.Name)
+
+	if t.kind != wordToken && t.kind != stringToken {
+		return nil, invalid
+	}
+
+	switch field.Type {
+	case String:
+		if t.kind != stringToken {
+			return nil, errors.Wrapf(ErrInvalidQuery, "%s needs a quoted string but found %q", field.Name, t.text)
+		}
+
+		return t.value, nil
+	case Integer:
+		if _, err := strconv.ParseInt(t.value, 10, 64); err != nil || t.kind != wordToken {
+			return nil, invalid
+		}
+
+		return t.value, nil
+	case Number:
+		if _, err := strconv.ParseFloat(t.value, 64); err != nil || t.kind != wordToken {
+			return nil, invalid
+		}
+
+		return t.value, nil
+	case Time:
+		return parseTime(t.value, invalid)
+	case Bool:
+		value, err := strconv.ParseBool(t.value)
+		if err != nil || t.kind != wordToken {
+			return nil, invalid
+		}
+
+		return value, nil
+	default:
+		return nil, invalid
+	}
+}
+
+// parseTime accepts RFC 3339 times and plain dates, which are midnight UTC.
+func parseTime(value string, invalid error) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, invalid
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
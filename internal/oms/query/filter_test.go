@@ -0,0 +1,132 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = NewSchema(
+	Field{Name: "ID", Column: "c.id", Type: Integer},
+	Field{Name: "Name", Column: "c.name", Type: String},
+	Field{Name: "Booked", Column: "c.booked_amount", Type: Number},
+	Field{Name: "Locked", Column: "c.locked", Type: Bool},
+	Field{Name: "StartedAt", Column: "c.started_at", Type: Time, Nullable: true},
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "comparison",
+			filter:    "Booked gt 100.5",
+			wantWhere: "base AND c.booked_amount > $1",
+			wantArgs:  []interface{}{"100.5"},
+		},
+		{
+			name:      "field names ignore case",
+			filter:    "name EQ 'spring'",
+			wantWhere: "base AND c.name = $1",
+			wantArgs:  []interface{}{"spring"},
+		},
+		{
+			name:      "and binds tighter than or",
+			filter:    "ID eq 1 or ID eq 2 and Locked eq true",
+			wantWhere: "base AND (c.id = $1 OR (c.id = $2 AND c.locked = $3))",
+			wantArgs:  []interface{}{"1", "2", true},
+		},
+		{
+			name:      "parentheses group or before and",
+			filter:    "(ID eq 1 or ID eq 2) and Locked eq true",
+			wantWhere: "base AND ((c.id = $1 OR c.id = $2) AND c.locked = $3)",
+			wantArgs:  []interface{}{"1", "2", true},
+		},
+		{
+			name:      "or is left associative",
+			filter:    "ID eq 1 or ID eq 2 or ID eq 3",
+			wantWhere: "base AND ((c.id = $1 OR c.id = $2) OR c.id = $3)",
+			wantArgs:  []interface{}{"1", "2", "3"},
+		},
+		{
+			name:      "null",
+			filter:    "StartedAt eq null and StartedAt ne null",
+			wantWhere: "base AND (c.started_at IS NULL AND c.started_at IS NOT NULL)",
+			wantArgs:  []interface{}{},
+		},
+		{
+			name:      "date",
+			filter:    "StartedAt ge 2024-01-01",
+			wantWhere: "base AND c.started_at >= $1",
+			wantArgs:  []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "contains escapes the like wildcards",
+			filter:    `contains(Name,'50%_off\')`,
+			wantWhere: `base AND c.name ILIKE ('%' || $1 || '%') ESCAPE '\'`,
+			wantArgs:  []interface{}{`50\%\_off\\`},
+		},
+		{
+			name:      "contains keeps an injection attempt in its argument",
+			filter:    "contains(Name,''' OR 1=1 --')",
+			wantWhere: `base AND c.name ILIKE ('%' || $1 || '%') ESCAPE '\'`,
+			wantArgs:  []interface{}{"' OR 1=1 --"},
+		},
+		{
+			name:      "comparison keeps an injection attempt in its argument",
+			filter:    "Name eq 'x''; DROP TABLE oms.campaigns; --'",
+			wantWhere: "base AND c.name = $1",
+			wantArgs:  []interface{}{"x'; DROP TABLE oms.campaigns; --"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := Parse(testSchema, tt.filter, "")
+			require.NoError(t, err)
+
+			statement, err := list.Build("base", nil, "c.id", nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantWhere, statement.Where)
+			require.Equal(t, tt.wantArgs, statement.Args)
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr string
+	}{
+		{name: "field that is not whitelisted", filter: "secret eq 'x'", wantErr: `unknown field "secret"`},
+		{name: "column name instead of field", filter: "c.name eq 'x'", wantErr: `unknown field "c.name"`},
+		{name: "injection as a field", filter: "1=1; -- eq 'x'", wantErr: `unknown field "1=1;"`},
+		{name: "contains on a field that is not whitelisted", filter: "contains(secret,'x')", wantErr: "unknown field"},
+		{name: "contains with an unquoted value", filter: "contains(Name,x)", wantErr: "needs a quoted string"},
+		{name: "contains with a raw sql value", filter: "contains(Name,'a') OR 1=1", wantErr: `unknown field "1=1"`},
+		{name: "contains on a number", filter: "contains(Booked,'1')", wantErr: "contains needs a string field"},
+		{name: "unknown operator", filter: "Name like 'x'", wantErr: `unknown operator "like"`},
+		{name: "number that is not a number", filter: "Booked gt 1;DROP", wantErr: "is not a valid number"},
+		{name: "quoted number", filter: "Booked gt '1'", wantErr: "is not a valid number"},
+		{name: "unquoted string", filter: "Name eq spring", wantErr: "needs a quoted string"},
+		{name: "null on a field that is never null", filter: "Name eq null", wantErr: "Name is never null"},
+		{name: "ordering a boolean", filter: "Locked gt true", wantErr: "can only be compared with eq or ne"},
+		{name: "unterminated string", filter: "Name eq 'x", wantErr: "unterminated string"},
+		{name: "unbalanced parenthesis", filter: "(ID eq 1", wantErr: "ended unexpectedly"},
+		{name: "trailing tokens", filter: "ID eq 1 ID", wantErr: `unexpected "ID"`},
+		{name: "missing operand", filter: "ID eq 1 and", wantErr: "ended unexpectedly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(testSchema, tt.filter, "")
+			require.ErrorIs(t, err, ErrInvalidQuery)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
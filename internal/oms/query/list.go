@@ -0,0 +1,259 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const maxOrderByKeys = 5
+
+type orderKey struct {
+	field Field
+	desc  bool
+}
+
+// List is a validated $filter and $orderby for one resource. Both stay in their
+// text form so they can travel in a pagination token.
+type List struct {
+	Filter  string
+	OrderBy string
+	filter  filterNode
+	order   []orderKey
+}
+
+// Parse checks the filter and order against the schema, empty strings mean no
+// filter and ordering by id alone.
+func Parse(schema *Schema, filter, orderBy string) (*List, error) {
+	list := &List{Filter: filter, OrderBy: orderBy}
+
+	if strings.TrimSpace(filter) != "" {
+		node, err := parseFilter(filter, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		list.filter = node
+	}
+
+	if strings.TrimSpace(orderBy) != "" {
+		order, err := parseOrderBy(orderBy, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		list.order = order
+	}
+
+	return list, nil
+}
+
+// parseOrderBy parses a comma separated list such as "StartedAt desc, Name".
+func parseOrderBy(orderBy string, schema *Schema) ([]orderKey, error) {
+	parts := strings.Split(orderBy, ",")
+	if len(parts) > maxOrderByKeys {
+		return nil, errors.Wrapf(ErrInvalidQuery, "$orderby can have at most %d fields", maxOrderByKeys)
+	}
+
+	order := make([]orderKey, 0, len(parts))
+	seen := map[string]bool{}
+
+	for _, part := range parts {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, errors.Wrapf(ErrInvalidQuery, "%q is not a field followed by asc or desc", strings.TrimSpace(part))
+		}
+
+		field, err := schema.field(words[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[field.Name] {
+			return nil, errors.Wrapf(ErrInvalidQuery, "%s appears more than once in $orderby", field.Name)
+		}
+
+		seen[field.Name] = true
+
+		key := orderKey{field: field}
+
+		if len(words) == 2 {
+			switch strings.ToLower(words[1]) {
+			case "asc":
+			case "desc":
+				key.desc = true
+			default:
+				return nil, errors.Wrapf(ErrInvalidQuery, "%q must be asc or desc", words[1])
+			}
+		}
+
+		order = append(order, key)
+	}
+
+	return order, nil
+}
+
+// Cursor is the position of a row that pages continue from, Values holds the value
+// of each $orderby field in order. A Backward cursor pages toward the first row.
+type Cursor struct {
+	ID       int
+	Values   []interface{}
+	Backward bool
+}
+
+// CursorAt reads the position of item, a pointer to the resource struct whose
+// fields are named like the schema fields.
+func (l *List) CursorAt(id int, item interface{}) Cursor {
+	cursor := Cursor{ID: id}
+
+	value := reflect.Indirect(reflect.ValueOf(item))
+
+	for _, key := range l.order {
+		cursor.Values = append(cursor.Values, fieldValue(value, key.field))
+	}
+
+	return cursor
+}
+
+// fieldValue returns the value of a field in a form that survives a JSON round
+// trip, times are RFC 3339 strings and a zero time of a nullable field is null.
+func fieldValue(item reflect.Value, field Field) interface{} {
+	v := item.FieldByName(field.Name)
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() && field.Nullable {
+			return nil
+		}
+
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return v.Interface()
+}
+
+// Statement holds the clauses of a page query, the placeholders in Where match Args.
+type Statement struct {
+	Where   string
+	OrderBy string
+	Args    []interface{}
+}
+
+type sortKey struct {
+	expr  string
+	desc  bool
+	value interface{}
+}
+
+// Build combines the base condition, whose placeholders are numbered from $1, with
+// the filter and, when after is given, the keyset position that follows it. The
+// id column breaks ties so the order is total and no row is skipped or repeated.
+// A backward cursor selects the rows before it in reverse order, the caller flips
+// them back.
+func (l *List) Build(base string, baseArgs []interface{}, idColumn string, after *Cursor) (Statement, error) {
+	a := &args{values: append([]interface{}{}, baseArgs...)}
+	conditions := []string{base}
+
+	if l.filter != nil {
+		conditions = append(conditions, l.filter.sql(a))
+	}
+
+	keys, err := l.sortKeys(idColumn, after)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	if after != nil {
+		conditions = append(conditions, keysetCondition(keys, a))
+	}
+
+	orderBy := make([]string, len(keys))
+
+	for i, key := range keys {
+		orderBy[i] = key.expr
+		if key.desc {
+			orderBy[i] += " DESC"
+		}
+	}
+
+	return Statement{
+		Where:   strings.Join(conditions, " AND "),
+		OrderBy: strings.Join(orderBy, ", "),
+		Args:    a.values,
+	}, nil
+}
+
+// sortKeys expands the order into SQL sort keys. A nullable field sorts on whether
+// it is null first, so nulls come last ascending and first descending, as in
+// Postgres, and the keyset comparison never has to compare against null.
+func (l *List) sortKeys(idColumn string, after *Cursor) ([]sortKey, error) {
+	if after != nil && len(after.Values) != len(l.order) {
+		return nil, errors.Wrap(ErrInvalidQuery, "the pagination token does not match $orderby")
+	}
+
+	backward := after != nil && after.Backward
+
+	var keys []sortKey
+
+	for i, key := range l.order {
+		var value interface{}
+		if after != nil {
+			value = after.Values[i]
+		}
+
+		desc := key.desc != backward
+
+		if key.field.Nullable {
+			keys = append(keys, sortKey{expr: "(" + key.field.Column + " IS NULL)", desc: desc, value: value == nil})
+		}
+
+		keys = append(keys, sortKey{expr: key.field.Column, desc: desc, value: value})
+	}
+
+	var id interface{}
+	if after != nil {
+		id = after.ID
+	}
+
+	return append(keys, sortKey{expr: idColumn, desc: backward, value: id}), nil
+}
+
+// keysetCondition selects the rows after the cursor: a row comes later when it
+// ties on the first keys and is past the cursor on the next one.
+func keysetCondition(keys []sortKey, a *args) string {
+	placeholders := make([]string, len(keys))
+	for i, key := range keys {
+		placeholders[i] = a.add(key.value)
+	}
+
+	alternatives := make([]string, len(keys))
+
+	for i, key := range keys {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].expr+" IS NOT DISTINCT FROM "+placeholders[j])
+		}
+
+		op := " > "
+		if key.desc {
+			op = " < "
+		}
+
+		parts = append(parts, key.expr+op+placeholders[i])
+		alternatives[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
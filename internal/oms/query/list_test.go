@@ -0,0 +1,146 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      string
+		orderBy     string
+		after       *Cursor
+		wantWhere   string
+		wantOrderBy string
+		wantArgs    []interface{}
+	}{
+		{
+			name:        "first page ordered by id",
+			wantWhere:   "c.deleted_at IS NULL AND c.campaign_id = $1",
+			wantOrderBy: "c.id",
+			wantArgs:    []interface{}{7},
+		},
+		{
+			name:        "next page ordered by id",
+			after:       &Cursor{ID: 40},
+			wantWhere:   "c.deleted_at IS NULL AND c.campaign_id = $1 AND ((c.id > $2))",
+			wantOrderBy: "c.id",
+			wantArgs:    []interface{}{7, 40},
+		},
+		{
+			name:    "filter placeholders follow the base ones",
+			filter:  "Name eq 'x'",
+			orderBy: "Booked desc",
+			after:   &Cursor{ID: 40, Values: []interface{}{"12.5"}},
+			wantWhere: "c.deleted_at IS NULL AND c.campaign_id = $1 AND c.name = $2 AND ((c.booked_amount < $3) OR " +
+				"(c.booked_amount IS NOT DISTINCT FROM $3 AND c.id > $4))",
+			wantOrderBy: "c.booked_amount DESC, c.id",
+			wantArgs:    []interface{}{7, "x", "12.5", 40},
+		},
+		{
+			name:    "next page of several keys",
+			orderBy: "Name, Booked desc",
+			after:   &Cursor{ID: 40, Values: []interface{}{"b", "12.5"}},
+			wantWhere: "c.deleted_at IS NULL AND c.campaign_id = $1 AND ((c.name > $2) OR " +
+				"(c.name IS NOT DISTINCT FROM $2 AND c.booked_amount < $3) OR " +
+				"(c.name IS NOT DISTINCT FROM $2 AND c.booked_amount IS NOT DISTINCT FROM $3 AND c.id > $4))",
+			wantOrderBy: "c.name, c.booked_amount DESC, c.id",
+			wantArgs:    []interface{}{7, "b", "12.5", 40},
+		},
+		{
+			name:    "previous page of several keys flips every direction",
+			orderBy: "Name, Booked desc",
+			after:   &Cursor{ID: 40, Values: []interface{}{"b", "12.5"}, Backward: true},
+			wantWhere: "c.deleted_at IS NULL AND c.campaign_id = $1 AND ((c.name < $2) OR " +
+				"(c.name IS NOT DISTINCT FROM $2 AND c.booked_amount > $3) OR " +
+				"(c.name IS NOT DISTINCT FROM $2 AND c.booked_amount IS NOT DISTINCT FROM $3 AND c.id < $4))",
+			wantOrderBy: "c.name DESC, c.booked_amount, c.id DESC",
+			wantArgs:    []interface{}{7, "b", "12.5", 40},
+		},
+		{
+			name:    "nullable key sorts on being null first",
+			orderBy: "StartedAt",
+			after:   &Cursor{ID: 40, Values: []interface{}{nil}},
+			wantWhere: "c.deleted_at IS NULL AND c.campaign_id = $1 AND (((c.started_at IS NULL) > $2) OR " +
+				"((c.started_at IS NULL) IS NOT DISTINCT FROM $2 AND c.started_at > $3) OR " +
+				"((c.started_at IS NULL) IS NOT DISTINCT FROM $2 AND c.started_at IS NOT DISTINCT FROM $3 AND c.id > $4))",
+			wantOrderBy: "(c.started_at IS NULL), c.started_at, c.id",
+			wantArgs:    []interface{}{7, true, nil, 40},
+		},
+		{
+			name:    "previous page of a nullable key",
+			orderBy: "StartedAt desc",
+			after:   &Cursor{ID: 40, Values: []interface{}{"2024-01-01T00:00:00Z"}, Backward: true},
+			wantWhere: "c.deleted_at IS NULL AND c.campaign_id = $1 AND (((c.started_at IS NULL) > $2) OR " +
+				"((c.started_at IS NULL) IS NOT DISTINCT FROM $2 AND c.started_at > $3) OR " +
+				"((c.started_at IS NULL) IS NOT DISTINCT FROM $2 AND c.started_at IS NOT DISTINCT FROM $3 AND c.id < $4))",
+			wantOrderBy: "(c.started_at IS NULL), c.started_at, c.id DESC",
+			wantArgs:    []interface{}{7, false, "2024-01-01T00:00:00Z", 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := Parse(testSchema, tt.filter, tt.orderBy)
+			require.NoError(t, err)
+
+			statement, err := list.Build("c.deleted_at IS NULL AND c.campaign_id = $1", []interface{}{7}, "c.id", tt.after)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantWhere, statement.Where)
+			require.Equal(t, tt.wantOrderBy, statement.OrderBy)
+			require.Equal(t, tt.wantArgs, statement.Args)
+		})
+	}
+}
+
+func TestBuildCursorOfOtherOrder(t *testing.T) {
+	list, err := Parse(testSchema, "", "Name, Booked")
+	require.NoError(t, err)
+
+	_, err = list.Build("true", nil, "c.id", &Cursor{ID: 1, Values: []interface{}{"b"}})
+	require.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestParseOrderByErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		orderBy string
+		wantErr string
+	}{
+		{name: "field that is not whitelisted", orderBy: "secret", wantErr: `unknown field "secret"`},
+		{name: "injection as a direction", orderBy: "Name; DROP TABLE x", wantErr: "is not a field followed by asc or desc"},
+		{name: "unknown direction", orderBy: "Name sideways", wantErr: `"sideways" must be asc or desc`},
+		{name: "repeated field", orderBy: "Name, name desc", wantErr: "Name appears more than once"},
+		{name: "empty key", orderBy: "Name,", wantErr: "is not a field followed by asc or desc"},
+		{name: "too many keys", orderBy: "ID, Name, Booked, Locked, StartedAt, ID", wantErr: "at most 5 fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(testSchema, "", tt.orderBy)
+			require.ErrorIs(t, err, ErrInvalidQuery)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCursorAt(t *testing.T) {
+	type campaign struct {
+		Name      string
+		Booked    float64
+		StartedAt time.Time
+	}
+
+	list, err := Parse(testSchema, "", "Name, StartedAt desc, Booked")
+	require.NoError(t, err)
+
+	cursor := list.CursorAt(3, &campaign{Name: "b", Booked: 1.5})
+	require.Equal(t, Cursor{ID: 3, Values: []interface{}{"b", nil, 1.5}}, cursor)
+
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+	cursor = list.CursorAt(3, &campaign{Name: "b", StartedAt: started})
+	require.Equal(t, "2024-01-02T02:04:05Z", cursor.Values[1])
+}
@@ -0,0 +1,105 @@
+// Package query parses the $filter and $orderby options of the list endpoints and
+// turns them into parameterized SQL with keyset pagination
+package query
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidQuery is wrapped by every error about a malformed $filter or $orderby.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// FieldType decides which literals and operators a field accepts.
+type FieldType int
+
+const (
+	String FieldType = iota
+	Integer
+	Number
+	Time
+	Bool
+)
+
+func (t FieldType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Integer:
+		return "integer"
+	case Number:
+		return "number"
+	case Time:
+		return "time"
+	case Bool:
+		return "boolean"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is a field of a resource that can be filtered and sorted on.
+type Field struct {
+	// Name is the name of the field in the resource, the value of the last row of a
+	// page is read from the Go field with this name.
+	Name string
+	// Column is the SQL expression of the field, it is never built from user input.
+	Column   string
+	Type     FieldType
+	Nullable bool
+}
+
+// Schema is the whitelist of fields of a resource.
+type Schema struct {
+	fields map[string]Field
+	names  []string
+}
+
+// NewSchema builds a schema, field names are matched without regard to case.
+func NewSchema(fields ...Field) *Schema {
+	schema := &Schema{fields: make(map[string]Field, len(fields))}
+
+	for _, field := range fields {
+		schema.fields[strings.ToLower(field.Name)] = field
+		schema.names = append(schema.names, field.Name)
+	}
+
+	return schema
+}
+
+// Renamed returns a schema that matches the fields by the names rename gives them,
+// the values are still read from the Go fields of their original names.
+func (s *Schema) Renamed(rename func(string) string) *Schema {
+	renamed := &Schema{fields: make(map[string]Field, len(s.fields))}
+
+	for _, name := range s.names {
+		field := s.fields[strings.ToLower(name)]
+		newName := rename(name)
+
+		renamed.fields[strings.ToLower(newName)] = field
+		renamed.names = append(renamed.names, newName)
+	}
+
+	return renamed
+}
+
+func (s *Schema) field(name string) (Field, error) {
+	field, ok := s.fields[strings.ToLower(name)]
+	if !ok {
+		return Field{}, errors.Wrapf(ErrInvalidQuery, "unknown field %q, expected one of %s",
+			name, strings.Join(s.names, ", "))
+	}
+
+	return field, nil
+}
+
+// args collects the values of a statement and hands out their placeholders.
+type args struct {
+	values []interface{}
+}
+
+func (a *args) add(value interface{}) string {
+	a.values = append(a.values, value)
+	return "$" + itoa(len(a.values))
+}
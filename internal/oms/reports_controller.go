@@ -0,0 +1,77 @@
+package oms
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/gin-gonic/gin"
+)
+
+type reportsController struct {
+	dbQueries *db.Queries
+	logger    *slog.Logger
+}
+
+func newReportsController(logger *slog.Logger, dbQueries *db.Queries) *reportsController {
+	return &reportsController{dbQueries: dbQueries, logger: logger}
+}
+
+// register adds the report routes.
+func (s *reportsController) register(router gin.IRoutes) {
+	router.GET("/reports/commissions", s.commissions)
+	router.GET("/reports/variance", s.variance)
+}
+
+// commissions reports the commission of every sales rep with a commission plan,
+// revenue counts in the period the invoice was issued or collected depending on the plan.
+func (s *reportsController) commissions(c *gin.Context) {
+	period := c.Query("period")
+
+	start, end, err := parseReportPeriod(period)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	rows, err := s.dbQueries.ListCommissionRevenue(c.Request.Context(), db.ListCommissionRevenueParams{
+		PeriodStart: start,
+		PeriodEnd:   end,
+	})
+	if err != nil {
+		s.logger.Error("Error computing commissions", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		respondError(c, err)
+
+		return
+	}
+
+	report, err := models.NewCommissionReportFromDB(period, start, end, rows)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, report)
+}
+
+// variance lists the line items, or campaigns with groupBy=campaign, whose actual
+// differs from booked by more than the threshold, largest variance first.
+func (s *reportsController) variance(c *gin.Context) {
+	opts, err := newVarianceReportOptions(c.Query("threshold"), c.Query("groupBy"), c.Query("sort"),
+		c.Query("status"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	report, err := buildVarianceReport(c.Request.Context(), s.dbQueries, opts)
+	if err != nil {
+		s.logger.Error("Error computing variance", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		respondError(c, err)
+
+		return
+	}
+
+	respond(c, http.StatusOK, report)
+}
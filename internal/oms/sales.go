@@ -0,0 +1,153 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+var errInvalidPeriod = errors.New("period must be YYYY, YYYY-Qn or YYYY-MM")
+
+// validateSalesRep checks the fields of a sales rep and that its commission plan exists.
+func validateSalesRep(ctx context.Context, dbQueries *db.Queries, rep *models.SalesRep) error {
+	var fieldErrors []models.FieldError
+
+	if rep.Name == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "Name", Message: "is required"})
+	}
+
+	if rep.CommissionPlanID != nil {
+		_, err := dbQueries.GetCommissionPlan(ctx, int32(*rep.CommissionPlanID))
+		if errors.Is(err, sql.ErrNoRows) {
+			fieldErrors = append(fieldErrors,
+				models.FieldError{Field: "CommissionPlanID", Message: "commission plan does not exist"})
+		} else if err != nil {
+			return errors.Wrap(err, "cannot get the commission plan of the sales rep")
+		}
+	}
+
+	return newValidationError(fieldErrors)
+}
+
+// updateSalesRep replaces a sales rep that is still at a version the request named
+// and returns the new version.
+func updateSalesRep(ctx context.Context, dbQueries *db.Queries, id int32, rep *models.SalesRep,
+	match *ifMatch) (int32, error) {
+	var version int32
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetSalesRepForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(existing.Version); err != nil {
+			return err
+		}
+
+		if err := validateSalesRep(ctx, q, rep); err != nil {
+			return err
+		}
+
+		if err := q.UpdateSalesRep(ctx, rep.ToUpdateSalesRep(id)); err != nil {
+			return err
+		}
+
+		updated, err := q.GetSalesRep(ctx, id)
+		version = updated.Version
+
+		return err
+	})
+
+	return version, err
+}
+
+// setCampaignOwners replaces the owners of a campaign. The owners are versioned
+// with the campaign, the change is checked against and bumps its version.
+func setCampaignOwners(ctx context.Context, dbQueries *db.Queries, campaignID int32,
+	owners []*models.CampaignOwner, match *ifMatch) (int32, error) {
+	fieldErrors := models.ValidateCampaignOwners(owners)
+
+	for i, owner := range owners {
+		_, err := dbQueries.GetSalesRep(ctx, int32(owner.SalesRepID))
+		if errors.Is(err, sql.ErrNoRows) {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   fmt.Sprintf("Owners[%d].SalesRepID", i),
+				Message: "sales rep does not exist",
+			})
+		} else if err != nil {
+			return 0, errors.Wrap(err, "cannot get the sales rep")
+		}
+	}
+
+	if err := newValidationError(fieldErrors); err != nil {
+		return 0, err
+	}
+
+	var version int32
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		campaign, err := q.GetCampaignForUpdate(ctx, campaignID)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(campaign.Version); err != nil {
+			return err
+		}
+
+		if err := q.DeleteCampaignOwners(ctx, campaignID); err != nil {
+			return errors.Wrap(err, "cannot remove the current owners")
+		}
+
+		for _, owner := range owners {
+			if err := q.AddCampaignOwner(ctx, owner.ToAddCampaignOwner(campaignID)); err != nil {
+				return errors.Wrapf(err, "cannot add sales rep %d as owner", owner.SalesRepID)
+			}
+		}
+
+		version, err = q.TouchCampaign(ctx, campaignID)
+
+		return errors.Wrap(err, "cannot bump the campaign version")
+	})
+
+	return version, err
+}
+
+// parseReportPeriod turns a year ("2024"), quarter ("2024-Q1") or month ("2024-03")
+// into the UTC range it covers, the end is exclusive.
+func parseReportPeriod(period string) (time.Time, time.Time, error) {
+	if month, err := time.Parse("2006-01", period); err == nil {
+		return month, month.AddDate(0, 1, 0), nil
+	}
+
+	if year, err := time.Parse("2006", period); err == nil {
+		return year, year.AddDate(1, 0, 0), nil
+	}
+
+	yearPart, quarterPart, found := strings.Cut(period, "-Q")
+	if !found {
+		return time.Time{}, time.Time{}, errInvalidPeriod
+	}
+
+	year, err := time.Parse("2006", yearPart)
+	if err != nil {
+		return time.Time{}, time.Time{}, errInvalidPeriod
+	}
+
+	quarter, err := strconv.Atoi(quarterPart)
+	if err != nil || quarter < 1 || quarter > 4 {
+		return time.Time{}, time.Time{}, errInvalidPeriod
+	}
+
+	start := year.AddDate(0, (quarter-1)*3, 0)
+
+	return start, start.AddDate(0, 3, 0), nil
+}
@@ -0,0 +1,271 @@
+package oms
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/gin-gonic/gin"
+)
+
+// salesController manages sales reps, their commission plans and the campaigns they own.
+type salesController struct {
+	dbQueries *db.Queries
+	logger    *slog.Logger
+}
+
+func newSalesController(logger *slog.Logger, dbQueries *db.Queries) *salesController {
+	return &salesController{dbQueries: dbQueries, logger: logger}
+}
+
+// register adds the commission plan, sales rep and campaign owner routes.
+func (s *salesController) register(router gin.IRoutes) {
+	router.POST("/commissionPlans", s.createCommissionPlan)
+	router.GET("/commissionPlans", s.listCommissionPlans)
+	router.GET("/commissionPlans/:id", s.getCommissionPlan)
+	router.POST("/salesReps", s.createSalesRep)
+	router.GET("/salesReps", s.listSalesReps)
+	router.GET("/salesReps/:id", s.getSalesRep)
+	router.PUT("/salesReps/:id", s.updateSalesRep)
+	router.GET("/campaigns/:id/owners", s.listCampaignOwners)
+	router.PUT("/campaigns/:id/owners", s.setCampaignOwners)
+}
+
+func (s *salesController) createCommissionPlan(c *gin.Context) {
+	var req models.CommissionPlan
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	if fieldErrors := req.Validate(); len(fieldErrors) > 0 {
+		respondError(c, &validationError{fields: fieldErrors})
+		return
+	}
+
+	plan, err := s.dbQueries.CreateCommissionPlan(c.Request.Context(), req.ToCreateCommissionPlan())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	planModel, err := models.NewCommissionPlanFromDB(&plan)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, planModel)
+}
+
+func (s *salesController) getCommissionPlan(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	plan, err := s.dbQueries.GetCommissionPlan(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "commission plan not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	planModel, err := models.NewCommissionPlanFromDB(&plan)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, planModel)
+}
+
+func (s *salesController) listCommissionPlans(c *gin.Context) {
+	plans, err := s.dbQueries.ListCommissionPlans(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	plansResp := &models.List[models.CommissionPlan]{Items: make([]*models.CommissionPlan, len(plans))}
+
+	for i := range plans {
+		plansResp.Items[i], err = models.NewCommissionPlanFromDB(&plans[i])
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	respond(c, http.StatusOK, plansResp)
+}
+
+func (s *salesController) createSalesRep(c *gin.Context) {
+	var req models.SalesRep
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	if err := validateSalesRep(c.Request.Context(), s.dbQueries, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	rep, err := s.dbQueries.CreateSalesRep(c.Request.Context(), req.ToCreateSalesRep())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, models.NewSalesRepFromDB(&rep))
+}
+
+func (s *salesController) getSalesRep(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	rep, err := s.dbQueries.GetSalesRep(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "sales rep not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, int(rep.Version))
+	respond(c, http.StatusOK, models.NewSalesRepFromDB(&rep))
+}
+
+func (s *salesController) listSalesReps(c *gin.Context) {
+	reps, err := s.dbQueries.ListSalesReps(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	repsResp := &models.List[models.SalesRep]{Items: make([]*models.SalesRep, len(reps))}
+	for i := range reps {
+		repsResp.Items[i] = models.NewSalesRepFromDB(&reps[i])
+	}
+
+	respond(c, http.StatusOK, repsResp)
+}
+
+func (s *salesController) updateSalesRep(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
+	}
+
+	var req models.SalesRep
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	version, err := updateSalesRep(c.Request.Context(), s.dbQueries, id, &req, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "sales rep not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, int(version))
+	respond(c, http.StatusOK, int(id))
+}
+
+func (s *salesController) listCampaignOwners(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	campaign, err := s.dbQueries.GetCampaign(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	owners, err := s.dbQueries.ListCampaignOwners(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ownersResp := &models.List[models.CampaignOwner]{Items: make([]*models.CampaignOwner, len(owners))}
+
+	for i := range owners {
+		ownersResp.Items[i], err = models.NewCampaignOwnerFromDB(&owners[i])
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	setETag(c, int(campaign.Version))
+	respond(c, http.StatusOK, ownersResp)
+}
+
+func (s *salesController) setCampaignOwners(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
+	}
+
+	var req []*models.CampaignOwner
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	version, err := setCampaignOwners(c.Request.Context(), s.dbQueries, id, req, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "campaign not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, int(version))
+	respond(c, http.StatusOK, int(id))
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"net"
 	"os"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 
 	"github.com/chrisrob11/oms/internal/oms/db"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
 )
 
 var DB *sql.DB
@@ -20,28 +23,125 @@ type Server struct {
 	campaignsController     *campaignsController
 	campaignlinesController *campaignLineItemsController
 	invoicesController      *invoicesController
+	salesController         *salesController
+	reportsController       *reportsController
+	deliveryController      *deliveryController
+	webhooksController      *webhooksController
+	trashPurger             *trashPurger
+	webhookDispatcher       *webhookDispatcher
+	grpcServer              *grpc.Server
+	grpcAddr                string
 }
 
 func NewServer() (*Server, error) {
-	r := gin.Default()
 	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
 
+	r := gin.New()
+	r.Use(gin.Logger(), requestContext(logger), gin.CustomRecovery(recoverWithProblem))
+	r.NoRoute(noRoute)
+
 	db, err := db.NewDBContext(logger)
 	if err != nil {
 		return nil, err
 	}
 
-	campaignController := newCampaignsController(logger, r, db)
-	campaignLineItemsController := newCampaignLineItemsController(logger, r, db)
-	invoices := newInvoicesController(logger, r, db)
+	tokens, err := newTokenCodecFromEnv(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := loadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	campaignController := newCampaignsController(logger, db, tokens)
+	campaignLineItemsController := newCampaignLineItemsController(logger, db, tokens)
+	invoices := newInvoicesController(logger, db, tokens)
+	sales := newSalesController(logger, db)
+	reports := newReportsController(logger, db)
+	delivery := newDeliveryController(logger, db)
+	webhooks := newWebhooksController(logger, db)
+
+	v1Routes := r.Group(v1.BasePath, validateRequests(doc, v1.BasePath))
+	campaignController.register(v1Routes)
+	campaignLineItemsController.register(v1Routes)
+	invoices.register(v1Routes)
+	sales.register(v1Routes)
+	reports.register(v1Routes)
+	delivery.register(v1Routes)
+	webhooks.register(v1Routes)
+
+	// Checked once every /v1 route is registered, a route added later would not be.
+	if err := checkOpenAPIRoutes(doc, v1.BasePath, r.Routes()); err != nil {
+		return nil, err
+	}
+
+	// The unversioned routes serve the models as they are until their clients have
+	// moved to /v1.
+	unversionedRoutes := r.Group("", deprecated)
+	campaignController.register(unversionedRoutes)
+	campaignLineItemsController.register(unversionedRoutes)
+	invoices.register(unversionedRoutes)
+	sales.register(unversionedRoutes)
+	reports.register(unversionedRoutes)
+	delivery.register(unversionedRoutes)
+	webhooks.register(unversionedRoutes)
+
+	registerOpenAPIRoutes(r)
+
+	graphQL, err := newGraphQLAPI(logger, db, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	graphQL.register(r)
+
+	purger, err := newTrashPurgerFromEnv(logger, db)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcher, err := newWebhookDispatcherFromEnv(logger, db)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcAddr := os.Getenv("OMS_GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = defaultGRPCAddr
+	}
 
 	return &Server{engine: r, db: db, campaignsController: campaignController,
 		invoicesController: invoices, campaignlinesController: campaignLineItemsController,
-		logger: logger,
+		salesController: sales, reportsController: reports, deliveryController: delivery,
+		webhooksController: webhooks, logger: logger, trashPurger: purger, webhookDispatcher: dispatcher,
+		grpcServer: newGRPCServer(logger, db, tokens), grpcAddr: grpcAddr,
 	}, nil
 }
 
 func (s *Server) Run(ctx context.Context) (err error) {
 	s.logger.Info("Server Starting")
+
+	go s.trashPurger.Run(ctx)
+	go s.webhookDispatcher.Run(ctx)
+
+	listener, err := net.Listen("tcp", s.grpcAddr)
+	if err != nil {
+		return err
+	}
+
+	// The gRPC API is served next to the REST API until the context is done.
+	go func() {
+		<-ctx.Done()
+		s.grpcServer.GracefulStop()
+	}()
+
+	go func() {
+		if serveErr := s.grpcServer.Serve(listener); serveErr != nil {
+			s.logger.Error("gRPC server stopped", slog.String("error", serveErr.Error()))
+		}
+	}()
+
 	return s.engine.Run() // listen and serve on 0.0.0.0:8080
 }
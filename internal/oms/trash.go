@@ -0,0 +1,304 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	includeDeletedQueryParamName = "includeDeleted"
+
+	defaultTrashRetention     = 30 * 24 * time.Hour
+	defaultTrashPurgeInterval = time.Hour
+)
+
+var errParentDeleted = errors.New("the campaign of this record is deleted, restore the campaign first")
+
+func extractIncludeDeleted(c *gin.Context) (includeDeleted bool, hasError bool) {
+	value := c.Query(includeDeletedQueryParamName)
+	if value == "" {
+		return false, false
+	}
+
+	includeDeleted, err := strconv.ParseBool(value)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest,
+			includeDeletedQueryParamName+" must be true or false")
+		return false, true
+	}
+
+	return includeDeleted, false
+}
+
+// restoreCampaign takes a campaign out of the trash together with the line items
+// and draft invoices that were trashed with it.
+func restoreCampaign(ctx context.Context, dbQueries *db.Queries, id int32) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		campaign, err := q.GetCampaignWithDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !campaign.DeletedAt.Valid {
+			return nil
+		}
+
+		lineItemIDs, err := q.RestoreCampaignLinesForCampaign(ctx, db.RestoreCampaignLinesForCampaignParams{
+			CampaignID: id,
+			DeletedAt:  campaign.DeletedAt,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot restore line items")
+		}
+
+		invoiceIDs, err := q.RestoreInvoicesForCampaign(ctx, db.RestoreInvoicesForCampaignParams{
+			CampaignID: id,
+			DeletedAt:  campaign.DeletedAt,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot restore invoices")
+		}
+
+		if err := q.RestoreCampaign(ctx, id); err != nil {
+			return errors.Wrap(err, "cannot restore campaign")
+		}
+
+		return emitRestoredEvents(ctx, q, id, lineItemIDs, invoiceIDs)
+	})
+}
+
+// emitRestoredEvents sends a restored event for the campaign and for each of the
+// line items and invoices restored with it.
+func emitRestoredEvents(ctx context.Context, q *db.Queries, campaignID int32, lineItemIDs, invoiceIDs []int32) error {
+	if err := emitCampaignEvent(ctx, q, models.WebhookEventCampaignRestored, campaignID); err != nil {
+		return err
+	}
+
+	for _, id := range lineItemIDs {
+		if err := emitLineItemEvent(ctx, q, models.WebhookEventLineItemRestored, id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range invoiceIDs {
+		if err := emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceRestored, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restoreCampaignLine(ctx context.Context, dbQueries *db.Queries, id int32) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		campaignLine, err := q.GetCampaignLineWithDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !campaignLine.DeletedAt.Valid {
+			return nil
+		}
+
+		if err := ensureCampaignNotDeleted(ctx, q, campaignLine.CampaignID); err != nil {
+			return err
+		}
+
+		if err := q.RestoreCampaignLine(ctx, id); err != nil {
+			return errors.Wrap(err, "cannot restore line item")
+		}
+
+		return emitLineItemEvent(ctx, q, models.WebhookEventLineItemRestored, id)
+	})
+}
+
+func restoreInvoice(ctx context.Context, dbQueries *db.Queries, id int32) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		invoice, err := q.GetInvoiceWithDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !invoice.DeletedAt.Valid {
+			return nil
+		}
+
+		if err := ensureCampaignNotDeleted(ctx, q, invoice.CampaignID); err != nil {
+			return err
+		}
+
+		if err := q.RestoreInvoice(ctx, id); err != nil {
+			return errors.Wrap(err, "cannot restore invoice")
+		}
+
+		return emitInvoiceEvent(ctx, q, models.WebhookEventInvoiceRestored, id)
+	})
+}
+
+func ensureCampaignNotDeleted(ctx context.Context, dbQueries *db.Queries, campaignID int32) error {
+	campaign, err := dbQueries.GetCampaignWithDeleted(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	if campaign.DeletedAt.Valid {
+		return errParentDeleted
+	}
+
+	return nil
+}
+
+// trashPurger permanently removes trashed rows once they are older than the retention.
+// Issued invoices and the line items they bill are billing records and are kept.
+type trashPurger struct {
+	dbQueries *db.Queries
+	logger    *slog.Logger
+	retention time.Duration
+	interval  time.Duration
+}
+
+// newTrashPurgerFromEnv configures the purger from OMS_TRASH_RETENTION and
+// OMS_TRASH_PURGE_INTERVAL, both parsed as go durations (e.g. "720h").
+func newTrashPurgerFromEnv(logger *slog.Logger, dbQueries *db.Queries) (*trashPurger, error) {
+	retention, err := durationFromEnv("OMS_TRASH_RETENTION", defaultTrashRetention)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := durationFromEnv("OMS_TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trashPurger{dbQueries: dbQueries, logger: logger, retention: retention, interval: interval}, nil
+}
+
+func durationFromEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%s is not a valid duration", name)
+	}
+
+	return duration, nil
+}
+
+// Run purges the trash every interval until the context is cancelled.
+func (p *trashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge removes the expired trash and sends a purged event with the last state of
+// every removed record in the same transaction.
+func (p *trashPurger) purge(ctx context.Context) {
+	cutoff := sql.NullTime{Valid: true, Time: time.Now().UTC().Add(-p.retention)}
+
+	err := p.dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		invoices, err := purgeInvoices(ctx, q, cutoff)
+		if err != nil {
+			return err
+		}
+
+		campaignLines, err := purgeCampaignLines(ctx, q, cutoff)
+		if err != nil {
+			return err
+		}
+
+		// Campaigns are purged last so their trashed children no longer reference them.
+		campaigns, err := purgeCampaigns(ctx, q, cutoff)
+		if err != nil {
+			return err
+		}
+
+		p.logger.Info("Trash purged",
+			slog.Attr{Key: "campaigns", Value: slog.IntValue(campaigns)},
+			slog.Attr{Key: "line_items", Value: slog.IntValue(campaignLines)},
+			slog.Attr{Key: "invoices", Value: slog.IntValue(invoices)})
+
+		return nil
+	})
+	if err != nil {
+		p.logger.Error("Error purging trash", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+	}
+}
+
+func purgeInvoices(ctx context.Context, q *db.Queries, cutoff sql.NullTime) (int, error) {
+	invoices, err := q.PurgeInvoices(ctx, cutoff)
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot purge invoices")
+	}
+
+	for _, invoice := range invoices {
+		invoiceModel, err := models.NewInvoiceFromDB(invoice)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := emitWebhookEvent(ctx, q, models.WebhookEventInvoicePurged, invoiceModel); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(invoices), nil
+}
+
+func purgeCampaignLines(ctx context.Context, q *db.Queries, cutoff sql.NullTime) (int, error) {
+	campaignLines, err := q.PurgeCampaignLines(ctx, cutoff)
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot purge line items")
+	}
+
+	for i := range campaignLines {
+		lineItem, err := models.NewCampaignLineItemFromDB(&campaignLines[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if err := emitWebhookEvent(ctx, q, models.WebhookEventLineItemPurged, lineItem); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(campaignLines), nil
+}
+
+func purgeCampaigns(ctx context.Context, q *db.Queries, cutoff sql.NullTime) (int, error) {
+	campaigns, err := q.PurgeCampaigns(ctx, cutoff)
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot purge campaigns")
+	}
+
+	for i := range campaigns {
+		err := emitWebhookEvent(ctx, q, models.WebhookEventCampaignPurged, models.NewCampaignFromDB(&campaigns[i]))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(campaigns), nil
+}
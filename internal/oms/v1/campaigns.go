@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+type Campaign struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Archiving bool       `json:"archiving"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	// Version is served as the ETag of the campaign.
+	Version int `json:"version"`
+}
+
+func NewCampaign(c *models.Campaign) *Campaign {
+	return &Campaign{
+		ID:        c.ID,
+		Name:      c.Name,
+		StartedAt: c.StartedAt,
+		EndedAt:   c.EndedAt,
+		Archiving: c.Archiving,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		DeletedAt: c.DeletedAt,
+		Version:   c.Version,
+	}
+}
+
+func (c *Campaign) Model() *models.Campaign {
+	return &models.Campaign{
+		ID:        c.ID,
+		Name:      c.Name,
+		StartedAt: c.StartedAt,
+		EndedAt:   c.EndedAt,
+		Archiving: c.Archiving,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		DeletedAt: c.DeletedAt,
+		Version:   c.Version,
+	}
+}
+
+// ExpandedCampaign is a campaign with the relations asked for with $expand, a
+// relation that was not asked for is left out of the response.
+type ExpandedCampaign struct {
+	Campaign
+	LineItems []*CampaignLineItem `json:"line_items"`
+	Invoices  []*Invoice          `json:"invoices"`
+}
+
+func NewExpandedCampaign(c *models.ExpandedCampaign) *ExpandedCampaign {
+	return &ExpandedCampaign{
+		Campaign:  *NewCampaign(&c.Campaign),
+		LineItems: mapSlice(c.LineItems, NewCampaignLineItem),
+		Invoices:  mapSlice(c.Invoices, NewInvoice),
+	}
+}
+
+func (c *ExpandedCampaign) Model() *models.ExpandedCampaign {
+	return &models.ExpandedCampaign{
+		Campaign:  *c.Campaign.Model(),
+		LineItems: mapSlice(c.LineItems, (*CampaignLineItem).Model),
+		Invoices:  mapSlice(c.Invoices, (*Invoice).Model),
+	}
+}
+
+type CloneCampaignRequest struct {
+	Name         string `json:"name"`
+	OffsetDays   int    `json:"offset_days"`
+	ResetActuals bool   `json:"reset_actuals"`
+}
+
+func NewCloneCampaignRequest(r *models.CloneCampaignRequest) *CloneCampaignRequest {
+	return &CloneCampaignRequest{Name: r.Name, OffsetDays: r.OffsetDays, ResetActuals: r.ResetActuals}
+}
+
+func (r *CloneCampaignRequest) Model() *models.CloneCampaignRequest {
+	return &models.CloneCampaignRequest{Name: r.Name, OffsetDays: r.OffsetDays, ResetActuals: r.ResetActuals}
+}
+
+type CampaignSummary struct {
+	CampaignID       int     `json:"campaign_id"`
+	LineItemCount    int     `json:"line_item_count"`
+	TotalBooked      float64 `json:"total_booked"`
+	TotalActual      float64 `json:"total_actual"`
+	TotalAdjustments float64 `json:"total_adjustments"`
+	Variance         float64 `json:"variance"`
+	PercentDelivered float64 `json:"percent_delivered"`
+	InvoicedToDate   float64 `json:"invoiced_to_date"`
+	Uninvoiced       float64 `json:"uninvoiced"`
+}
+
+func NewCampaignSummary(s *models.CampaignSummary) *CampaignSummary {
+	return &CampaignSummary{
+		CampaignID:       s.CampaignID,
+		LineItemCount:    s.LineItemCount,
+		TotalBooked:      s.TotalBooked,
+		TotalActual:      s.TotalActual,
+		TotalAdjustments: s.TotalAdjustments,
+		Variance:         s.Variance,
+		PercentDelivered: s.PercentDelivered,
+		InvoicedToDate:   s.InvoicedToDate,
+		Uninvoiced:       s.Uninvoiced,
+	}
+}
+
+func (s *CampaignSummary) Model() *models.CampaignSummary {
+	return &models.CampaignSummary{
+		CampaignID:       s.CampaignID,
+		LineItemCount:    s.LineItemCount,
+		TotalBooked:      s.TotalBooked,
+		TotalActual:      s.TotalActual,
+		TotalAdjustments: s.TotalAdjustments,
+		Variance:         s.Variance,
+		PercentDelivered: s.PercentDelivered,
+		InvoicedToDate:   s.InvoicedToDate,
+		Uninvoiced:       s.Uninvoiced,
+	}
+}
+
+type CampaignDependents struct {
+	LineItems      int `json:"line_items"`
+	DraftInvoices  int `json:"draft_invoices"`
+	IssuedInvoices int `json:"issued_invoices"`
+}
+
+func NewCampaignDependents(d *models.CampaignDependents) *CampaignDependents {
+	if d == nil {
+		return nil
+	}
+
+	return &CampaignDependents{
+		LineItems:      d.LineItems,
+		DraftInvoices:  d.DraftInvoices,
+		IssuedInvoices: d.IssuedInvoices,
+	}
+}
+
+func (d *CampaignDependents) Model() *models.CampaignDependents {
+	if d == nil {
+		return nil
+	}
+
+	return &models.CampaignDependents{
+		LineItems:      d.LineItems,
+		DraftInvoices:  d.DraftInvoices,
+		IssuedInvoices: d.IssuedInvoices,
+	}
+}
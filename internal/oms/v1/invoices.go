@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+type Invoice struct {
+	ID                int        `json:"id"`
+	CampaignID        int        `json:"campaign_id"`
+	TotalBookedAmount float64    `json:"total_booked_amount"`
+	TotalActualAmount float64    `json:"total_actual_amount"`
+	TotalAdjustments  float64    `json:"total_adjustments"`
+	StartedAt         *time.Time `json:"started_at"`
+	EndedAt           *time.Time `json:"ended_at"`
+	// IssuedAt is null while the invoice is a draft.
+	IssuedAt    *time.Time `json:"issued_at"`
+	CollectedAt *time.Time `json:"collected_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+	// Version is served as the ETag of the invoice.
+	Version int `json:"version"`
+}
+
+func NewInvoice(i *models.Invoice) *Invoice {
+	invoice := &Invoice{
+		ID:                i.ID,
+		CampaignID:        i.CampaignID,
+		TotalBookedAmount: i.TotalBookedAmount,
+		TotalActualAmount: i.TotalActualAmount,
+		TotalAdjustments:  i.TotalAdjustments,
+		StartedAt:         i.StartedAt,
+		EndedAt:           i.EndedAt,
+		CollectedAt:       i.CollectedAt,
+		CreatedAt:         i.CreatedAt,
+		UpdatedAt:         i.UpdatedAt,
+		DeletedAt:         i.DeletedAt,
+		Version:           i.Version,
+	}
+
+	if !i.IssuedAt.IsZero() {
+		issuedAt := i.IssuedAt
+		invoice.IssuedAt = &issuedAt
+	}
+
+	return invoice
+}
+
+func (i *Invoice) Model() *models.Invoice {
+	invoice := &models.Invoice{
+		ID:                i.ID,
+		CampaignID:        i.CampaignID,
+		TotalBookedAmount: i.TotalBookedAmount,
+		TotalActualAmount: i.TotalActualAmount,
+		TotalAdjustments:  i.TotalAdjustments,
+		StartedAt:         i.StartedAt,
+		EndedAt:           i.EndedAt,
+		CollectedAt:       i.CollectedAt,
+		CreatedAt:         i.CreatedAt,
+		UpdatedAt:         i.UpdatedAt,
+		DeletedAt:         i.DeletedAt,
+		Version:           i.Version,
+	}
+
+	if i.IssuedAt != nil {
+		invoice.IssuedAt = *i.IssuedAt
+	}
+
+	return invoice
+}
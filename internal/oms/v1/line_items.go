@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+type CampaignLineItem struct {
+	ID             int        `json:"id"`
+	CampaignID     int        `json:"campaign_id"`
+	Name           string     `json:"name"`
+	Booked         float64    `json:"booked"`
+	Actual         float64    `json:"actual"`
+	Adjustments    float64    `json:"adjustments"`
+	StartedAt      *time.Time `json:"started_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+	PricingModel   string     `json:"pricing_model"`
+	UnitRate       float64    `json:"unit_rate"`
+	BookedUnits    int64      `json:"booked_units"`
+	DeliveredUnits int64      `json:"delivered_units"`
+	LockedAt       *time.Time `json:"locked_at"`
+	// Version is served as the ETag of the line item.
+	Version int `json:"version"`
+}
+
+func NewCampaignLineItem(l *models.CampaignLineItem) *CampaignLineItem {
+	return &CampaignLineItem{
+		ID:             l.ID,
+		CampaignID:     l.CampaignID,
+		Name:           l.Name,
+		Booked:         l.Booked,
+		Actual:         l.Actual,
+		Adjustments:    l.Adjustments,
+		StartedAt:      l.StartedAt,
+		EndedAt:        l.EndedAt,
+		CreatedAt:      l.CreatedAt,
+		UpdatedAt:      l.UpdatedAt,
+		DeletedAt:      l.DeletedAt,
+		PricingModel:   l.PricingModel,
+		UnitRate:       l.UnitRate,
+		BookedUnits:    l.BookedUnits,
+		DeliveredUnits: l.DeliveredUnits,
+		LockedAt:       l.LockedAt,
+		Version:        l.Version,
+	}
+}
+
+func (l *CampaignLineItem) Model() *models.CampaignLineItem {
+	return &models.CampaignLineItem{
+		ID:             l.ID,
+		CampaignID:     l.CampaignID,
+		Name:           l.Name,
+		Booked:         l.Booked,
+		Actual:         l.Actual,
+		Adjustments:    l.Adjustments,
+		StartedAt:      l.StartedAt,
+		EndedAt:        l.EndedAt,
+		CreatedAt:      l.CreatedAt,
+		UpdatedAt:      l.UpdatedAt,
+		DeletedAt:      l.DeletedAt,
+		PricingModel:   l.PricingModel,
+		UnitRate:       l.UnitRate,
+		BookedUnits:    l.BookedUnits,
+		DeliveredUnits: l.DeliveredUnits,
+		LockedAt:       l.LockedAt,
+		Version:        l.Version,
+	}
+}
+
+type CampaignLineItemOperation struct {
+	Op       string            `json:"op"`
+	ID       int               `json:"id"`
+	Version  int               `json:"version"`
+	LineItem *CampaignLineItem `json:"line_item"`
+}
+
+func NewCampaignLineItemOperation(o *models.CampaignLineItemOperation) *CampaignLineItemOperation {
+	op := &CampaignLineItemOperation{Op: o.Op, ID: o.ID, Version: o.Version}
+	if o.LineItem != nil {
+		op.LineItem = NewCampaignLineItem(o.LineItem)
+	}
+
+	return op
+}
+
+func (o *CampaignLineItemOperation) Model() *models.CampaignLineItemOperation {
+	op := &models.CampaignLineItemOperation{Op: o.Op, ID: o.ID, Version: o.Version}
+	if o.LineItem != nil {
+		op.LineItem = o.LineItem.Model()
+	}
+
+	return op
+}
+
+type CampaignLineItemOperationResult struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	ID     int           `json:"id"`
+	Status int           `json:"status"`
+	Error  string        `json:"error,omitempty"`
+	Fields []*FieldError `json:"fields,omitempty"`
+}
+
+func NewCampaignLineItemOperationResult(r *models.CampaignLineItemOperationResult) *CampaignLineItemOperationResult {
+	return &CampaignLineItemOperationResult{
+		Index:  r.Index,
+		Op:     r.Op,
+		ID:     r.ID,
+		Status: r.Status,
+		Error:  r.Error,
+		Fields: newFieldErrors(r.Fields),
+	}
+}
+
+func (r *CampaignLineItemOperationResult) Model() *models.CampaignLineItemOperationResult {
+	return &models.CampaignLineItemOperationResult{
+		Index:  r.Index,
+		Op:     r.Op,
+		ID:     r.ID,
+		Status: r.Status,
+		Error:  r.Error,
+		Fields: fieldErrorModels(r.Fields),
+	}
+}
+
+type CampaignLineItemBatchResult struct {
+	Results []*CampaignLineItemOperationResult `json:"results"`
+}
+
+func NewCampaignLineItemBatchResult(r *models.CampaignLineItemBatchResult) *CampaignLineItemBatchResult {
+	return &CampaignLineItemBatchResult{Results: mapSlice(r.Results, NewCampaignLineItemOperationResult)}
+}
+
+func (r *CampaignLineItemBatchResult) Model() *models.CampaignLineItemBatchResult {
+	return &models.CampaignLineItemBatchResult{
+		Results: mapSlice(r.Results, (*CampaignLineItemOperationResult).Model),
+	}
+}
+
+type MoveCampaignLineItemRequest struct {
+	CampaignID int    `json:"campaign_id"`
+	Reason     string `json:"reason"`
+}
+
+func NewMoveCampaignLineItemRequest(r *models.MoveCampaignLineItemRequest) *MoveCampaignLineItemRequest {
+	return &MoveCampaignLineItemRequest{CampaignID: r.CampaignID, Reason: r.Reason}
+}
+
+func (r *MoveCampaignLineItemRequest) Model() *models.MoveCampaignLineItemRequest {
+	return &models.MoveCampaignLineItemRequest{CampaignID: r.CampaignID, Reason: r.Reason}
+}
+
+type UnlockCampaignLineItemRequest struct {
+	Reason string `json:"reason"`
+}
+
+func NewUnlockCampaignLineItemRequest(r *models.UnlockCampaignLineItemRequest) *UnlockCampaignLineItemRequest {
+	return &UnlockCampaignLineItemRequest{Reason: r.Reason}
+}
+
+func (r *UnlockCampaignLineItemRequest) Model() *models.UnlockCampaignLineItemRequest {
+	return &models.UnlockCampaignLineItemRequest{Reason: r.Reason}
+}
+
+type LineItemHistoryEntry struct {
+	ID             int       `json:"id"`
+	LineItemID     int       `json:"line_item_id"`
+	Event          string    `json:"event"`
+	FromCampaignID *int      `json:"from_campaign_id"`
+	ToCampaignID   *int      `json:"to_campaign_id"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func NewLineItemHistoryEntry(h *models.LineItemHistoryEntry) *LineItemHistoryEntry {
+	return &LineItemHistoryEntry{
+		ID:             h.ID,
+		LineItemID:     h.LineItemID,
+		Event:          h.Event,
+		FromCampaignID: h.FromCampaignID,
+		ToCampaignID:   h.ToCampaignID,
+		Reason:         h.Reason,
+		CreatedAt:      h.CreatedAt,
+	}
+}
+
+func (h *LineItemHistoryEntry) Model() *models.LineItemHistoryEntry {
+	return &models.LineItemHistoryEntry{
+		ID:             h.ID,
+		LineItemID:     h.LineItemID,
+		Event:          h.Event,
+		FromCampaignID: h.FromCampaignID,
+		ToCampaignID:   h.ToCampaignID,
+		Reason:         h.Reason,
+		CreatedAt:      h.CreatedAt,
+	}
+}
+
+type LineItemDelivery struct {
+	LineItemID  int       `json:"line_item_id"`
+	Date        time.Time `json:"date"`
+	Impressions int64     `json:"impressions"`
+	Clicks      int64     `json:"clicks"`
+	Conversions int64     `json:"conversions"`
+	Spend       float64   `json:"spend"`
+}
+
+func NewLineItemDelivery(d *models.LineItemDelivery) *LineItemDelivery {
+	return &LineItemDelivery{
+		LineItemID:  d.LineItemID,
+		Date:        d.Date,
+		Impressions: d.Impressions,
+		Clicks:      d.Clicks,
+		Conversions: d.Conversions,
+		Spend:       d.Spend,
+	}
+}
+
+func (d *LineItemDelivery) Model() *models.LineItemDelivery {
+	return &models.LineItemDelivery{
+		LineItemID:  d.LineItemID,
+		Date:        d.Date,
+		Impressions: d.Impressions,
+		Clicks:      d.Clicks,
+		Conversions: d.Conversions,
+		Spend:       d.Spend,
+	}
+}
+
+type DeliveryIngestResult struct {
+	Records   int `json:"records"`
+	LineItems int `json:"line_items"`
+}
+
+func NewDeliveryIngestResult(r *models.DeliveryIngestResult) *DeliveryIngestResult {
+	return &DeliveryIngestResult{Records: r.Records, LineItems: r.LineItems}
+}
+
+func (r *DeliveryIngestResult) Model() *models.DeliveryIngestResult {
+	return &models.DeliveryIngestResult{Records: r.Records, LineItems: r.LineItems}
+}
@@ -0,0 +1,228 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+type CommissionPlan struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Basis       string    `json:"basis"`
+	RatePercent float64   `json:"rate_percent"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewCommissionPlan(p *models.CommissionPlan) *CommissionPlan {
+	return &CommissionPlan{
+		ID:          p.ID,
+		Name:        p.Name,
+		Basis:       p.Basis,
+		RatePercent: p.RatePercent,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+func (p *CommissionPlan) Model() *models.CommissionPlan {
+	return &models.CommissionPlan{
+		ID:          p.ID,
+		Name:        p.Name,
+		Basis:       p.Basis,
+		RatePercent: p.RatePercent,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+type SalesRep struct {
+	ID               int       `json:"id"`
+	Name             string    `json:"name"`
+	Email            string    `json:"email"`
+	CommissionPlanID *int      `json:"commission_plan_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Version          int       `json:"version"`
+}
+
+func NewSalesRep(r *models.SalesRep) *SalesRep {
+	return &SalesRep{
+		ID:               r.ID,
+		Name:             r.Name,
+		Email:            r.Email,
+		CommissionPlanID: r.CommissionPlanID,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		Version:          r.Version,
+	}
+}
+
+func (r *SalesRep) Model() *models.SalesRep {
+	return &models.SalesRep{
+		ID:               r.ID,
+		Name:             r.Name,
+		Email:            r.Email,
+		CommissionPlanID: r.CommissionPlanID,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		Version:          r.Version,
+	}
+}
+
+type CampaignOwner struct {
+	SalesRepID   int     `json:"sales_rep_id"`
+	SalesRepName string  `json:"sales_rep_name"`
+	SplitPercent float64 `json:"split_percent"`
+}
+
+func NewCampaignOwner(o *models.CampaignOwner) *CampaignOwner {
+	return &CampaignOwner{SalesRepID: o.SalesRepID, SalesRepName: o.SalesRepName, SplitPercent: o.SplitPercent}
+}
+
+func (o *CampaignOwner) Model() *models.CampaignOwner {
+	return &models.CampaignOwner{SalesRepID: o.SalesRepID, SalesRepName: o.SalesRepName, SplitPercent: o.SplitPercent}
+}
+
+type CommissionReport struct {
+	Period          string                  `json:"period"`
+	PeriodStart     time.Time               `json:"period_start"`
+	PeriodEnd       time.Time               `json:"period_end"`
+	Lines           []*CommissionReportLine `json:"lines"`
+	TotalRevenue    float64                 `json:"total_revenue"`
+	TotalCommission float64                 `json:"total_commission"`
+}
+
+func NewCommissionReport(r *models.CommissionReport) *CommissionReport {
+	return &CommissionReport{
+		Period:          r.Period,
+		PeriodStart:     r.PeriodStart,
+		PeriodEnd:       r.PeriodEnd,
+		Lines:           mapSlice(r.Lines, NewCommissionReportLine),
+		TotalRevenue:    r.TotalRevenue,
+		TotalCommission: r.TotalCommission,
+	}
+}
+
+func (r *CommissionReport) Model() *models.CommissionReport {
+	return &models.CommissionReport{
+		Period:          r.Period,
+		PeriodStart:     r.PeriodStart,
+		PeriodEnd:       r.PeriodEnd,
+		Lines:           mapSlice(r.Lines, (*CommissionReportLine).Model),
+		TotalRevenue:    r.TotalRevenue,
+		TotalCommission: r.TotalCommission,
+	}
+}
+
+type CommissionReportLine struct {
+	SalesRepID         int     `json:"sales_rep_id"`
+	SalesRepName       string  `json:"sales_rep_name"`
+	CommissionPlanID   int     `json:"commission_plan_id"`
+	CommissionPlanName string  `json:"commission_plan_name"`
+	Basis              string  `json:"basis"`
+	RatePercent        float64 `json:"rate_percent"`
+	InvoiceCount       int     `json:"invoice_count"`
+	Revenue            float64 `json:"revenue"`
+	Commission         float64 `json:"commission"`
+}
+
+func NewCommissionReportLine(l *models.CommissionReportLine) *CommissionReportLine {
+	return &CommissionReportLine{
+		SalesRepID:         l.SalesRepID,
+		SalesRepName:       l.SalesRepName,
+		CommissionPlanID:   l.CommissionPlanID,
+		CommissionPlanName: l.CommissionPlanName,
+		Basis:              l.Basis,
+		RatePercent:        l.RatePercent,
+		InvoiceCount:       l.InvoiceCount,
+		Revenue:            l.Revenue,
+		Commission:         l.Commission,
+	}
+}
+
+func (l *CommissionReportLine) Model() *models.CommissionReportLine {
+	return &models.CommissionReportLine{
+		SalesRepID:         l.SalesRepID,
+		SalesRepName:       l.SalesRepName,
+		CommissionPlanID:   l.CommissionPlanID,
+		CommissionPlanName: l.CommissionPlanName,
+		Basis:              l.Basis,
+		RatePercent:        l.RatePercent,
+		InvoiceCount:       l.InvoiceCount,
+		Revenue:            l.Revenue,
+		Commission:         l.Commission,
+	}
+}
+
+type VarianceReport struct {
+	Threshold        float64               `json:"threshold"`
+	ThresholdPercent bool                  `json:"threshold_percent"`
+	GroupBy          string                `json:"group_by"`
+	Sort             string                `json:"sort"`
+	Items            []*VarianceReportItem `json:"items"`
+}
+
+func NewVarianceReport(r *models.VarianceReport) *VarianceReport {
+	return &VarianceReport{
+		Threshold:        r.Threshold,
+		ThresholdPercent: r.ThresholdPercent,
+		GroupBy:          r.GroupBy,
+		Sort:             r.Sort,
+		Items:            mapSlice(r.Items, NewVarianceReportItem),
+	}
+}
+
+func (r *VarianceReport) Model() *models.VarianceReport {
+	return &models.VarianceReport{
+		Threshold:        r.Threshold,
+		ThresholdPercent: r.ThresholdPercent,
+		GroupBy:          r.GroupBy,
+		Sort:             r.Sort,
+		Items:            mapSlice(r.Items, (*VarianceReportItem).Model),
+	}
+}
+
+type VarianceReportItem struct {
+	CampaignID      int      `json:"campaign_id"`
+	CampaignName    string   `json:"campaign_name"`
+	LineItemID      int      `json:"line_item_id,omitempty"`
+	LineItemName    string   `json:"line_item_name,omitempty"`
+	LineItemCount   int      `json:"line_item_count,omitempty"`
+	Booked          float64  `json:"booked"`
+	Actual          float64  `json:"actual"`
+	Variance        float64  `json:"variance"`
+	VariancePercent *float64 `json:"variance_percent"`
+	Status          string   `json:"status"`
+}
+
+func NewVarianceReportItem(i *models.VarianceReportItem) *VarianceReportItem {
+	return &VarianceReportItem{
+		CampaignID:      i.CampaignID,
+		CampaignName:    i.CampaignName,
+		LineItemID:      i.LineItemID,
+		LineItemName:    i.LineItemName,
+		LineItemCount:   i.LineItemCount,
+		Booked:          i.Booked,
+		Actual:          i.Actual,
+		Variance:        i.Variance,
+		VariancePercent: i.VariancePercent,
+		Status:          i.Status,
+	}
+}
+
+func (i *VarianceReportItem) Model() *models.VarianceReportItem {
+	return &models.VarianceReportItem{
+		CampaignID:      i.CampaignID,
+		CampaignName:    i.CampaignName,
+		LineItemID:      i.LineItemID,
+		LineItemName:    i.LineItemName,
+		LineItemCount:   i.LineItemCount,
+		Booked:          i.Booked,
+		Actual:          i.Actual,
+		Variance:        i.Variance,
+		VariancePercent: i.VariancePercent,
+		Status:          i.Status,
+	}
+}
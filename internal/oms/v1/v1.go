@@ -0,0 +1,418 @@
+// Package v1 is the wire format of the /v1 API: snake_case request and response
+// bodies that change only with a new version of the API, whatever happens to the
+// models and the database. Every mapping between the models and the wire is in
+// this package, the server and the client both go through Encode and Decode.
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+// BasePath is the prefix of every route of the version.
+const BasePath = "/v1"
+
+// List is a page of a list endpoint.
+type List[T any] struct {
+	Items             []*T   `json:"items"`
+	NextPageToken     string `json:"next_page_token,omitempty"`
+	PreviousPageToken string `json:"previous_page_token,omitempty"`
+	// TotalCount is only set when the request asked for it with $count=true.
+	TotalCount *int64 `json:"total_count,omitempty"`
+}
+
+func NewList[M, D any](l *models.List[M], item func(*M) *D) *List[D] {
+	return &List[D]{
+		Items:             mapSlice(l.Items, item),
+		NextPageToken:     l.NextPageToken,
+		PreviousPageToken: l.PreviousPageToken,
+		TotalCount:        l.TotalCount,
+	}
+}
+
+func listModel[D, M any](l *List[D], item func(*D) *M) *models.List[M] {
+	return &models.List[M]{
+		Items:             mapSlice(l.Items, item),
+		NextPageToken:     l.NextPageToken,
+		PreviousPageToken: l.PreviousPageToken,
+		TotalCount:        l.TotalCount,
+	}
+}
+
+// FieldError names a rejected field by its key in the request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func newFieldErrors(fieldErrors []models.FieldError) []*FieldError {
+	if fieldErrors == nil {
+		return nil
+	}
+
+	out := make([]*FieldError, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		out[i] = &FieldError{Field: FieldName(fieldError.Field), Message: fieldError.Message}
+	}
+
+	return out
+}
+
+// fieldErrorModels keeps the wire names of the fields, the models have no others.
+func fieldErrorModels(fieldErrors []*FieldError) []models.FieldError {
+	if fieldErrors == nil {
+		return nil
+	}
+
+	out := make([]models.FieldError, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		out[i] = models.FieldError{Field: fieldError.Field, Message: fieldError.Message}
+	}
+
+	return out
+}
+
+// Problem is an RFC 7807 problem details body, see models.Problem.
+type Problem struct {
+	Type       string                             `json:"type"`
+	Title      string                             `json:"title"`
+	Status     int                                `json:"status"`
+	Detail     string                             `json:"detail,omitempty"`
+	Instance   string                             `json:"instance,omitempty"`
+	Code       string                             `json:"code"`
+	RequestID  string                             `json:"request_id,omitempty"`
+	Errors     []*FieldError                      `json:"errors,omitempty"`
+	Dependents *CampaignDependents                `json:"dependents,omitempty"`
+	Results    []*CampaignLineItemOperationResult `json:"results,omitempty"`
+}
+
+func NewProblem(p *models.Problem) *Problem {
+	return &Problem{
+		Type:       p.Type,
+		Title:      p.Title,
+		Status:     p.Status,
+		Detail:     p.Detail,
+		Instance:   p.Instance,
+		Code:       p.Code,
+		RequestID:  p.RequestID,
+		Errors:     newFieldErrors(p.Errors),
+		Dependents: NewCampaignDependents(p.Dependents),
+		Results:    mapSlice(p.Results, NewCampaignLineItemOperationResult),
+	}
+}
+
+func (p *Problem) Model() *models.Problem {
+	return &models.Problem{
+		Type:       p.Type,
+		Title:      p.Title,
+		Status:     p.Status,
+		Detail:     p.Detail,
+		Instance:   p.Instance,
+		Code:       p.Code,
+		RequestID:  p.RequestID,
+		Errors:     fieldErrorModels(p.Errors),
+		Dependents: p.Dependents.Model(),
+		Results:    mapSlice(p.Results, (*CampaignLineItemOperationResult).Model),
+	}
+}
+
+// Encode returns the wire form of a model, or a list or slice of them. Ids and
+// other plain values are returned as they are. Any other type panics, encoding it
+// as it is would put the Go names of its fields on the wire.
+//
+//nolint:gocyclo // Why: one case for every type with a wire form.
+func Encode(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *models.Campaign:
+		return NewCampaign(v)
+	case *models.ExpandedCampaign:
+		return NewExpandedCampaign(v)
+	case *models.CloneCampaignRequest:
+		return NewCloneCampaignRequest(v)
+	case *models.CampaignSummary:
+		return NewCampaignSummary(v)
+	case *models.CampaignLineItem:
+		return NewCampaignLineItem(v)
+	case []*models.CampaignLineItemOperation:
+		return mapSlice(v, NewCampaignLineItemOperation)
+	case *models.CampaignLineItemBatchResult:
+		return NewCampaignLineItemBatchResult(v)
+	case *models.MoveCampaignLineItemRequest:
+		return NewMoveCampaignLineItemRequest(v)
+	case *models.UnlockCampaignLineItemRequest:
+		return NewUnlockCampaignLineItemRequest(v)
+	case []*models.LineItemDelivery:
+		return mapSlice(v, NewLineItemDelivery)
+	case *models.DeliveryIngestResult:
+		return NewDeliveryIngestResult(v)
+	case *models.Invoice:
+		return NewInvoice(v)
+	case *models.CommissionPlan:
+		return NewCommissionPlan(v)
+	case *models.SalesRep:
+		return NewSalesRep(v)
+	case []*models.CampaignOwner:
+		return mapSlice(v, NewCampaignOwner)
+	case *models.CommissionReport:
+		return NewCommissionReport(v)
+	case *models.VarianceReport:
+		return NewVarianceReport(v)
+	case *models.WebhookSubscription:
+		return NewWebhookSubscription(v)
+	case *models.WebhookEvent:
+		return NewWebhookEvent(v)
+	case *models.Problem:
+		return NewProblem(v)
+	case *models.List[models.Campaign]:
+		return NewList(v, NewCampaign)
+	case *models.List[json.RawMessage]:
+		// The items are already in their wire form, see the $select of campaigns.
+		return NewList(v, func(item *json.RawMessage) *json.RawMessage { return item })
+	case *models.List[models.CampaignLineItem]:
+		return NewList(v, NewCampaignLineItem)
+	case *models.List[models.LineItemHistoryEntry]:
+		return NewList(v, NewLineItemHistoryEntry)
+	case *models.List[models.LineItemDelivery]:
+		return NewList(v, NewLineItemDelivery)
+	case *models.List[models.Invoice]:
+		return NewList(v, NewInvoice)
+	case *models.List[models.CommissionPlan]:
+		return NewList(v, NewCommissionPlan)
+	case *models.List[models.SalesRep]:
+		return NewList(v, NewSalesRep)
+	case *models.List[models.CampaignOwner]:
+		return NewList(v, NewCampaignOwner)
+	case *models.List[models.WebhookSubscription]:
+		return NewList(v, NewWebhookSubscription)
+	case *models.List[models.WebhookDelivery]:
+		return NewList(v, NewWebhookDelivery)
+	case nil, bool, int, int32, int64, float64, *float64, string, json.RawMessage, *json.RawMessage:
+		return v
+	default:
+		panic(fmt.Sprintf("v1: %T has no wire form", v))
+	}
+}
+
+// Decode decodes the wire form of the model out points to into it. Ids and other
+// plain values are decoded as they are, any other type is an error.
+//
+//nolint:gocyclo // Why: one case for every type with a wire form.
+func Decode(data []byte, out interface{}) error {
+	switch out := out.(type) {
+	case *models.Campaign:
+		return decode(data, out, (*Campaign).Model)
+	case *models.ExpandedCampaign:
+		return decode(data, out, (*ExpandedCampaign).Model)
+	case *models.CloneCampaignRequest:
+		return decode(data, out, (*CloneCampaignRequest).Model)
+	case *models.CampaignSummary:
+		return decode(data, out, (*CampaignSummary).Model)
+	case *models.CampaignLineItem:
+		return decode(data, out, (*CampaignLineItem).Model)
+	case *[]*models.CampaignLineItemOperation:
+		return decodeSlice(data, out, (*CampaignLineItemOperation).Model)
+	case *models.CampaignLineItemBatchResult:
+		return decode(data, out, (*CampaignLineItemBatchResult).Model)
+	case *models.MoveCampaignLineItemRequest:
+		return decode(data, out, (*MoveCampaignLineItemRequest).Model)
+	case *models.UnlockCampaignLineItemRequest:
+		return decode(data, out, (*UnlockCampaignLineItemRequest).Model)
+	case *[]*models.LineItemDelivery:
+		return decodeSlice(data, out, (*LineItemDelivery).Model)
+	case *models.DeliveryIngestResult:
+		return decode(data, out, (*DeliveryIngestResult).Model)
+	case *models.Invoice:
+		return decode(data, out, (*Invoice).Model)
+	case *models.CommissionPlan:
+		return decode(data, out, (*CommissionPlan).Model)
+	case *models.SalesRep:
+		return decode(data, out, (*SalesRep).Model)
+	case *[]*models.CampaignOwner:
+		return decodeSlice(data, out, (*CampaignOwner).Model)
+	case *models.CommissionReport:
+		return decode(data, out, (*CommissionReport).Model)
+	case *models.VarianceReport:
+		return decode(data, out, (*VarianceReport).Model)
+	case *models.WebhookSubscription:
+		return decode(data, out, (*WebhookSubscription).Model)
+	case *models.WebhookEvent:
+		return decode(data, out, (*WebhookEvent).Model)
+	case *models.Problem:
+		return decode(data, out, (*Problem).Model)
+	case *models.List[models.Campaign]:
+		return decodeList(data, out, (*Campaign).Model)
+	case *models.List[models.ExpandedCampaign]:
+		return decodeList(data, out, (*ExpandedCampaign).Model)
+	case *models.List[models.CampaignLineItem]:
+		return decodeList(data, out, (*CampaignLineItem).Model)
+	case *models.List[models.LineItemHistoryEntry]:
+		return decodeList(data, out, (*LineItemHistoryEntry).Model)
+	case *models.List[models.LineItemDelivery]:
+		return decodeList(data, out, (*LineItemDelivery).Model)
+	case *models.List[models.Invoice]:
+		return decodeList(data, out, (*Invoice).Model)
+	case *models.List[models.CommissionPlan]:
+		return decodeList(data, out, (*CommissionPlan).Model)
+	case *models.List[models.SalesRep]:
+		return decodeList(data, out, (*SalesRep).Model)
+	case *models.List[models.CampaignOwner]:
+		return decodeList(data, out, (*CampaignOwner).Model)
+	case *models.List[models.WebhookSubscription]:
+		return decodeList(data, out, (*WebhookSubscription).Model)
+	case *models.List[models.WebhookDelivery]:
+		return decodeList(data, out, (*WebhookDelivery).Model)
+	case *bool, *int, *int32, *int64, *float64, *string, *json.RawMessage:
+		return json.Unmarshal(data, out)
+	default:
+		return fmt.Errorf("v1: %T has no wire form", out)
+	}
+}
+
+func decode[D, M any](data []byte, out *M, model func(*D) *M) error {
+	var dto D
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return err
+	}
+
+	*out = *model(&dto)
+
+	return nil
+}
+
+func decodeSlice[D, M any](data []byte, out *[]*M, model func(*D) *M) error {
+	var dtos []*D
+	if err := json.Unmarshal(data, &dtos); err != nil {
+		return err
+	}
+
+	*out = mapSlice(dtos, model)
+
+	return nil
+}
+
+func decodeList[D, M any](data []byte, out *models.List[M], model func(*D) *M) error {
+	var dto List[D]
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return err
+	}
+
+	*out = *listModel(&dto, model)
+
+	return nil
+}
+
+// mapSlice maps every element and keeps nil apart from empty, an expanded
+// relation without rows is an empty array on the wire.
+func mapSlice[From, To any](from []*From, f func(*From) *To) []*To {
+	if from == nil {
+		return nil
+	}
+
+	to := make([]*To, len(from))
+	for i, item := range from {
+		if item != nil {
+			to[i] = f(item)
+		}
+	}
+
+	return to
+}
+
+// FieldName turns the Go name of a model field into its key on the wire, CampaignID
+// becomes campaign_id. Paths like Owners[0].SplitPercent are turned segment by
+// segment. It names the fields of errors, the fields of a resource are mapped by
+// its WireNames.
+func FieldName(goName string) string {
+	runes := []rune(goName)
+
+	var name strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			afterWord := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			endsAcronym := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if afterWord || endsAcronym {
+				name.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		name.WriteRune(r)
+	}
+
+	return name.String()
+}
+
+// WireNames maps the fields of a resource between the Go names of its model and
+// their keys on the wire, as the json tags of its DTO name them. The fields of a
+// DTO are named like the model fields they carry.
+type WireNames struct {
+	keys    map[string]string
+	goNames map[string]string
+}
+
+// The wire names of the resources that take $filter, $orderby and merge patches.
+var (
+	CampaignNames         = newWireNames(reflect.TypeOf(Campaign{}))
+	CampaignLineItemNames = newWireNames(reflect.TypeOf(CampaignLineItem{}))
+	InvoiceNames          = newWireNames(reflect.TypeOf(Invoice{}))
+)
+
+func newWireNames(dto reflect.Type) *WireNames {
+	names := &WireNames{keys: map[string]string{}, goNames: map[string]string{}}
+
+	for i := 0; i < dto.NumField(); i++ {
+		field := dto.Field(i)
+
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+
+		names.keys[field.Name] = key
+		names.goNames[key] = field.Name
+	}
+
+	return names
+}
+
+// Key returns the key on the wire of a model field, ok is false when the DTO does
+// not carry the field.
+func (n *WireNames) Key(goName string) (key string, ok bool) {
+	key, ok = n.keys[goName]
+	return key, ok
+}
+
+// PatchFromWire renames the fields of a merge patch from their keys on the wire to
+// the Go names of the model fields, the values are kept as they are. Unknown keys
+// are kept as well so they are rejected as fields that cannot be patched.
+func (n *WireNames) PatchFromWire(patch map[string]interface{}) map[string]interface{} {
+	return renamePatch(patch, n.goNames)
+}
+
+// PatchToWire renames the fields of a merge patch from the Go names of the model
+// fields to their keys on the wire.
+func (n *WireNames) PatchToWire(patch map[string]interface{}) map[string]interface{} {
+	return renamePatch(patch, n.keys)
+}
+
+func renamePatch(patch map[string]interface{}, names map[string]string) map[string]interface{} {
+	renamed := make(map[string]interface{}, len(patch))
+
+	for key, value := range patch {
+		if name, ok := names[key]; ok {
+			key = name
+		}
+
+		renamed[key] = value
+	}
+
+	return renamed
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+)
+
+type WebhookSubscription struct {
+	ID         int      `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	// Secret is only returned when the subscription is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int       `json:"version"`
+}
+
+func NewWebhookSubscription(s *models.WebhookSubscription) *WebhookSubscription {
+	return &WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		Secret:     s.Secret,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+		Version:    s.Version,
+	}
+}
+
+func (s *WebhookSubscription) Model() *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		Secret:     s.Secret,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+		Version:    s.Version,
+	}
+}
+
+type WebhookDelivery struct {
+	ID             int        `json:"id"`
+	SubscriptionID int        `json:"subscription_id"`
+	EventID        int        `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	ResponseStatus *int       `json:"response_status"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func NewWebhookDelivery(d *models.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+func (d *WebhookDelivery) Model() *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// WebhookEvent is the body of every webhook delivery.
+type WebhookEvent struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func NewWebhookEvent(e *models.WebhookEvent) *WebhookEvent {
+	return &WebhookEvent{ID: e.ID, Type: e.Type, CreatedAt: e.CreatedAt, Data: e.Data}
+}
+
+func (e *WebhookEvent) Model() *models.WebhookEvent {
+	return &models.WebhookEvent{ID: e.ID, Type: e.Type, CreatedAt: e.CreatedAt, Data: e.Data}
+}
@@ -0,0 +1,135 @@
+package oms
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	errInvalidThreshold = errors.New("threshold must be a non negative amount, or a percentage such as 5%")
+	errInvalidGroupBy   = errors.New("groupBy must be lineItem or campaign")
+	errInvalidSort      = errors.New("sort must be absolute or percent")
+	errInvalidStatus    = errors.New("status must be under or over")
+)
+
+// varianceReportOptions are the query parameters of the variance report.
+type varianceReportOptions struct {
+	threshold        float64
+	thresholdPercent bool
+	groupBy          string
+	sort             string
+	status           string
+}
+
+func newVarianceReportOptions(threshold, groupBy, sort, status string) (*varianceReportOptions, error) {
+	opts := &varianceReportOptions{groupBy: groupBy, sort: sort, status: status}
+
+	if threshold == "" {
+		threshold = "0"
+	}
+
+	amount, isPercent := strings.CutSuffix(threshold, "%")
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil || value < 0 {
+		return nil, errInvalidThreshold
+	}
+
+	opts.threshold = value
+	opts.thresholdPercent = isPercent
+
+	if opts.groupBy == "" {
+		opts.groupBy = models.VarianceGroupByLineItem
+	}
+
+	if opts.groupBy != models.VarianceGroupByLineItem && opts.groupBy != models.VarianceGroupByCampaign {
+		return nil, errInvalidGroupBy
+	}
+
+	if opts.sort == "" {
+		opts.sort = models.VarianceSortAbsolute
+	}
+
+	if opts.sort != models.VarianceSortAbsolute && opts.sort != models.VarianceSortPercent {
+		return nil, errInvalidSort
+	}
+
+	if opts.status != "" && opts.status != models.VarianceUnder && opts.status != models.VarianceOver {
+		return nil, errInvalidStatus
+	}
+
+	return opts, nil
+}
+
+// buildVarianceReport lists what under or over delivered by more than the threshold.
+func buildVarianceReport(ctx context.Context, dbQueries *db.Queries,
+	opts *varianceReportOptions) (*models.VarianceReport, error) {
+	report := &models.VarianceReport{
+		Threshold:        opts.threshold,
+		ThresholdPercent: opts.thresholdPercent,
+		GroupBy:          opts.groupBy,
+		Sort:             opts.sort,
+		Items:            []*models.VarianceReportItem{},
+	}
+
+	items, err := listVarianceItems(ctx, dbQueries, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if opts.status == "" || item.Status == opts.status {
+			report.Items = append(report.Items, item)
+		}
+	}
+
+	report.SortItems()
+
+	return report, nil
+}
+
+func listVarianceItems(ctx context.Context, dbQueries *db.Queries,
+	opts *varianceReportOptions) ([]*models.VarianceReportItem, error) {
+	threshold := strconv.FormatFloat(opts.threshold, 'f', -1, 64)
+
+	if opts.groupBy == models.VarianceGroupByCampaign {
+		rows, err := dbQueries.ListCampaignVariance(ctx, db.ListCampaignVarianceParams{
+			Percent:   opts.thresholdPercent,
+			Threshold: threshold,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot list campaign variance")
+		}
+
+		items := make([]*models.VarianceReportItem, len(rows))
+		for i := range rows {
+			if items[i], err = models.NewCampaignVarianceFromDB(&rows[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		return items, nil
+	}
+
+	rows, err := dbQueries.ListLineItemVariance(ctx, db.ListLineItemVarianceParams{
+		Percent:   opts.thresholdPercent,
+		Threshold: threshold,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list line item variance")
+	}
+
+	items := make([]*models.VarianceReportItem, len(rows))
+	for i := range rows {
+		if items[i], err = models.NewLineItemVarianceFromDB(&rows[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
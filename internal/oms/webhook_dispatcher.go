@@ -0,0 +1,231 @@
+package oms
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWebhookPollInterval = 5 * time.Second
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookRetryBackoff = 30 * time.Second
+	defaultWebhookMaxAttempts  = 8
+	maxWebhookRetryBackoff     = 6 * time.Hour
+	webhookBatchSize           = 50
+	maxWebhookErrorLength      = 1000
+	maxWebhookResponseBytes    = 64 << 10
+)
+
+// webhookDispatcher sends the pending webhook deliveries. A failed delivery is
+// retried with an exponential backoff until it runs out of attempts and is dead.
+type webhookDispatcher struct {
+	dbQueries   *db.Queries
+	logger      *slog.Logger
+	client      *http.Client
+	interval    time.Duration
+	backoff     time.Duration
+	maxAttempts int
+}
+
+// newWebhookDispatcherFromEnv configures the dispatcher from OMS_WEBHOOK_POLL_INTERVAL,
+// OMS_WEBHOOK_TIMEOUT and OMS_WEBHOOK_RETRY_BACKOFF, parsed as go durations, and
+// from OMS_WEBHOOK_MAX_ATTEMPTS.
+func newWebhookDispatcherFromEnv(logger *slog.Logger, dbQueries *db.Queries) (*webhookDispatcher, error) {
+	interval, err := durationFromEnv("OMS_WEBHOOK_POLL_INTERVAL", defaultWebhookPollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := durationFromEnv("OMS_WEBHOOK_TIMEOUT", defaultWebhookTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff, err := durationFromEnv("OMS_WEBHOOK_RETRY_BACKOFF", defaultWebhookRetryBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts := defaultWebhookMaxAttempts
+	if value := os.Getenv("OMS_WEBHOOK_MAX_ATTEMPTS"); value != "" {
+		maxAttempts, err = strconv.Atoi(value)
+		if err != nil || maxAttempts < 1 {
+			return nil, errors.Errorf("OMS_WEBHOOK_MAX_ATTEMPTS must be a positive number, got %q", value)
+		}
+	}
+
+	return &webhookDispatcher{
+		dbQueries:   dbQueries,
+		logger:      logger,
+		client:      &http.Client{Timeout: timeout},
+		interval:    interval,
+		backoff:     backoff,
+		maxAttempts: maxAttempts,
+	}, nil
+}
+
+// Run sends the due deliveries every interval until the context is cancelled.
+func (d *webhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch sends the due deliveries batch by batch until none is left. Claiming a
+// delivery leases it for twice the timeout, so another server does not send it
+// meanwhile and a server that stops while sending leaves it to be sent again. The
+// deliveries of a batch are sent at once, one after the other they would outlast
+// the lease and be sent twice.
+func (d *webhookDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.dbQueries.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+			LeaseUntil: time.Now().UTC().Add(2 * d.client.Timeout),
+			BatchSize:  webhookBatchSize,
+		})
+		if err != nil {
+			d.logger.Error("Error claiming webhook deliveries", slog.String("error", err.Error()))
+			return
+		}
+
+		var wg sync.WaitGroup
+
+		for i := range deliveries {
+			wg.Add(1)
+
+			go func(delivery *db.ClaimWebhookDeliveriesRow) {
+				defer wg.Done()
+
+				d.deliver(ctx, delivery)
+			}(&deliveries[i])
+		}
+
+		wg.Wait()
+
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// deliver sends a delivery once and records the outcome. Any 2xx answer delivers it.
+func (d *webhookDispatcher) deliver(ctx context.Context, delivery *db.ClaimWebhookDeliveriesRow) {
+	status, sendErr := d.send(ctx, delivery)
+
+	responseStatus := sql.NullInt32{Valid: status != 0, Int32: int32(status)}
+
+	var err error
+
+	if sendErr == nil {
+		err = d.dbQueries.MarkWebhookDeliveryDelivered(ctx, db.MarkWebhookDeliveryDeliveredParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+		})
+	} else {
+		attempts := int(delivery.Attempts) + 1
+		params := db.MarkWebhookDeliveryFailedParams{
+			ID:             delivery.ID,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now().UTC().Add(d.retryBackoff(attempts)),
+			ResponseStatus: responseStatus,
+			LastError:      sql.NullString{Valid: true, String: truncate(sendErr.Error(), maxWebhookErrorLength)},
+		}
+
+		if attempts >= d.maxAttempts {
+			params.Status = models.WebhookDeliveryDead
+		}
+
+		d.logger.Warn("Webhook delivery failed",
+			slog.Int64("delivery", delivery.ID),
+			slog.Int("attempts", attempts),
+			slog.String("status", params.Status),
+			slog.String("error", sendErr.Error()))
+
+		err = d.dbQueries.MarkWebhookDeliveryFailed(ctx, params)
+	}
+
+	if err != nil {
+		d.logger.Error("Error recording webhook delivery", slog.Int64("delivery", delivery.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// send posts the signed event to the subscription and returns the status of the answer.
+func (d *webhookDispatcher) send(ctx context.Context, delivery *db.ClaimWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(v1.NewWebhookEvent(&models.WebhookEvent{
+		ID:        int(delivery.EventID),
+		Type:      delivery.EventType,
+		CreatedAt: delivery.EventCreatedAt.Time,
+		Data:      delivery.Payload,
+	}))
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot encode the event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(models.WebhookEventHeader, delivery.EventType)
+	req.Header.Set(models.WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(models.WebhookSignatureHeader, models.SignWebhook(delivery.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Reading the body lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseBytes))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("the receiver answered %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// retryBackoff doubles the backoff with every failed attempt, up to six hours.
+func (d *webhookDispatcher) retryBackoff(attempts int) time.Duration {
+	backoff := d.backoff
+
+	for i := 1; i < attempts && backoff < maxWebhookRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxWebhookRetryBackoff)
+}
+
+func truncate(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+
+	return s[:maxLength]
+}
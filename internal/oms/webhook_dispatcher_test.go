@@ -0,0 +1,195 @@
+package oms
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "test-secret"
+
+// recordingDB records the statements run with ExecContext, the only method the
+// dispatcher uses to record the outcome of a delivery.
+type recordingDB struct {
+	mu    sync.Mutex
+	execs []recordedExec
+}
+
+type recordedExec struct {
+	name string
+	args []interface{}
+}
+
+func (r *recordingDB) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name, _, _ := strings.Cut(strings.TrimPrefix(query, "-- name: "), " ")
+	r.execs = append(r.execs, recordedExec{name: name, args: args})
+
+	return driver.RowsAffected(1), nil
+}
+
+func (r *recordingDB) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (r *recordingDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (r *recordingDB) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+// testReceiver answers every delivery with its status and keeps the event types
+// and whether the signatures verified.
+type testReceiver struct {
+	*httptest.Server
+	mu         sync.Mutex
+	eventTypes []string
+	signatures []error
+}
+
+func newTestReceiver(t *testing.T, status int) *testReceiver {
+	t.Helper()
+
+	receiver := &testReceiver{}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receiver.mu.Lock()
+		receiver.eventTypes = append(receiver.eventTypes, r.Header.Get(models.WebhookEventHeader))
+		receiver.signatures = append(receiver.signatures, models.VerifyWebhookSignature(testWebhookSecret,
+			r.Header.Get(models.WebhookSignatureHeader), body, time.Now(), time.Minute))
+		receiver.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+
+	return receiver
+}
+
+func newTestDispatcher(dbtx db.DBTX) *webhookDispatcher {
+	return &webhookDispatcher{
+		dbQueries:   db.New(dbtx),
+		logger:      newTestLogger(),
+		client:      &http.Client{Timeout: time.Second},
+		interval:    time.Second,
+		backoff:     time.Minute,
+		maxAttempts: 3,
+	}
+}
+
+func TestWebhookDispatcherDeliver(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		attempts       int32
+		wantQuery      string
+		wantStatus     string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:      "2xx answer delivers",
+			status:    http.StatusNoContent,
+			wantQuery: "MarkWebhookDeliveryDelivered",
+		},
+		{
+			name:           "first failure retries after the backoff",
+			status:         http.StatusInternalServerError,
+			wantQuery:      "MarkWebhookDeliveryFailed",
+			wantStatus:     models.WebhookDeliveryPending,
+			wantRetryAfter: time.Minute,
+		},
+		{
+			name:           "second failure retries after twice the backoff",
+			status:         http.StatusInternalServerError,
+			attempts:       1,
+			wantQuery:      "MarkWebhookDeliveryFailed",
+			wantStatus:     models.WebhookDeliveryPending,
+			wantRetryAfter: 2 * time.Minute,
+		},
+		{
+			name:           "last attempt is dead",
+			status:         http.StatusInternalServerError,
+			attempts:       2,
+			wantQuery:      "MarkWebhookDeliveryFailed",
+			wantStatus:     models.WebhookDeliveryDead,
+			wantRetryAfter: 4 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := newTestReceiver(t, tt.status)
+			recorder := &recordingDB{}
+			d := newTestDispatcher(recorder)
+
+			before := time.Now().UTC()
+
+			d.deliver(context.Background(), &db.ClaimWebhookDeliveriesRow{
+				ID:        12,
+				Attempts:  tt.attempts,
+				Url:       receiver.URL,
+				Secret:    testWebhookSecret,
+				EventID:   3,
+				EventType: models.WebhookEventInvoiceGenerated,
+				Payload:   json.RawMessage(`{"id":7}`),
+			})
+
+			require.Equal(t, []string{models.WebhookEventInvoiceGenerated}, receiver.eventTypes)
+			require.Equal(t, []error{nil}, receiver.signatures)
+			require.Len(t, recorder.execs, 1)
+
+			exec := recorder.execs[0]
+			require.Equal(t, tt.wantQuery, exec.name)
+			require.Equal(t, int64(12), exec.args[0])
+
+			if tt.wantStatus == "" {
+				require.Equal(t, sql.NullInt32{Valid: true, Int32: int32(tt.status)}, exec.args[1])
+				return
+			}
+
+			require.Equal(t, tt.wantStatus, exec.args[1])
+			require.WithinRange(t, exec.args[2].(time.Time), before.Add(tt.wantRetryAfter),
+				time.Now().UTC().Add(tt.wantRetryAfter))
+			require.Equal(t, sql.NullInt32{Valid: true, Int32: int32(tt.status)}, exec.args[3])
+			require.Equal(t, "the receiver answered 500 Internal Server Error", exec.args[4].(sql.NullString).String)
+		})
+	}
+}
+
+func TestWebhookRetryBackoff(t *testing.T) {
+	d := newTestDispatcher(&recordingDB{})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 3, want: 4 * time.Minute},
+		{attempts: 9, want: 256 * time.Minute},
+		{attempts: 10, want: maxWebhookRetryBackoff},
+		{attempts: 1000, want: maxWebhookRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, d.retryBackoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
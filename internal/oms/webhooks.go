@@ -0,0 +1,208 @@
+package oms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	webhookSecretBytes      = 32
+	maxWebhookDeliveryRows  = 500
+	webhookDeliveryNotFound = "webhook delivery not found"
+)
+
+var errWebhookDeliveryNotDead = newAPIError(http.StatusConflict, models.ProblemInvalidState,
+	"only dead deliveries can be retried")
+
+// createWebhookSubscription stores a subscription, with a random secret when the
+// request has none. The secret is returned this once.
+func createWebhookSubscription(ctx context.Context, dbQueries *db.Queries,
+	req *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	if err := newValidationError(req.Validate()); err != nil {
+		return nil, err
+	}
+
+	if req.Secret == "" {
+		secret := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, errors.Wrap(err, "cannot generate the webhook secret")
+		}
+
+		req.Secret = hex.EncodeToString(secret)
+	}
+
+	subscription, err := dbQueries.CreateWebhookSubscription(ctx, req.ToCreateWebhookSubscription())
+	if err != nil {
+		return nil, err
+	}
+
+	created := models.NewWebhookSubscriptionFromDB(&subscription)
+	created.Secret = subscription.Secret
+
+	return created, nil
+}
+
+// updateWebhookSubscription changes the URL and event types of a subscription, its
+// secret stays the same.
+func updateWebhookSubscription(ctx context.Context, dbQueries *db.Queries, id int32,
+	req *models.WebhookSubscription, match *ifMatch) (*models.WebhookSubscription, error) {
+	req.Secret = ""
+	if err := newValidationError(req.Validate()); err != nil {
+		return nil, err
+	}
+
+	var subscription db.OmsWebhookSubscription
+
+	err := dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetWebhookSubscriptionForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(existing.Version); err != nil {
+			return err
+		}
+
+		if err := q.UpdateWebhookSubscription(ctx, req.ToUpdateWebhookSubscription(id)); err != nil {
+			return err
+		}
+
+		subscription, err = q.GetWebhookSubscription(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewWebhookSubscriptionFromDB(&subscription), nil
+}
+
+// deleteWebhookSubscription removes a subscription together with its delivery log.
+func deleteWebhookSubscription(ctx context.Context, dbQueries *db.Queries, id int32, match *ifMatch) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetWebhookSubscriptionForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := match.check(existing.Version); err != nil {
+			return err
+		}
+
+		_, err = q.DeleteWebhookSubscription(ctx, id)
+
+		return err
+	})
+}
+
+// listWebhookDeliveries returns the newest deliveries first, of one subscription
+// when subscriptionID is set and in one status when status is set.
+func listWebhookDeliveries(ctx context.Context, dbQueries *db.Queries, subscriptionID int32,
+	status string) (*models.List[models.WebhookDelivery], error) {
+	if status != "" && status != models.WebhookDeliveryPending && status != models.WebhookDeliveryDelivered &&
+		status != models.WebhookDeliveryDead {
+		return nil, newValidationError([]models.FieldError{{
+			Field:   "Status",
+			Message: "must be pending, delivered or dead",
+		}})
+	}
+
+	deliveries, err := dbQueries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscriptionID,
+		Status:         status,
+		MaxRows:        maxWebhookDeliveryRows,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.List[models.WebhookDelivery]{Items: make([]*models.WebhookDelivery, len(deliveries))}
+	for i := range deliveries {
+		list.Items[i] = models.NewWebhookDeliveryFromDB(&deliveries[i])
+	}
+
+	return list, nil
+}
+
+// retryWebhookDelivery sends a dead delivery again with a fresh set of attempts.
+func retryWebhookDelivery(ctx context.Context, dbQueries *db.Queries, id int64) error {
+	return dbQueries.ExecTx(ctx, func(q *db.Queries) error {
+		retried, err := q.RetryWebhookDelivery(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if retried > 0 {
+			return nil
+		}
+
+		if _, err := q.GetWebhookDeliveryStatus(ctx, id); err != nil {
+			return err
+		}
+
+		return errWebhookDeliveryNotDead
+	})
+}
+
+// emitWebhookEvent records an event with the wire form of its record and a
+// delivery for every subscription to its type. Call it in the transaction of the
+// change so the event is sent if and only if the change is committed.
+func emitWebhookEvent(ctx context.Context, dbQueries *db.Queries, eventType string, record interface{}) error {
+	payload, err := json.Marshal(v1.Encode(record))
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode the %s event", eventType)
+	}
+
+	_, err = dbQueries.EmitWebhookEvent(ctx, db.EmitWebhookEventParams{EventType: eventType, Payload: payload})
+
+	return errors.Wrapf(err, "cannot record the %s event", eventType)
+}
+
+// emitCampaignEvent sends the campaign as it is now, deleted or not.
+func emitCampaignEvent(ctx context.Context, dbQueries *db.Queries, eventType string, id int32) error {
+	campaign, err := dbQueries.GetCampaignWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return emitWebhookEvent(ctx, dbQueries, eventType, models.NewCampaignFromDB(&campaign))
+}
+
+// emitLineItemEvent sends the line item as it is now, deleted or not.
+func emitLineItemEvent(ctx context.Context, dbQueries *db.Queries, eventType string, id int32) error {
+	campaignLine, err := dbQueries.GetCampaignLineWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	lineItem, err := models.NewCampaignLineItemFromDB(&campaignLine)
+	if err != nil {
+		return err
+	}
+
+	return emitWebhookEvent(ctx, dbQueries, eventType, lineItem)
+}
+
+// emitInvoiceEvent sends the invoice as it is now, deleted or not.
+func emitInvoiceEvent(ctx context.Context, dbQueries *db.Queries, eventType string, id int32) error {
+	invoice, err := dbQueries.GetInvoiceWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	invoiceModel, err := models.NewInvoiceFromDB(invoice)
+	if err != nil {
+		return err
+	}
+
+	return emitWebhookEvent(ctx, dbQueries, eventType, invoiceModel)
+}
@@ -0,0 +1,209 @@
+package oms
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chrisrob11/oms/internal/oms/db"
+	"github.com/chrisrob11/oms/internal/oms/models"
+	"github.com/gin-gonic/gin"
+)
+
+// webhooksController manages the webhook subscriptions and their delivery log.
+type webhooksController struct {
+	dbQueries *db.Queries
+	logger    *slog.Logger
+}
+
+func newWebhooksController(logger *slog.Logger, dbQueries *db.Queries) *webhooksController {
+	return &webhooksController{dbQueries: dbQueries, logger: logger}
+}
+
+// register adds the webhook subscription and delivery routes.
+func (s *webhooksController) register(router gin.IRoutes) {
+	router.POST("/webhooks", s.createSubscription)
+	router.GET("/webhooks", s.listSubscriptions)
+	router.GET("/webhooks/:id", s.getSubscription)
+	router.PUT("/webhooks/:id", s.updateSubscription)
+	router.DELETE("/webhooks/:id", s.deleteSubscription)
+	router.GET("/webhooks/:id/deliveries", s.listSubscriptionDeliveries)
+	router.GET("/webhookDeliveries", s.listDeliveries)
+	router.POST("/webhookDeliveries/:id/retry", s.retryDelivery)
+}
+
+func (s *webhooksController) createSubscription(c *gin.Context) {
+	var req models.WebhookSubscription
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	subscription, err := createWebhookSubscription(c.Request.Context(), s.dbQueries, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, subscription)
+}
+
+func (s *webhooksController) getSubscription(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	subscription, err := s.dbQueries.GetWebhookSubscription(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "webhook subscription not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, int(subscription.Version))
+	respond(c, http.StatusOK, models.NewWebhookSubscriptionFromDB(&subscription))
+}
+
+func (s *webhooksController) listSubscriptions(c *gin.Context) {
+	subscriptions, err := s.dbQueries.ListWebhookSubscriptions(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	subscriptionsResp := &models.List[models.WebhookSubscription]{
+		Items: make([]*models.WebhookSubscription, len(subscriptions)),
+	}
+	for i := range subscriptions {
+		subscriptionsResp.Items[i] = models.NewWebhookSubscriptionFromDB(&subscriptions[i])
+	}
+
+	respond(c, http.StatusOK, subscriptionsResp)
+}
+
+func (s *webhooksController) updateSubscription(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
+	}
+
+	var req models.WebhookSubscription
+	if err := bindJSON(c, &req); err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, err.Error())
+		return
+	}
+
+	subscription, err := updateWebhookSubscription(c.Request.Context(), s.dbQueries, id, &req, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "webhook subscription not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, subscription.Version)
+	respond(c, http.StatusOK, subscription)
+}
+
+// deleteSubscription removes a subscription together with its delivery log.
+func (s *webhooksController) deleteSubscription(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	match, hasError := requireIfMatch(c)
+	if hasError {
+		return
+	}
+
+	err = deleteWebhookSubscription(c.Request.Context(), s.dbQueries, id, match)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "webhook subscription not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (s *webhooksController) listSubscriptionDeliveries(c *gin.Context) {
+	id, err := toInt32(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	_, err = s.dbQueries.GetWebhookSubscription(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, "webhook subscription not found")
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	s.respondDeliveries(c, id)
+}
+
+// listDeliveries lists the deliveries of every subscription, ?status=dead lists
+// the dead letters.
+func (s *webhooksController) listDeliveries(c *gin.Context) {
+	s.respondDeliveries(c, 0)
+}
+
+func (s *webhooksController) respondDeliveries(c *gin.Context, subscriptionID int32) {
+	deliveries, err := listWebhookDeliveries(c.Request.Context(), s.dbQueries, subscriptionID, c.Query("status"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, deliveries)
+}
+
+// retryDelivery sends a dead delivery again.
+func (s *webhooksController) retryDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, models.ProblemInvalidRequest, "invalid ID")
+		return
+	}
+
+	err = retryWebhookDelivery(c.Request.Context(), s.dbQueries, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondProblem(c, http.StatusNotFound, models.ProblemNotFound, webhookDeliveryNotFound)
+		return
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, int(id))
+}
@@ -0,0 +1,45 @@
+package oms
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chrisrob11/oms/internal/oms/models"
+	v1 "github.com/chrisrob11/oms/internal/oms/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptionChangesRequireIfMatch(t *testing.T) {
+	r := newOpenAPITestEngine(t)
+
+	tests := []struct {
+		method string
+		body   string
+	}{
+		{method: http.MethodPut, body: `{"url": "https://example.com/hook", "event_types": ["campaign.created"]}`},
+		{method: http.MethodDelete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			// The precondition is checked before the database is used, so there is none.
+			req := httptest.NewRequest(tt.method, v1.BasePath+"/webhooks/3", strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", gin.MIMEJSON)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+			var problem v1.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.Equal(t, models.ProblemPreconditionRequired, problem.Code)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+-- +migrate Up
+
+-- Every change to a row bumps its version, which is served as the ETag and checked
+-- against If-Match so concurrent edits cannot silently overwrite each other.
+ALTER TABLE oms.campaigns ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE oms.campaign_line_items ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE oms.invoices ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE oms.sales_reps ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- The trigger also keeps updated_at current, the update queries never set it themselves.
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION oms.bump_row_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    NEW.updated_at := CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS bump_campaign_version ON oms.campaigns;
+CREATE TRIGGER bump_campaign_version BEFORE UPDATE ON oms.campaigns
+FOR EACH ROW EXECUTE FUNCTION oms.bump_row_version();
+
+DROP TRIGGER IF EXISTS bump_campaign_line_item_version ON oms.campaign_line_items;
+CREATE TRIGGER bump_campaign_line_item_version BEFORE UPDATE ON oms.campaign_line_items
+FOR EACH ROW EXECUTE FUNCTION oms.bump_row_version();
+
+DROP TRIGGER IF EXISTS bump_invoice_version ON oms.invoices;
+CREATE TRIGGER bump_invoice_version BEFORE UPDATE ON oms.invoices
+FOR EACH ROW EXECUTE FUNCTION oms.bump_row_version();
+
+DROP TRIGGER IF EXISTS bump_sales_rep_version ON oms.sales_reps;
+CREATE TRIGGER bump_sales_rep_version BEFORE UPDATE ON oms.sales_reps
+FOR EACH ROW EXECUTE FUNCTION oms.bump_row_version();
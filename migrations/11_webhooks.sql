@@ -0,0 +1,41 @@
+-- +migrate Up
+
+-- Webhook subscriptions receive the events of the types they list at their URL,
+-- signed with their secret.
+CREATE TABLE IF NOT EXISTS oms.webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The outbox: a change records its event and a delivery for every subscription to
+-- it in its own transaction, the dispatcher sends the deliveries once it committed.
+CREATE TABLE IF NOT EXISTS oms.webhook_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A delivery is pending until the receiver accepts it, and dead once it ran out of
+-- attempts. Dead deliveries stay until they are retried.
+CREATE TABLE IF NOT EXISTS oms.webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES oms.webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES oms.webhook_events(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    response_status INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_due ON oms.webhook_deliveries(next_attempt_at)
+WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_subscription_id ON oms.webhook_deliveries(subscription_id);
@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- Webhook subscriptions are versioned like the other records, see 10_row_versions.sql.
+ALTER TABLE oms.webhook_subscriptions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+DROP TRIGGER IF EXISTS bump_webhook_subscription_version ON oms.webhook_subscriptions;
+CREATE TRIGGER bump_webhook_subscription_version BEFORE UPDATE ON oms.webhook_subscriptions
+FOR EACH ROW EXECUTE FUNCTION oms.bump_row_version();
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+-- Rows are moved to the trash by setting deleted_at, they are permanently
+-- removed by the purge job once they are older than the configured retention.
+ALTER TABLE oms.campaigns ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE oms.campaign_line_items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE oms.invoices ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
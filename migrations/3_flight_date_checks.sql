@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- The constraints are added as NOT VALID so rows that already break them do not
+-- block the migration, new and updated rows are still checked. Existing rows can
+-- be found with `omsclient validate`, once fixed the constraints can be validated
+-- with ALTER TABLE ... VALIDATE CONSTRAINT.
+ALTER TABLE oms.campaigns ADD CONSTRAINT campaigns_flight_dates_check
+    CHECK (started_at IS NULL OR ended_at IS NULL OR ended_at >= started_at) NOT VALID;
+
+ALTER TABLE oms.campaign_line_items ADD CONSTRAINT campaign_line_items_flight_dates_check
+    CHECK (started_at IS NULL OR ended_at IS NULL OR ended_at >= started_at) NOT VALID;
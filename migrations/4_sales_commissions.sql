@@ -0,0 +1,34 @@
+-- +migrate Up
+
+-- Commission plans pay a percentage of either the billed (issued) or the
+-- collected revenue of the invoices of the campaigns a sales rep owns.
+CREATE TABLE IF NOT EXISTS oms.commission_plans (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    basis VARCHAR(16) NOT NULL CHECK (basis IN ('billed', 'collected')),
+    rate_percent NUMERIC NOT NULL CHECK (rate_percent >= 0 AND rate_percent <= 100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS oms.sales_reps (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    commission_plan_id INTEGER REFERENCES oms.commission_plans(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Owners of a campaign share its revenue by split_percent.
+CREATE TABLE IF NOT EXISTS oms.campaign_owners (
+    campaign_id INTEGER NOT NULL REFERENCES oms.campaigns(id) ON DELETE CASCADE,
+    sales_rep_id INTEGER NOT NULL REFERENCES oms.sales_reps(id),
+    split_percent NUMERIC NOT NULL CHECK (split_percent > 0 AND split_percent <= 100),
+    PRIMARY KEY (campaign_id, sales_rep_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_owner_sales_rep_id ON oms.campaign_owners(sales_rep_id);
+
+-- Collected revenue is recognised when the payment of an invoice is recorded.
+ALTER TABLE oms.invoices ADD COLUMN IF NOT EXISTS collected_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,6 @@
+-- +migrate Up
+
+-- Listing the line items of a campaign pages through them by id, the index
+-- covers the id so the pages are read in order straight from the index.
+DROP INDEX IF EXISTS oms.idx_line_item_campaign_id;
+CREATE INDEX IF NOT EXISTS idx_line_item_campaign_id ON oms.campaign_line_items(campaign_id, id);
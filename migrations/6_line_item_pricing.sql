@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- Line items priced per unit derive booked and actual from their units, flat fee
+-- line items keep the amounts they are given. Existing line items become flat fee.
+ALTER TABLE oms.campaign_line_items
+    ADD COLUMN IF NOT EXISTS pricing_model VARCHAR(8) NOT NULL DEFAULT 'flat'
+        CHECK (pricing_model IN ('cpm', 'cpc', 'cpa', 'flat')),
+    ADD COLUMN IF NOT EXISTS unit_rate NUMERIC NOT NULL DEFAULT 0 CHECK (unit_rate >= 0),
+    ADD COLUMN IF NOT EXISTS booked_units BIGINT NOT NULL DEFAULT 0 CHECK (booked_units >= 0),
+    ADD COLUMN IF NOT EXISTS delivered_units BIGINT NOT NULL DEFAULT 0 CHECK (delivered_units >= 0);
@@ -0,0 +1,14 @@
+-- +migrate Up
+
+-- Daily delivery reported for a line item, a day is ingested again by replacing its row.
+CREATE TABLE IF NOT EXISTS oms.line_item_delivery (
+    line_item_id INTEGER NOT NULL REFERENCES oms.campaign_line_items(id) ON DELETE CASCADE,
+    delivery_date DATE NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0 CHECK (impressions >= 0),
+    clicks BIGINT NOT NULL DEFAULT 0 CHECK (clicks >= 0),
+    conversions BIGINT NOT NULL DEFAULT 0 CHECK (conversions >= 0),
+    spend NUMERIC NOT NULL DEFAULT 0 CHECK (spend >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (line_item_id, delivery_date)
+);
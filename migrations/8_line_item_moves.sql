@@ -0,0 +1,30 @@
+-- +migrate Up
+
+-- Line items billed by an invoice, recorded when the invoice is generated.
+CREATE TABLE IF NOT EXISTS oms.invoice_line_items (
+    invoice_id INTEGER NOT NULL REFERENCES oms.invoices(id) ON DELETE CASCADE,
+    line_item_id INTEGER NOT NULL REFERENCES oms.campaign_line_items(id) ON DELETE CASCADE,
+    PRIMARY KEY (invoice_id, line_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_line_item_line_item_id ON oms.invoice_line_items(line_item_id);
+
+-- Invoices issued before the link existed billed every line item their campaign had at the time.
+INSERT INTO oms.invoice_line_items (invoice_id, line_item_id)
+SELECT i.id, l.id
+FROM oms.invoices i
+JOIN oms.campaign_line_items l ON l.campaign_id = i.campaign_id
+WHERE i.issued_at IS NOT NULL AND (l.created_at IS NULL OR l.created_at <= i.issued_at)
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS oms.line_item_history (
+    id SERIAL PRIMARY KEY,
+    line_item_id INTEGER NOT NULL REFERENCES oms.campaign_line_items(id) ON DELETE CASCADE,
+    event VARCHAR(32) NOT NULL,
+    from_campaign_id INTEGER,
+    to_campaign_id INTEGER,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_line_item_history_line_item_id ON oms.line_item_history(line_item_id);
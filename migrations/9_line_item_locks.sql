@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- Line items are locked once an issued invoice bills them and must be unlocked,
+-- with an audited reason, before they can change again.
+ALTER TABLE oms.campaign_line_items ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE oms.campaign_line_items l
+SET locked_at = i.issued_at
+FROM oms.invoice_line_items il
+JOIN oms.invoices i ON i.id = il.invoice_id
+WHERE il.line_item_id = l.id AND i.issued_at IS NOT NULL AND i.deleted_at IS NULL AND l.locked_at IS NULL;
@@ -12,18 +12,45 @@ VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetCampaign :one
+SELECT * FROM oms.campaigns WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetCampaignWithDeleted :one
 SELECT * FROM oms.campaigns WHERE id = $1;
 
--- name: ListCampaigns :many
-SELECT * FROM oms.campaigns 
-WHERE archiving = false AND id > $1
-Order by id
-LIMIT $2;
+-- name: ListCampaignsByIDs :many
+SELECT * FROM oms.campaigns WHERE id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id;
 
 -- name: UpdateCampaign :exec
 UPDATE oms.campaigns
 SET name = $1, started_at = $2, ended_at = $3, archiving = $4
-WHERE id = $5;
+WHERE id = $5 AND deleted_at IS NULL;
+
+-- name: TouchCampaign :one
+UPDATE oms.campaigns SET updated_at = CURRENT_TIMESTAMP WHERE id = $1
+RETURNING version;
+
+-- name: SoftDeleteCampaign :exec
+UPDATE oms.campaigns SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreCampaign :exec
+UPDATE oms.campaigns SET deleted_at = NULL WHERE id = $1;
+
+-- name: PurgeCampaigns :many
+DELETE FROM oms.campaigns c
+WHERE c.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM oms.campaign_line_items li WHERE li.campaign_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM oms.invoices i WHERE i.campaign_id = c.id)
+RETURNING *;
+
+-- name: GetCampaignForUpdate :one
+SELECT * FROM oms.campaigns WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
 
--- name: DeleteCampaign :exec 
-DELETE FROM oms.campaigns WHERE id = $1;
\ No newline at end of file
+-- name: CountCampaignDependents :one
+SELECT
+    (SELECT COUNT(*) FROM oms.campaign_line_items li
+        WHERE li.campaign_id = $1 AND li.deleted_at IS NULL) AS line_item_count,
+    (SELECT COUNT(*) FROM oms.invoices i
+        WHERE i.campaign_id = $1 AND i.issued_at IS NULL AND i.deleted_at IS NULL) AS draft_invoice_count,
+    (SELECT COUNT(*) FROM oms.invoices i
+        WHERE i.campaign_id = $1 AND i.issued_at IS NOT NULL AND i.deleted_at IS NULL) AS issued_invoice_count;
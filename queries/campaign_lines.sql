@@ -1,28 +1,116 @@
 -- campaign_line.sql
 
 -- name: CreateCampaignLineWithID :one
-INSERT INTO oms.campaign_line_items (id, campaign_id, name, booked, actual, adjustments, started_at, ended_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO oms.campaign_line_items (id, campaign_id, name, booked, actual, adjustments, started_at, ended_at,
+    pricing_model, unit_rate, booked_units, delivered_units)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING id;
 
 -- name: CreateCampaignLine :one
-INSERT INTO oms.campaign_line_items (campaign_id, name, booked, actual, adjustments, started_at, ended_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO oms.campaign_line_items (campaign_id, name, booked, actual, adjustments, started_at, ended_at,
+    pricing_model, unit_rate, booked_units, delivered_units)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING id;
 
 -- name: GetCampaignLine :one
+SELECT * FROM oms.campaign_line_items WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetCampaignLineForUpdate :one
+SELECT * FROM oms.campaign_line_items WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: GetCampaignLineWithDeleted :one
 SELECT * FROM oms.campaign_line_items WHERE id = $1;
 
 -- name: UpdateCampaignLine :exec
 UPDATE oms.campaign_line_items
-SET name = $2, booked = $3, actual = $4, adjustments = $5, started_at = $6, ended_at = $7
-WHERE id = $1;
+SET name = $2, booked = $3, actual = $4, adjustments = $5, started_at = $6, ended_at = $7,
+    pricing_model = $8, unit_rate = $9, booked_units = $10, delivered_units = $11
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteCampaignLine :exec
+UPDATE oms.campaign_line_items SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreCampaignLine :exec
+UPDATE oms.campaign_line_items SET deleted_at = NULL WHERE id = $1;
+
+-- name: PurgeCampaignLines :many
+DELETE FROM oms.campaign_line_items li
+WHERE li.deleted_at < $1
+    AND NOT EXISTS (
+        SELECT 1 FROM oms.invoice_line_items il
+        JOIN oms.invoices i ON i.id = il.invoice_id
+        WHERE il.line_item_id = li.id AND i.issued_at IS NOT NULL
+    )
+RETURNING *;
+
+-- name: ListCampaignLinesForCampaign :many
+SELECT * FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL
+Order by id;
+
+-- name: ListCampaignLinesForCampaigns :many
+SELECT * FROM oms.campaign_line_items
+WHERE id IN (
+    SELECT kept.id FROM unnest(sqlc.arg(campaign_ids)::int[]) AS campaign(id)
+    CROSS JOIN LATERAL (
+        SELECT id FROM oms.campaign_line_items
+        WHERE campaign_id = campaign.id AND deleted_at IS NULL
+        ORDER BY id LIMIT sqlc.arg(max_per_campaign)::integer
+    ) AS kept
+)
+ORDER BY campaign_id, id;
+
+-- name: GetCampaignLineTotals :one
+SELECT COUNT(*) AS line_item_count,
+    COALESCE(SUM(booked), 0)::numeric AS total_booked,
+    COALESCE(SUM(actual), 0)::numeric AS total_actual,
+    COALESCE(SUM(adjustments), 0)::numeric AS total_adjustments
+FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteCampaignLinesForCampaign :exec
+UPDATE oms.campaign_line_items SET deleted_at = $2 WHERE campaign_id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreCampaignLinesForCampaign :many
+UPDATE oms.campaign_line_items SET deleted_at = NULL WHERE campaign_id = $1 AND deleted_at = $2
+RETURNING id;
+
+-- name: GetCampaignLineFlightBounds :one
+SELECT LEAST(MIN(started_at), MIN(ended_at))::timestamptz AS earliest,
+    GREATEST(MAX(started_at), MAX(ended_at))::timestamptz AS latest
+FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL;
+
+-- name: MoveCampaignLine :exec
+UPDATE oms.campaign_line_items
+SET campaign_id = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: CreateLineItemHistory :exec
+INSERT INTO oms.line_item_history (line_item_id, event, from_campaign_id, to_campaign_id, reason)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListLineItemHistory :many
+SELECT * FROM oms.line_item_history
+WHERE line_item_id = $1
+Order by id;
+
+-- name: LockCampaignLinesForInvoice :many
+SELECT id FROM oms.campaign_line_items
+WHERE campaign_id = $1 AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE;
+
+-- name: LockInvoiceLineItems :exec
+UPDATE oms.campaign_line_items
+SET locked_at = $2
+WHERE id IN (SELECT line_item_id FROM oms.invoice_line_items WHERE invoice_id = $1) AND locked_at IS NULL;
 
--- name: DeleteCampaignLine :exec
-DELETE FROM oms.campaign_line_items WHERE id = $1;
+-- name: RecordInvoiceLineItemsLocked :exec
+INSERT INTO oms.line_item_history (line_item_id, event, reason)
+SELECT line_item_id, 'locked', 'billed by invoice ' || sqlc.arg(invoice_id)::integer
+FROM oms.invoice_line_items
+WHERE invoice_id = sqlc.arg(invoice_id);
 
--- name: ListCampaignLineItems :many
-SELECT * FROM oms.campaign_line_items 
-WHERE id > $1
-Order by id
-LIMIT $2;
\ No newline at end of file
+-- name: UnlockCampaignLine :exec
+UPDATE oms.campaign_line_items SET locked_at = NULL WHERE id = $1;
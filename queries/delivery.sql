@@ -0,0 +1,26 @@
+-- delivery.sql
+
+-- name: UpsertLineItemDelivery :exec
+INSERT INTO oms.line_item_delivery (line_item_id, delivery_date, impressions, clicks, conversions, spend)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (line_item_id, delivery_date) DO UPDATE
+SET impressions = EXCLUDED.impressions, clicks = EXCLUDED.clicks, conversions = EXCLUDED.conversions,
+    spend = EXCLUDED.spend, updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListLineItemDelivery :many
+SELECT * FROM oms.line_item_delivery
+WHERE line_item_id = $1
+Order by delivery_date;
+
+-- name: GetLineItemDeliveryTotals :one
+SELECT COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::numeric AS spend
+FROM oms.line_item_delivery
+WHERE line_item_id = $1;
+
+-- name: UpdateCampaignLineDelivery :exec
+UPDATE oms.campaign_line_items
+SET delivered_units = $2, actual = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -6,18 +6,72 @@ VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING id;
 
 -- name: GetInvoice :one
-SELECT * FROM oms.invoices WHERE id = $1;
+SELECT * FROM oms.invoices WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetInvoiceForUpdate :one
+SELECT * FROM oms.invoices WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
 
--- name: ListInvoices :many
-SELECT * FROM oms.invoices 
-WHERE id > $1
-Order by id
-LIMIT $2;
+-- name: GetInvoiceWithDeleted :one
+SELECT * FROM oms.invoices WHERE id = $1;
 
 -- name: AdjustInvoice :exec
 UPDATE oms.invoices
 SET total_adjustments = $2
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateInvoice :exec
+UPDATE oms.invoices
+SET total_adjustments = $2, started_at = $3, ended_at = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteInvoice :exec
+UPDATE oms.invoices SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreInvoice :exec
+UPDATE oms.invoices SET deleted_at = NULL WHERE id = $1;
+
+-- name: PurgeInvoices :many
+DELETE FROM oms.invoices WHERE deleted_at < $1 AND issued_at IS NULL
+RETURNING *;
+
+-- name: GetCampaignInvoicedTotal :one
+SELECT COALESCE((
+    SELECT COALESCE(total_actual_amount, 0) + COALESCE(total_adjustments, 0)
+    FROM oms.invoices
+    WHERE campaign_id = $1 AND issued_at IS NOT NULL AND deleted_at IS NULL
+    ORDER BY issued_at DESC, id DESC
+    LIMIT 1
+), 0)::numeric AS invoiced_total;
+
+-- name: ListInvoicesForCampaigns :many
+SELECT * FROM oms.invoices
+WHERE id IN (
+    SELECT kept.id FROM unnest(sqlc.arg(campaign_ids)::int[]) AS campaign(id)
+    CROSS JOIN LATERAL (
+        SELECT id FROM oms.invoices
+        WHERE campaign_id = campaign.id AND deleted_at IS NULL
+        ORDER BY id LIMIT sqlc.arg(max_per_campaign)::integer
+    ) AS kept
+)
+ORDER BY campaign_id, id;
+
+-- name: SoftDeleteDraftInvoicesForCampaign :exec
+UPDATE oms.invoices SET deleted_at = $2 WHERE campaign_id = $1 AND issued_at IS NULL AND deleted_at IS NULL;
+
+-- name: RestoreInvoicesForCampaign :many
+UPDATE oms.invoices SET deleted_at = NULL WHERE campaign_id = $1 AND deleted_at = $2
+RETURNING id;
+
+-- name: CollectInvoice :exec
+UPDATE oms.invoices
+SET collected_at = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: LinkInvoiceLineItems :exec
+INSERT INTO oms.invoice_line_items (invoice_id, line_item_id)
+SELECT sqlc.arg(invoice_id)::integer, unnest(sqlc.arg(line_item_ids)::int[]);
 
--- name: DeleteInvoice :exec
-DELETE FROM oms.invoices WHERE id = $1;
\ No newline at end of file
+-- name: CountIssuedInvoicesForLineItem :one
+SELECT COUNT(*) FROM oms.invoice_line_items il
+JOIN oms.invoices i ON i.id = il.invoice_id
+WHERE il.line_item_id = $1 AND i.issued_at IS NOT NULL AND i.deleted_at IS NULL;
@@ -0,0 +1,24 @@
+-- reports.sql
+
+-- name: ListLineItemVariance :many
+SELECT l.id AS line_item_id, l.name AS line_item_name, l.campaign_id, c.name AS campaign_name,
+    l.booked::numeric AS booked, COALESCE(l.actual, 0)::numeric AS actual
+FROM oms.campaign_line_items l
+JOIN oms.campaigns c ON c.id = l.campaign_id AND c.deleted_at IS NULL
+WHERE l.deleted_at IS NULL
+    AND (CASE WHEN sqlc.arg(percent)::boolean
+        THEN ABS(COALESCE(l.actual, 0) - l.booked) * 100 > sqlc.arg(threshold)::numeric * ABS(l.booked)
+        ELSE ABS(COALESCE(l.actual, 0) - l.booked) > sqlc.arg(threshold)::numeric END)
+Order by l.id;
+
+-- name: ListCampaignVariance :many
+SELECT c.id AS campaign_id, c.name AS campaign_name, COUNT(l.id) AS line_item_count,
+    SUM(l.booked)::numeric AS booked, SUM(COALESCE(l.actual, 0))::numeric AS actual
+FROM oms.campaigns c
+JOIN oms.campaign_line_items l ON l.campaign_id = c.id AND l.deleted_at IS NULL
+WHERE c.deleted_at IS NULL
+GROUP BY c.id, c.name
+HAVING (CASE WHEN sqlc.arg(percent)::boolean
+    THEN ABS(SUM(COALESCE(l.actual, 0)) - SUM(l.booked)) * 100 > sqlc.arg(threshold)::numeric * ABS(SUM(l.booked))
+    ELSE ABS(SUM(COALESCE(l.actual, 0)) - SUM(l.booked)) > sqlc.arg(threshold)::numeric END)
+Order by c.id;
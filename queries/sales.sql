@@ -0,0 +1,69 @@
+-- sales.sql
+
+-- name: CreateCommissionPlan :one
+INSERT INTO oms.commission_plans (name, basis, rate_percent)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetCommissionPlan :one
+SELECT * FROM oms.commission_plans WHERE id = $1;
+
+-- name: ListCommissionPlans :many
+SELECT * FROM oms.commission_plans
+Order by id;
+
+-- name: CreateSalesRep :one
+INSERT INTO oms.sales_reps (name, email, commission_plan_id)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetSalesRep :one
+SELECT * FROM oms.sales_reps WHERE id = $1;
+
+-- name: GetSalesRepForUpdate :one
+SELECT * FROM oms.sales_reps WHERE id = $1 FOR UPDATE;
+
+-- name: ListSalesReps :many
+SELECT * FROM oms.sales_reps
+Order by id;
+
+-- name: UpdateSalesRep :exec
+UPDATE oms.sales_reps
+SET name = $2, email = $3, commission_plan_id = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListCampaignOwners :many
+SELECT o.campaign_id, o.sales_rep_id, r.name AS sales_rep_name, o.split_percent
+FROM oms.campaign_owners o
+JOIN oms.sales_reps r ON r.id = o.sales_rep_id
+WHERE o.campaign_id = $1
+Order by o.sales_rep_id;
+
+-- name: DeleteCampaignOwners :exec
+DELETE FROM oms.campaign_owners WHERE campaign_id = $1;
+
+-- name: AddCampaignOwner :exec
+INSERT INTO oms.campaign_owners (campaign_id, sales_rep_id, split_percent)
+VALUES ($1, $2, $3);
+
+-- name: ListCommissionRevenue :many
+WITH billed AS (
+    SELECT id, campaign_id, issued_at, collected_at,
+        COALESCE(total_actual_amount, 0) + COALESCE(total_adjustments, 0) - COALESCE(LAG(
+            COALESCE(total_actual_amount, 0) + COALESCE(total_adjustments, 0)
+        ) OVER (PARTITION BY campaign_id ORDER BY issued_at, id), 0) AS amount
+    FROM oms.invoices
+    WHERE issued_at IS NOT NULL AND deleted_at IS NULL
+)
+SELECT r.id AS sales_rep_id, r.name AS sales_rep_name,
+    p.id AS commission_plan_id, p.name AS commission_plan_name, p.basis, p.rate_percent,
+    COUNT(DISTINCT b.id) AS invoice_count,
+    SUM(b.amount * o.split_percent / 100)::numeric AS revenue
+FROM oms.campaign_owners o
+JOIN oms.sales_reps r ON r.id = o.sales_rep_id
+JOIN oms.commission_plans p ON p.id = r.commission_plan_id
+JOIN billed b ON b.campaign_id = o.campaign_id
+WHERE (CASE p.basis WHEN 'collected' THEN b.collected_at ELSE b.issued_at END) >= sqlc.arg(period_start)::timestamptz
+    AND (CASE p.basis WHEN 'collected' THEN b.collected_at ELSE b.issued_at END) < sqlc.arg(period_end)::timestamptz
+GROUP BY r.id, r.name, p.id, p.name, p.basis, p.rate_percent
+Order by r.id;
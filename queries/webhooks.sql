@@ -0,0 +1,79 @@
+-- webhooks.sql
+
+-- name: CreateWebhookSubscription :one
+INSERT INTO oms.webhook_subscriptions (url, event_types, secret)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM oms.webhook_subscriptions WHERE id = $1;
+
+-- name: GetWebhookSubscriptionForUpdate :one
+SELECT * FROM oms.webhook_subscriptions WHERE id = $1 FOR UPDATE;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM oms.webhook_subscriptions
+Order by id;
+
+-- name: UpdateWebhookSubscription :exec
+UPDATE oms.webhook_subscriptions
+SET url = $2, event_types = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM oms.webhook_subscriptions WHERE id = $1;
+
+-- name: EmitWebhookEvent :execrows
+WITH event AS (
+    INSERT INTO oms.webhook_events (event_type, payload)
+    SELECT sqlc.arg(event_type)::varchar, sqlc.arg(payload)::jsonb
+    WHERE EXISTS (SELECT 1 FROM oms.webhook_subscriptions WHERE sqlc.arg(event_type) = ANY(event_types))
+    RETURNING id
+)
+INSERT INTO oms.webhook_deliveries (subscription_id, event_id)
+SELECT s.id, event.id FROM oms.webhook_subscriptions s, event
+WHERE sqlc.arg(event_type) = ANY(s.event_types);
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE oms.webhook_deliveries d
+SET next_attempt_at = sqlc.arg(lease_until)::timestamptz
+FROM oms.webhook_subscriptions s, oms.webhook_events e
+WHERE d.id IN (
+    SELECT id FROM oms.webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+    ORDER BY next_attempt_at, id
+    LIMIT sqlc.arg(batch_size)::int
+    FOR UPDATE SKIP LOCKED
+) AND s.id = d.subscription_id AND e.id = d.event_id
+RETURNING d.id, d.attempts, s.url, s.secret, e.id AS event_id, e.event_type, e.payload,
+    e.created_at AS event_created_at;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE oms.webhook_deliveries
+SET status = 'delivered', attempts = attempts + 1, response_status = $2, last_error = NULL,
+    delivered_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE oms.webhook_deliveries
+SET status = $2, attempts = attempts + 1, next_attempt_at = $3, response_status = $4, last_error = $5,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT d.id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts, d.next_attempt_at,
+    d.response_status, d.last_error, d.delivered_at, d.created_at, d.updated_at
+FROM oms.webhook_deliveries d
+JOIN oms.webhook_events e ON e.id = d.event_id
+WHERE (sqlc.arg(subscription_id)::int = 0 OR d.subscription_id = sqlc.arg(subscription_id))
+    AND (sqlc.arg(status)::varchar = '' OR d.status = sqlc.arg(status))
+ORDER BY d.id DESC
+LIMIT sqlc.arg(max_rows)::int;
+
+-- name: RetryWebhookDelivery :execrows
+UPDATE oms.webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'dead';
+
+-- name: GetWebhookDeliveryStatus :one
+SELECT status FROM oms.webhook_deliveries WHERE id = $1;